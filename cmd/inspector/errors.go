@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	httpclient "github.com/germangorelkin/http-client"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+)
+
+// usageError is an invalid command line or configuration.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string { return e.msg }
+
+func usageErrorf(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// exitCode maps err to the exit code of its class.
+func exitCode(err error) int {
+	var (
+		usage   *usageError
+		errResp *httpclient.ErrorResponse
+		urlErr  *url.Error
+	)
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &usage):
+		return exitUsage
+	case errors.Is(err, inspector.ErrReportFailed):
+		return exitReportFailed
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return exitTimeout
+	case errors.As(err, &errResp) && errResp.Response != nil:
+		switch status := errResp.Response.StatusCode; {
+		case status == http.StatusUnauthorized, status == http.StatusForbidden:
+			return exitAuth
+		case status == http.StatusNotFound:
+			return exitNotFound
+		case status == http.StatusTooManyRequests, status >= http.StatusInternalServerError:
+			return exitUnavailable
+		default:
+			return exitRejected
+		}
+	case errors.As(err, &urlErr): // transport errors of the HTTP client
+		if urlErr.Timeout() {
+			return exitTimeout
+		}
+		return exitUnavailable
+	}
+	return exitFailure
+}
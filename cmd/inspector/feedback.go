@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+)
+
+func feedback(c *cli, args []string) error {
+	fs := c.flags("feedback")
+	reportID := fs.Int("report", 0, "REALOGRAM report ID (required)")
+	imageID := fs.Int("image", 0, "image of the report the corrections refer to (default: the only image)")
+	scene := fs.String("scene", "", "scene uuid of the recognition (required)")
+	file := fs.String("corrections", "", `JSON array of corrections, "-" for stdin (required)`)
+	concurrency := fs.Int("concurrency", inspector.DefaultFeedbackConcurrency, "requests sent concurrently")
+	pos, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usageErrorf("feedback: unexpected arguments %v", pos)
+	}
+	if *reportID == 0 || *scene == "" || *file == "" {
+		return usageErrorf("feedback: -report, -scene and -corrections are required")
+	}
+	corrections, err := readCorrections(c, *file)
+	if err != nil {
+		return err
+	}
+	api, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	report, err := api.Report.GetReport(c.ctx, *reportID)
+	if err != nil {
+		return err
+	}
+	realograms, err := api.Report.ToRealogram(report.Json)
+	if err != nil {
+		return err
+	}
+	realogram, err := pickRealogram(realograms, *reportID, *imageID)
+	if err != nil {
+		return err
+	}
+
+	fb := inspector.NewRecognitionFeedback(*scene, realogram)
+	fb.Corrections = corrections
+	results, err := fb.Submit(c.ctx, api.Recognize, &inspector.FeedbackOptions{Concurrency: *concurrency})
+	if err != nil {
+		return err
+	}
+
+	type item struct {
+		inspector.FeedbackResult
+		Error string `json:"error,omitempty"`
+	}
+	items := make([]item, len(results))
+	t := &table{header: []string{"corrections", "sku_id", "recognition_error_id", "error"}}
+	for i, r := range results {
+		items[i] = item{FeedbackResult: r}
+		var sku any
+		if r.Request != nil {
+			sku = r.Request.SkuId
+		}
+		if r.Err != nil {
+			items[i].Error = r.Err.Error()
+		}
+		t.add(r.Corrections, sku, r.RecognitionErrorID, items[i].Error)
+	}
+	if err := c.render(items, t); err != nil {
+		return err
+	}
+	return results.Err()
+}
+
+func readCorrections(c *cli, path string) ([]inspector.Correction, error) {
+	var r io.Reader = c.stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	var corrections []inspector.Correction
+	if err := json.NewDecoder(r).Decode(&corrections); err != nil {
+		return nil, usageErrorf("feedback: failed to decode corrections: %v", err)
+	}
+	return corrections, nil
+}
+
+// pickRealogram returns the realogram of imageID, or the only one when imageID is 0.
+func pickRealogram(realograms []inspector.ReportRealogramJson, reportID, imageID int) (inspector.ReportRealogramJson, error) {
+	if imageID == 0 {
+		if len(realograms) != 1 {
+			return inspector.ReportRealogramJson{}, usageErrorf("feedback: report %d has %d images, set -image", reportID, len(realograms))
+		}
+		return realograms[0], nil
+	}
+	for _, r := range realograms {
+		if r.Image == imageID {
+			return r, nil
+		}
+	}
+	return inspector.ReportRealogramJson{}, fmt.Errorf("report %d has no image %d", reportID, imageID)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+)
+
+func imageUpload(c *cli, args []string) error {
+	fs := c.flags("image upload")
+	photos, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(photos) == 0 {
+		return usageErrorf("image upload: no files or URLs")
+	}
+	api, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	images := make([]inspector.Image, 0, len(photos))
+	for _, p := range photos {
+		var img inspector.Image
+		if strings.HasPrefix(p, "http://") || strings.HasPrefix(p, "https://") {
+			img, err = api.Image.UploadByURL(c.ctx, p)
+		} else {
+			img, err = uploadFile(c, api, p)
+		}
+		if err != nil {
+			return err
+		}
+		images = append(images, img)
+	}
+	return c.render(images, imageTable(images...))
+}
+
+func uploadFile(c *cli, api *inspector.Client, path string) (inspector.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return inspector.Image{}, err
+	}
+	defer f.Close()
+	return api.Image.Upload(c.ctx, f, filepath.Base(path))
+}
+
+func imageGet(c *cli, args []string) error {
+	fs := c.flags("image get")
+	pos, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := idArg(fs.Name(), pos)
+	if err != nil {
+		return err
+	}
+	api, err := c.client()
+	if err != nil {
+		return err
+	}
+	img, err := api.Image.GetImage(c.ctx, id)
+	if err != nil {
+		return err
+	}
+	return c.render(img, imageTable(*img))
+}
+
+// idArg parses the single positional ID argument of a command.
+func idArg(name string, pos []string) (int, error) {
+	if len(pos) != 1 {
+		return 0, usageErrorf("%s: expected one ID argument, got %d", name, len(pos))
+	}
+	id, err := strconv.Atoi(pos[0])
+	if err != nil || id <= 0 {
+		return 0, usageErrorf("%s: invalid ID %q", name, pos[0])
+	}
+	return id, nil
+}
+
+// intList parses a comma separated list of integers.
+func intList(s string) ([]int, error) {
+	var ids []int
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f == "" {
+			continue
+		}
+		id, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, usageErrorf("invalid ID %q", f)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,240 @@
+// Command inspector is a command-line client of the Inspector Cloud API.
+//
+// Usage:
+//
+//	inspector [global flags] <command> [subcommand] [flags] [args]
+//
+// Global flags may also follow the command. The API key and instance default
+// to the API_KEY and INSTANCE environment variables. Every command renders its
+// result with -output json, table (default) or csv. Run "inspector help" for
+// the list of commands and the exit codes.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+)
+
+// command is a subcommand of the CLI.
+type command struct {
+	name    string // one or two words, e.g. "report wait"
+	args    string // argument synopsis
+	summary string
+	run     func(c *cli, args []string) error
+}
+
+var commands = []command{
+	{"image upload", "<file|url>...", "upload photos from files or by URL", imageUpload},
+	{"image get", "<id>", "show an uploaded image", imageGet},
+	{"recognize", "-images <ids> [-reports <types>] [-wait]", "start recognition of uploaded images", recognize},
+	{"report get", "<id>", "show a report", reportGet},
+	{"report wait", "<id>", "poll a report until it is READY or ERROR", reportWait},
+	{"report export", "<id> [-xlsx <file>] [-catalog]", "export a READY report as rows or an XLSX workbook", reportExport},
+	{"sku list", "[-search <text>] [-all]", "list SKUs of the catalog", skuList},
+	{"sku find", "-ean <ean13> | -cid <cid> | -id <id>", "find SKUs by code", skuFind},
+	{"sku export", "[-format csv|jsonl|columnar] [-file <path>]", "export the SKU catalog", skuExport},
+	{"visit create", "[-shop <id>] [-agent <name>] [-started <time>]", "create a visit", visitCreate},
+	{"visit list", "[-shop <id>] [-agent <name>] [-after <time>] [-before <time>]", "list visits", visitList},
+	{"feedback", "-report <id> -scene <uuid> -corrections <file>", "send recognition corrections of a realogram", feedback},
+}
+
+// Exit codes
+const (
+	exitOK           = 0
+	exitFailure      = 1 // any other error
+	exitUsage        = 2 // invalid flags, arguments or configuration
+	exitAuth         = 3 // API key rejected: HTTP 401 or 403
+	exitNotFound     = 4 // HTTP 404
+	exitRejected     = 5 // request rejected: other HTTP 4xx
+	exitUnavailable  = 6 // network error, HTTP 429 or 5xx
+	exitTimeout      = 7 // timeout or interrupt
+	exitReportFailed = 8 // report ended with status ERROR
+)
+
+// cli holds the global flags and I/O of one invocation.
+type cli struct {
+	ctx    context.Context
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	instance string
+	apiKey   string
+	timeout  time.Duration
+	verbose  bool
+	output   string
+
+	cmd command // command being run
+	api *inspector.Client
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr, os.Getenv)
+	stop()
+	os.Exit(code)
+}
+
+// run executes the command line args and returns the exit code.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) int {
+	c := &cli{
+		ctx:      ctx,
+		stdin:    stdin,
+		stdout:   stdout,
+		stderr:   stderr,
+		instance: getenv("INSTANCE"),
+		apiKey:   getenv("API_KEY"),
+		timeout:  inspector.DefaultHTTPTimeout,
+		output:   outputTable,
+	}
+
+	fs := c.flags("inspector")
+	if err := fs.Parse(args); errors.Is(err, flag.ErrHelp) {
+		c.usage(c.stdout)
+		return exitOK
+	} else if err != nil {
+		return c.fail(usageErrorf("%v", err))
+	}
+	args = fs.Args()
+	if len(args) == 0 || args[0] == "help" {
+		c.usage(c.stdout)
+		return exitOK
+	}
+
+	cmd, rest, ok := findCommand(args)
+	if !ok {
+		return c.fail(usageErrorf("unknown command %q", strings.Join(args[:min(2, len(args))], " ")))
+	}
+	c.cmd = cmd
+	if err := cmd.run(c, rest); err != nil {
+		return c.fail(err)
+	}
+	return exitOK
+}
+
+// findCommand matches the longest command name at the start of args.
+func findCommand(args []string) (command, []string, bool) {
+	for _, n := range []int{2, 1} {
+		if len(args) < n {
+			continue
+		}
+		name := strings.Join(args[:n], " ")
+		for _, cmd := range commands {
+			if cmd.name == name {
+				return cmd, args[n:], true
+			}
+		}
+	}
+	return command{}, nil, false
+}
+
+// flags makes a flag set with the global flags bound to c.
+func (c *cli) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&c.instance, "instance", c.instance, "API instance URL (default: $INSTANCE)")
+	fs.StringVar(&c.apiKey, "api-key", c.apiKey, "API key (default: $API_KEY)")
+	fs.DurationVar(&c.timeout, "timeout", c.timeout, "HTTP request timeout")
+	fs.BoolVar(&c.verbose, "verbose", c.verbose, "dump HTTP requests and responses")
+	fs.StringVar(&c.output, "output", c.output, "output format: json, table or csv")
+	fs.StringVar(&c.output, "o", c.output, "shorthand for -output")
+	return fs
+}
+
+// parse parses flags and positional arguments in any order.
+func (c *cli) parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); errors.Is(err, flag.ErrHelp) {
+			c.commandUsage(fs)
+			return nil, err
+		} else if err != nil {
+			return nil, usageErrorf("%s: %v", fs.Name(), err)
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	switch c.output {
+	case outputJSON, outputTable, outputCSV:
+	default:
+		return nil, usageErrorf("unknown output format %q", c.output)
+	}
+	return positional, nil
+}
+
+// client makes the API client on first use.
+func (c *cli) client() (*inspector.Client, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	if c.instance == "" || c.apiKey == "" {
+		return nil, usageErrorf("API instance and key are required: set -instance and -api-key, or INSTANCE and API_KEY")
+	}
+	api, err := inspector.NewClient(inspector.ClientConf{
+		Instance: c.instance,
+		APIKey:   c.apiKey,
+		Verbose:  c.verbose,
+		Timeout:  c.timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.api = api
+	return api, nil
+}
+
+// fail prints err and returns its exit code.
+func (c *cli) fail(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK // usage already printed
+	}
+	fmt.Fprintf(c.stderr, "inspector: %v\n", err)
+	code := exitCode(err)
+	if code == exitUsage {
+		fmt.Fprintln(c.stderr, `Run "inspector help" for usage.`)
+	}
+	return code
+}
+
+// commandUsage prints the synopsis and flags of the running command.
+func (c *cli) commandUsage(fs *flag.FlagSet) {
+	fmt.Fprintf(c.stdout, "Usage: inspector %s %s\n\n%s.\n\nFlags:\n", c.cmd.name, c.cmd.args, c.cmd.summary)
+	fs.SetOutput(c.stdout)
+	fs.PrintDefaults()
+}
+
+func (c *cli) usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: inspector [global flags] <command> [flags] [args]")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "\nGlobal flags:")
+	fs := c.flags("inspector")
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+	fmt.Fprintln(w, `
+Exit codes:
+  0  success
+  1  other error
+  2  invalid flags, arguments or configuration
+  3  API key rejected (HTTP 401, 403)
+  4  not found (HTTP 404)
+  5  request rejected (other HTTP 4xx)
+  6  API unavailable (network error, HTTP 429, 5xx)
+  7  timeout or interrupt
+  8  report ended with status ERROR`)
+}
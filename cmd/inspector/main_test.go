@@ -0,0 +1,345 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	httpclient "github.com/germangorelkin/http-client"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+	"github.com/germangorelkin/go-inspector/inspector/inspectortest"
+)
+
+type result struct {
+	code   int
+	stdout string
+	stderr string
+}
+
+// exec runs the CLI against srv with the given global output format.
+func exec(srv *inspectortest.Server, stdin io.Reader, args ...string) result {
+	var stdout, stderr bytes.Buffer
+	env := map[string]string{"INSTANCE": srv.URL, "API_KEY": inspectortest.APIKey}
+	code := run(context.Background(), args, stdin, &stdout, &stderr, func(k string) string { return env[k] })
+	return result{code: code, stdout: stdout.String(), stderr: stderr.String()}
+}
+
+func decode(t *testing.T, r result, v any) {
+	t.Helper()
+	if !assert.Equal(t, exitOK, r.code, r.stderr) {
+		t.FailNow()
+	}
+	if err := json.Unmarshal([]byte(r.stdout), v); err != nil {
+		t.Fatalf("failed to decode %q: %v", r.stdout, err)
+	}
+}
+
+func ean(s string) *string { return &s }
+
+func TestRun_Usage(t *testing.T) {
+	srv := inspectortest.NewServer()
+	defer srv.Close()
+
+	t.Run("help", func(t *testing.T) {
+		r := exec(srv, nil, "help")
+		assert.Equal(t, exitOK, r.code)
+		assert.Contains(t, r.stdout, "report export")
+		assert.Contains(t, r.stdout, "-output")
+	})
+	t.Run("command help", func(t *testing.T) {
+		r := exec(srv, nil, "report", "wait", "-h")
+		assert.Equal(t, exitOK, r.code)
+		assert.Contains(t, r.stdout, "-wait-timeout")
+	})
+	t.Run("unknown command", func(t *testing.T) {
+		r := exec(srv, nil, "report", "delete", "1")
+		assert.Equal(t, exitUsage, r.code)
+		assert.Contains(t, r.stderr, `unknown command "report delete"`)
+	})
+	t.Run("bad output", func(t *testing.T) {
+		r := exec(srv, nil, "-o", "yaml", "sku", "list")
+		assert.Equal(t, exitUsage, r.code)
+	})
+	t.Run("missing argument", func(t *testing.T) {
+		assert.Equal(t, exitUsage, exec(srv, nil, "report", "get").code)
+		assert.Equal(t, exitUsage, exec(srv, nil, "recognize").code)
+		assert.Equal(t, exitUsage, exec(srv, nil, "sku", "find", "-ean", "1", "-cid", "2").code)
+		assert.Equal(t, exitUsage, exec(srv, nil, "visit", "list", "-after", "yesterday").code)
+	})
+	t.Run("no credentials", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		code := run(context.Background(), []string{"sku", "list"}, nil, &stdout, &stderr, func(string) string { return "" })
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr.String(), "-instance")
+	})
+}
+
+func TestRun_Image(t *testing.T) {
+	srv := inspectortest.NewServer()
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "shelf.jpg")
+	if err := os.WriteFile(path, []byte("jpeg"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var uploaded []inspector.Image
+	decode(t, exec(srv, nil, "-o", "json", "image", "upload", path, "https://example.com/shelf.jpg"), &uploaded)
+	assert.Len(t, uploaded, 2)
+	assert.Len(t, srv.Images(), 2)
+
+	var got inspector.Image
+	decode(t, exec(srv, nil, "image", "get", strconv.Itoa(uploaded[0].ID), "-o", "json"), &got)
+	assert.Equal(t, uploaded[0], got)
+
+	r := exec(srv, nil, "image", "get", strconv.Itoa(uploaded[1].ID))
+	assert.Equal(t, exitOK, r.code)
+	assert.True(t, strings.HasPrefix(r.stdout, "ID"), r.stdout)
+
+	assert.Equal(t, exitNotFound, exec(srv, nil, "image", "get", "999999").code)
+	assert.Equal(t, exitFailure, exec(srv, nil, "image", "upload", filepath.Join(t.TempDir(), "missing.jpg")).code)
+}
+
+func TestRun_Report(t *testing.T) {
+	srv := inspectortest.NewServer()
+	defer srv.Close()
+	srv.SetReadyAfter(0)
+	srv.SetReportData(inspector.ReportTypeFACING_COUNT, []inspector.ReportFacingCountJson{{SkuId: 7, Count: 3}})
+	srv.AddSKU(inspector.Sku{ID: 7, Name: "Cola 0.5", EAN13: ean("4600000000007")})
+	img, err := srv.Client().Image.UploadByURL(context.Background(), "https://example.com/shelf.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rec struct {
+		Recognition inspector.RecognizeResponse `json:"recognition"`
+		Reports     []inspector.Report          `json:"reports"`
+	}
+	decode(t, exec(srv, nil, "-o", "json", "recognize", "-images", strconv.Itoa(img.ID), "-wait", "-interval", "1ms"), &rec)
+	if !assert.Len(t, rec.Reports, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, inspector.ReportStatusREADY, rec.Reports[0].Status)
+	id := strconv.Itoa(rec.Reports[0].ID)
+
+	t.Run("get", func(t *testing.T) {
+		var report inspector.Report
+		decode(t, exec(srv, nil, "-o", "json", "report", "get", id), &report)
+		assert.Equal(t, rec.Reports[0].ID, report.ID)
+		assert.Equal(t, exitNotFound, exec(srv, nil, "report", "get", "999999").code)
+	})
+	t.Run("export csv", func(t *testing.T) {
+		r := exec(srv, nil, "-o", "csv", "report", "export", id, "-catalog")
+		assert.Equal(t, exitOK, r.code, r.stderr)
+		assert.Equal(t, "sku_id,count,sku_name,ean13\n7,3,Cola 0.5,4600000000007\n", r.stdout)
+	})
+	t.Run("export table", func(t *testing.T) {
+		r := exec(srv, nil, "report", "export", id)
+		assert.Equal(t, exitOK, r.code, r.stderr)
+		assert.Contains(t, r.stdout, "SKU_ID")
+	})
+	t.Run("export xlsx", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.xlsx")
+		var out struct {
+			File   string `json:"file"`
+			Sheets []struct {
+				Name string `json:"name"`
+				Rows int    `json:"rows"`
+			} `json:"sheets"`
+		}
+		decode(t, exec(srv, nil, "-o", "json", "report", "export", id, "-xlsx", path), &out)
+		assert.Equal(t, path, out.File)
+		if assert.Len(t, out.Sheets, 1) {
+			assert.Equal(t, 1, out.Sheets[0].Rows)
+		}
+		_, err := zip.OpenReader(path)
+		assert.NoError(t, err)
+	})
+	t.Run("report failed", func(t *testing.T) {
+		srv.SetReportError(inspector.ReportTypeFACING_COUNT, "no shelves")
+		defer srv.SetReportError(inspector.ReportTypeFACING_COUNT, "")
+		r := exec(srv, nil, "recognize", "-images", strconv.Itoa(img.ID), "-wait", "-interval", "1ms")
+		assert.Equal(t, exitReportFailed, r.code, r.stderr)
+
+		failed := srv.Recognitions()[len(srv.Recognitions())-1].Reports[inspector.ReportTypeFACING_COUNT]
+		assert.Equal(t, exitReportFailed, exec(srv, nil, "report", "export", strconv.Itoa(failed)).code)
+	})
+	t.Run("wait timeout", func(t *testing.T) {
+		srv.SetReadyAfter(1000)
+		defer srv.SetReadyAfter(0)
+		r := exec(srv, nil, "recognize", "-images", strconv.Itoa(img.ID), "-wait", "-interval", "1ms", "-wait-timeout", "20ms")
+		assert.Equal(t, exitTimeout, r.code, r.stderr)
+		assert.Contains(t, r.stderr, inspector.ReportStatusNOT_READY)
+	})
+}
+
+func TestRun_Sku(t *testing.T) {
+	srv := inspectortest.NewServer()
+	defer srv.Close()
+	srv.AddSKU(
+		inspector.Sku{ID: 1, Name: "Cola 0.5", EAN13: ean("4600000000001")},
+		inspector.Sku{ID: 2, Name: "Cola 1.0", EAN13: ean("4600000000002"), CID: "c-2"},
+		inspector.Sku{ID: 3, Name: "Water 0.5", EAN13: ean("4600000000003")},
+	)
+
+	var skus []inspector.Sku
+	decode(t, exec(srv, nil, "-o", "json", "sku", "list", "-all", "-limit", "2"), &skus)
+	assert.Len(t, skus, 3)
+
+	decode(t, exec(srv, nil, "-o", "json", "sku", "list", "-limit", "2"), &skus)
+	assert.Len(t, skus, 2)
+
+	decode(t, exec(srv, nil, "-o", "json", "sku", "find", "-ean", "4600000000003"), &skus)
+	if assert.Len(t, skus, 1) {
+		assert.Equal(t, 3, skus[0].ID)
+	}
+
+	decode(t, exec(srv, nil, "-o", "json", "sku", "find", "-cid", "c-2"), &skus)
+	if assert.Len(t, skus, 1) {
+		assert.Equal(t, "Cola 1.0", skus[0].Name)
+	}
+
+	r := exec(srv, nil, "sku", "export", "-columns", "id,name")
+	assert.Equal(t, exitOK, r.code, r.stderr)
+	assert.Equal(t, "id,name\n1,Cola 0.5\n2,Cola 1.0\n3,Water 0.5\n", r.stdout)
+	assert.Equal(t, "exported 3 SKUs\n", r.stderr)
+
+	path := filepath.Join(t.TempDir(), "skus.jsonl")
+	r = exec(srv, nil, "-o", "json", "sku", "export", "-file", path)
+	assert.Equal(t, exitOK, r.code, r.stderr)
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, bytes.Count(data, []byte("\n")))
+}
+
+func TestRun_Visit(t *testing.T) {
+	srv := inspectortest.NewServer()
+	defer srv.Close()
+	srv.AddVisit(inspector.Visit{Shop: 1, Agent: "a1"})
+
+	var visit inspector.Visit
+	decode(t, exec(srv, nil, "-o", "json", "visit", "create", "-shop", "2", "-agent", "a2", "-started", "2021-07-03T07:30:00Z"), &visit)
+	assert.Equal(t, 2, visit.Shop)
+	assert.Equal(t, "a2", visit.Agent)
+
+	var visits []inspector.Visit
+	decode(t, exec(srv, nil, "-o", "json", "visit", "list"), &visits)
+	assert.Len(t, visits, 2)
+
+	decode(t, exec(srv, nil, "-o", "json", "visit", "list", "-shop", "2"), &visits)
+	if assert.Len(t, visits, 1) {
+		assert.Equal(t, visit.ID, visits[0].ID)
+	}
+
+	r := exec(srv, nil, "-o", "csv", "visit", "list", "-agent", "a1")
+	assert.Equal(t, exitOK, r.code, r.stderr)
+	assert.Equal(t, 2, strings.Count(r.stdout, "\n"), r.stdout)
+}
+
+func TestRun_Feedback(t *testing.T) {
+	srv := inspectortest.NewServer()
+	defer srv.Close()
+	srv.SetReadyAfter(0)
+	srv.SetReportData(inspector.ReportTypeREALOGRAM, []inspector.ReportRealogramJson{{
+		Image: 10,
+		Annotations: []inspector.ReportRealogramAnnotations{
+			{X: 1, Y: 2, W: 3, H: 4, Name: "Cola 0.5", SkuId: 1},
+			{X: 5, Y: 6, W: 7, H: 8, Name: "Cola 1.0", SkuId: 2},
+		},
+	}})
+	img, err := srv.Client().Image.UploadByURL(context.Background(), "https://example.com/shelf.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := srv.Client().Recognize.Recognize(context.Background(), inspector.RecognizeRequest{
+		Images:      []int{img.ID},
+		ReportTypes: []string{inspector.ReportTypeREALOGRAM},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reportID := strconv.Itoa(rec.Reports[inspector.ReportTypeREALOGRAM])
+
+	corrections := `[
+		{"kind": "WRONG_SKU", "annotation": 0, "sku_id": 2},
+		{"kind": "FALSE_POSITIVE", "annotation": 1}
+	]`
+	var items []struct {
+		Corrections        []int  `json:"corrections"`
+		RecognitionErrorID int    `json:"recognition_error_id"`
+		Error              string `json:"error"`
+	}
+	decode(t, exec(srv, strings.NewReader(corrections), "-o", "json", "feedback",
+		"-report", reportID, "-scene", rec.Scene, "-corrections", "-"), &items)
+	assert.Len(t, items, 2)
+	assert.Len(t, srv.RecognitionErrors(), 2)
+	for _, it := range items {
+		assert.NotZero(t, it.RecognitionErrorID)
+		assert.Empty(t, it.Error)
+	}
+
+	r := exec(srv, strings.NewReader("{"), "feedback", "-report", reportID, "-scene", rec.Scene, "-corrections", "-")
+	assert.Equal(t, exitUsage, r.code)
+	r = exec(srv, strings.NewReader(corrections), "feedback", "-report", reportID, "-scene", rec.Scene, "-corrections", "-", "-image", "11")
+	assert.Equal(t, exitFailure, r.code)
+}
+
+func TestExitCode(t *testing.T) {
+	status := func(code int) error {
+		return fmt.Errorf("failed to GetReport:%w", &httpclient.ErrorResponse{Response: &http.Response{StatusCode: code}})
+	}
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, exitOK},
+		{"other", errors.New("boom"), exitFailure},
+		{"usage", usageErrorf("bad flag"), exitUsage},
+		{"unauthorized", status(http.StatusUnauthorized), exitAuth},
+		{"forbidden", status(http.StatusForbidden), exitAuth},
+		{"not found", status(http.StatusNotFound), exitNotFound},
+		{"bad request", status(http.StatusBadRequest), exitRejected},
+		{"too many requests", status(http.StatusTooManyRequests), exitUnavailable},
+		{"server error", status(http.StatusBadGateway), exitUnavailable},
+		{"deadline", fmt.Errorf("wait:%w", context.DeadlineExceeded), exitTimeout},
+		{"canceled", context.Canceled, exitTimeout},
+		{"missing file", &os.PathError{Op: "open", Path: "shelf.jpg", Err: os.ErrNotExist}, exitFailure},
+		{"connection refused", &url.Error{Op: "Get", URL: "http://localhost", Err: errors.New("connection refused")}, exitUnavailable},
+		{"report failed", fmt.Errorf("report 1:%w", inspector.ErrReportFailed), exitReportFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, exitCode(tt.err))
+		})
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	srv := inspectortest.NewServer()
+	defer srv.Close()
+
+	t.Run("bad api key", func(t *testing.T) {
+		r := exec(srv, nil, "-api-key", "wrong", "sku", "list")
+		assert.Equal(t, exitAuth, r.code, r.stderr)
+		assert.True(t, strings.HasPrefix(r.stderr, "inspector: "), r.stderr)
+	})
+	t.Run("server error", func(t *testing.T) {
+		srv.Inject(inspectortest.Fault{Path: inspectortest.PathSku, Status: http.StatusServiceUnavailable})
+		defer srv.ClearFaults()
+		assert.Equal(t, exitUnavailable, exec(srv, nil, "sku", "list").code)
+	})
+}
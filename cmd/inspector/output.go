@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+)
+
+// Output formats
+const (
+	outputJSON  = "json"
+	outputTable = "table"
+	outputCSV   = "csv"
+)
+
+// table is the tabular form of a command result.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func (t *table) add(cells ...any) {
+	row := make([]string, len(cells))
+	for i, v := range cells {
+		row[i] = cell(v)
+	}
+	t.rows = append(t.rows, row)
+}
+
+// cell formats a value for table and CSV output.
+func cell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case *int:
+		if v == nil {
+			return ""
+		}
+		return strconv.Itoa(*v)
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case []int:
+		s := make([]string, len(v))
+		for i, n := range v {
+			s[i] = strconv.Itoa(n)
+		}
+		return strings.Join(s, " ")
+	}
+	return fmt.Sprint(v)
+}
+
+// render writes a result: v as JSON, or t as an aligned table or CSV.
+func (c *cli) render(v any, t *table) error {
+	switch c.output {
+	case outputJSON:
+		enc := json.NewEncoder(c.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case outputCSV:
+		w := csv.NewWriter(c.stdout)
+		w.Write(t.header)
+		w.WriteAll(t.rows)
+		return w.Error()
+	}
+	w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(t.header, "\t")))
+	for _, row := range t.rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+func imageTable(images ...inspector.Image) *table {
+	t := &table{header: []string{"id", "url", "width", "height", "created_date"}}
+	for _, img := range images {
+		t.add(img.ID, img.URL, img.Width, img.Height, img.CreatedDate)
+	}
+	return t
+}
+
+func reportTable(reports ...*inspector.Report) *table {
+	t := &table{header: []string{"id", "report_type", "status", "visit", "created_date", "updated_date"}}
+	for _, r := range reports {
+		t.add(r.ID, r.ReportType, r.Status, r.Visit, r.CreatedDate, r.UpdatedDate)
+	}
+	return t
+}
+
+func skuTable(skus ...inspector.Sku) *table {
+	t := &table{header: []string{"id", "cid", "ean13", "name", "brand", "category", "manufacturer"}}
+	for _, s := range skus {
+		t.add(s.ID, s.CID, s.EAN13, s.Name, s.Brand, s.Category, s.Manufacturer)
+	}
+	return t
+}
+
+func visitTable(visits ...inspector.Visit) *table {
+	t := &table{header: []string{"id", "shop", "agent", "started_date", "latitude", "longitude"}}
+	for _, v := range visits {
+		t.add(v.ID, v.Shop, v.Agent, v.StartedDate, v.Latitude, v.Longitude)
+	}
+	return t
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+)
+
+func recognize(c *cli, args []string) error {
+	fs := c.flags("recognize")
+	images := fs.String("images", "", "comma separated image IDs (required)")
+	reports := fs.String("reports", inspector.ReportTypeFACING_COUNT, "comma separated report types")
+	display := fs.Int("display", 0, "display ID")
+	visit := fs.Int("visit", 0, "visit ID")
+	retailChain := fs.String("retail-chain", "", "retail chain of the store")
+	country := fs.String("country", "", "two-letter country code of the SKUs")
+	webhook := fs.String("webhook", "", "URL the reports are POSTed to when ready")
+	wait := fs.Bool("wait", false, "wait for the reports and print them")
+	interval := fs.Duration("interval", inspector.ReportWaitDefaultInterval, "report polling interval with -wait")
+	waitTimeout := fs.Duration("wait-timeout", inspector.ReportWaitDefaultTimeout, "overall report timeout with -wait")
+	pos, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usageErrorf("recognize: unexpected arguments %v", pos)
+	}
+	ids, err := intList(*images)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return usageErrorf("recognize: -images is required")
+	}
+	api, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	rr := inspector.RecognizeRequest{
+		Images:      ids,
+		ReportTypes: strings.Split(*reports, ","),
+		Display:     *display,
+		Visit:       *visit,
+		Webhook:     *webhook,
+		CountryCode: *country,
+		RetailChain: *retailChain,
+	}
+	rec, err := api.Recognize.Recognize(c.ctx, rr)
+	if err != nil {
+		return err
+	}
+	types := make([]string, 0, len(rec.Reports))
+	for t := range rec.Reports {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	if !*wait {
+		t := &table{header: []string{"id", "scene", "report_type", "report_id"}}
+		for _, rt := range types {
+			t.add(rec.ID, rec.Scene, rt, rec.Reports[rt])
+		}
+		return c.render(rec, t)
+	}
+
+	result := struct {
+		Recognition *inspector.RecognizeResponse `json:"recognition"`
+		Reports     []*inspector.Report          `json:"reports"`
+	}{Recognition: rec}
+	for _, rt := range types {
+		report, err := waitReport(c, api, rec.Reports[rt], *interval, *waitTimeout)
+		if err != nil {
+			return err
+		}
+		result.Reports = append(result.Reports, report)
+	}
+	return c.render(result, reportTable(result.Reports...))
+}
+
+// waitReport polls a report, printing status changes to stderr.
+func waitReport(c *cli, api *inspector.Client, id int, interval, timeout time.Duration) (*inspector.Report, error) {
+	var last string
+	return api.Report.WaitForReport(c.ctx, id, &inspector.ReportWaitOptions{
+		Interval: interval,
+		Timeout:  timeout,
+		OnProgress: func(r *inspector.Report) {
+			if r.Status != last {
+				fmt.Fprintf(c.stderr, "report %d: %s\n", r.ID, r.Status)
+				last = r.Status
+			}
+		},
+	})
+}
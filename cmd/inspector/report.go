@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+)
+
+func reportGet(c *cli, args []string) error {
+	fs := c.flags("report get")
+	pos, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := idArg(fs.Name(), pos)
+	if err != nil {
+		return err
+	}
+	api, err := c.client()
+	if err != nil {
+		return err
+	}
+	report, err := api.Report.GetReport(c.ctx, id)
+	if err != nil {
+		return err
+	}
+	return c.render(report, reportTable(report))
+}
+
+func reportWait(c *cli, args []string) error {
+	fs := c.flags("report wait")
+	interval := fs.Duration("interval", inspector.ReportWaitDefaultInterval, "polling interval")
+	waitTimeout := fs.Duration("wait-timeout", inspector.ReportWaitDefaultTimeout, "overall timeout")
+	pos, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := idArg(fs.Name(), pos)
+	if err != nil {
+		return err
+	}
+	api, err := c.client()
+	if err != nil {
+		return err
+	}
+	report, err := waitReport(c, api, id, *interval, *waitTimeout)
+	if err != nil {
+		return err
+	}
+	return c.render(report, reportTable(report))
+}
+
+func reportExport(c *cli, args []string) error {
+	fs := c.flags("report export")
+	xlsx := fs.String("xlsx", "", "write an XLSX workbook to this file instead of printing rows")
+	catalog := fs.Bool("catalog", false, "add SKU names and EANs from the catalog")
+	pos, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := idArg(fs.Name(), pos)
+	if err != nil {
+		return err
+	}
+	api, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	report, err := api.Report.GetReport(c.ctx, id)
+	if err != nil {
+		return err
+	}
+	switch report.Status {
+	case inspector.ReportStatusREADY:
+	case inspector.ReportStatusERROR:
+		return fmt.Errorf("report %d has status ERROR:%w", id, inspector.ErrReportFailed)
+	default:
+		return fmt.Errorf("report %d is not ready: status %s", id, report.Status)
+	}
+	decoded, err := api.Report.DecodeReport(report)
+	if err != nil {
+		return err
+	}
+	opts := &inspector.ReportExportOptions{}
+	if *catalog {
+		skus, err := api.Sku.GetAllSKU(c.ctx, inspector.DefaultPageSize)
+		if err != nil {
+			return err
+		}
+		opts.SKUs = inspector.NewSkuIndex(skus)
+	}
+
+	if *xlsx != "" {
+		return c.exportXLSX(*xlsx, decoded, opts)
+	}
+	switch c.output {
+	case outputJSON:
+		return c.render(decoded, nil)
+	case outputCSV:
+		return inspector.ExportReportCSV(c.stdout, decoded, opts)
+	}
+	tables, err := inspector.ReportTables(decoded, opts)
+	if err != nil {
+		return err
+	}
+	for _, rt := range tables {
+		t := &table{header: rt.Header}
+		for _, row := range rt.Rows {
+			t.add(row...)
+		}
+		if err := c.render(nil, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportXLSX writes the workbook to path and prints its sheets.
+func (c *cli) exportXLSX(path string, decoded any, opts *inspector.ReportExportOptions) error {
+	tables, err := inspector.ReportTables(decoded, opts)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := inspector.ExportReportXLSX(f, decoded, opts); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	type sheet struct {
+		Name string `json:"name"`
+		Rows int    `json:"rows"`
+	}
+	result := struct {
+		File   string  `json:"file"`
+		Sheets []sheet `json:"sheets"`
+	}{File: path}
+	t := &table{header: []string{"file", "sheet", "rows"}}
+	for _, rt := range tables {
+		result.Sheets = append(result.Sheets, sheet{rt.Name, len(rt.Rows)})
+		t.add(path, rt.Name, len(rt.Rows))
+	}
+	return c.render(result, t)
+}
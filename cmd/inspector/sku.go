@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+)
+
+func skuList(c *cli, args []string) error {
+	fs := c.flags("sku list")
+	search := fs.String("search", "", "full-text search by name and codes")
+	brand := fs.Int("brand", 0, "brand ID")
+	category := fs.Int("category", 0, "category ID")
+	manufacturer := fs.Int("manufacturer", 0, "manufacturer ID")
+	ordering := fs.String("ordering", "", `field to order by, "-" prefix for descending`)
+	offset := fs.Int("offset", 0, "first item")
+	limit := fs.Int("limit", inspector.DefaultPageSize, "page size")
+	all := fs.Bool("all", false, "fetch every page")
+	pos, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usageErrorf("sku list: unexpected arguments %v", pos)
+	}
+	api, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	q := &inspector.SkuQuery{
+		Search:       *search,
+		Brand:        optionalID(*brand),
+		Category:     optionalID(*category),
+		Manufacturer: optionalID(*manufacturer),
+		Ordering:     *ordering,
+	}
+	var skus []inspector.Sku
+	if *all {
+		skus, err = api.Sku.GetAllSKU(c.ctx, *limit, q)
+	} else {
+		var page *inspector.Pagination
+		if page, err = api.Sku.GetSKU(c.ctx, *offset, *limit, q); err == nil {
+			skus, err = api.Sku.ToSku(page.Results)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return c.render(skus, skuTable(skus...))
+}
+
+// optionalID returns nil for 0.
+func optionalID(id int) *int {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+func skuFind(c *cli, args []string) error {
+	fs := c.flags("sku find")
+	ean := fs.String("ean", "", "EAN-13")
+	cid := fs.String("cid", "", "client-specific SKU ID")
+	id := fs.Int("id", 0, "SKU ID")
+	pos, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	set := 0
+	for _, ok := range []bool{*ean != "", *cid != "", *id != 0} {
+		if ok {
+			set++
+		}
+	}
+	if len(pos) > 0 || set != 1 {
+		return usageErrorf("sku find: set exactly one of -ean, -cid and -id")
+	}
+	api, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	var skus []inspector.Sku
+	switch {
+	case *ean != "":
+		skus, err = api.Sku.FindByEAN(c.ctx, *ean)
+	case *cid != "":
+		skus, err = api.Sku.FindByCID(c.ctx, *cid)
+	default:
+		var sku *inspector.Sku
+		if sku, err = api.Sku.GetSKUByID(c.ctx, *id); err == nil {
+			skus = []inspector.Sku{*sku}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return c.render(skus, skuTable(skus...))
+}
+
+func skuExport(c *cli, args []string) error {
+	fs := c.flags("sku export")
+	format := fs.String("format", "", "csv, jsonl or columnar (default: jsonl with -output json, csv otherwise)")
+	file := fs.String("file", "", "write to this file instead of stdout")
+	columns := fs.String("columns", "", "comma separated CSV and columnar columns (default: all)")
+	search := fs.String("search", "", "export only SKUs matching this search")
+	pos, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usageErrorf("sku export: unexpected arguments %v", pos)
+	}
+	if *format == "" {
+		*format = inspector.SkuFormatCSV
+		if c.output == outputJSON {
+			*format = inspector.SkuFormatJSONL
+		}
+	}
+	opts := &inspector.SkuExportOptions{Format: *format}
+	if *columns != "" {
+		opts.Columns = strings.Split(*columns, ",")
+	}
+	if *search != "" {
+		opts.Query = &inspector.SkuQuery{Search: *search}
+	}
+	api, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	var (
+		w io.Writer = c.stdout
+		f *os.File
+	)
+	if *file != "" {
+		if f, err = os.Create(*file); err != nil {
+			return err
+		}
+		w = f
+	}
+	n, err := api.Sku.ExportSKU(c.ctx, w, opts)
+	if f != nil {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stderr, "exported %d SKUs\n", n)
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"time"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+)
+
+func visitCreate(c *cli, args []string) error {
+	fs := c.flags("visit create")
+	shop := fs.Int("shop", 0, "shop ID")
+	agent := fs.String("agent", "", "agent name, ID or route")
+	started := fs.String("started", "", "start time, RFC 3339 (default: set by the server)")
+	pos, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usageErrorf("visit create: unexpected arguments %v", pos)
+	}
+	vr := inspector.VisitCreateRequest{Shop: *shop, Agent: *agent}
+	if vr.StartedDate, err = timeFlag("started", *started); err != nil {
+		return err
+	}
+	api, err := c.client()
+	if err != nil {
+		return err
+	}
+	visit, err := api.Visit.CreateVisit(c.ctx, vr)
+	if err != nil {
+		return err
+	}
+	return c.render(visit, visitTable(*visit))
+}
+
+func visitList(c *cli, args []string) error {
+	fs := c.flags("visit list")
+	shop := fs.Int("shop", 0, "shop ID")
+	agent := fs.String("agent", "", "agent name, ID or route")
+	after := fs.String("after", "", "visits started at or after this RFC 3339 time")
+	before := fs.String("before", "", "visits started at or before this RFC 3339 time")
+	pos, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usageErrorf("visit list: unexpected arguments %v", pos)
+	}
+	q := &inspector.VisitQuery{Shop: optionalID(*shop), Agent: *agent}
+	if q.StartedAfter, err = timeFlag("after", *after); err != nil {
+		return err
+	}
+	if q.StartedBefore, err = timeFlag("before", *before); err != nil {
+		return err
+	}
+	api, err := c.client()
+	if err != nil {
+		return err
+	}
+	visits, err := api.Visit.ListVisits(c.ctx, q)
+	if err != nil {
+		return err
+	}
+	return c.render(visits, visitTable(visits...))
+}
+
+// timeFlag parses an optional RFC 3339 time flag.
+func timeFlag(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, usageErrorf("-%s: %v", name, err)
+	}
+	return &t, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+)
+
+func main() {
+	// Define flags
+	manifest := flag.String("manifest", "", "Manifest file with one image URL or path per line (required)")
+	checkpoint := flag.String("checkpoint", "batch.checkpoint", "Checkpoint file used to resume the batch")
+	reportTypes := flag.String("types", "FACING_COUNT", "Comma-separated report types")
+	concurrency := flag.Int("concurrency", 4, "Workers per stage")
+	timeout := flag.Duration("timeout", 5*time.Minute, "Timeout for each report")
+	flag.Parse()
+
+	// Validate required flags
+	if *manifest == "" {
+		fmt.Fprintf(os.Stderr, "Error: -manifest flag is required\n\n")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	// Get credentials from environment
+	apiKey := os.Getenv("API_KEY")
+	instance := os.Getenv("INSTANCE")
+	if apiKey == "" || instance == "" {
+		log.Fatal("API_KEY and INSTANCE environment variables must be set")
+	}
+
+	// Create client
+	client, err := inspector.NewClient(inspector.ClientConf{
+		APIKey:   apiKey,
+		Instance: instance,
+		Timeout:  30 * time.Second,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	// Read manifest
+	f, err := os.Open(*manifest)
+	if err != nil {
+		log.Fatalf("Failed to open manifest: %v", err)
+	}
+	items, err := inspector.ReadBatchManifest(f)
+	f.Close()
+	if err != nil {
+		log.Fatalf("Failed to read manifest: %v", err)
+	}
+
+	// Stop on Ctrl+C; the next run resumes from the checkpoint
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Write every finished item as a JSON line to stdout
+	enc := json.NewEncoder(os.Stdout)
+	pipeline := inspector.NewBatchPipeline(client, &inspector.BatchOptions{
+		Request:              inspector.RecognizeRequest{ReportTypes: strings.Split(*reportTypes, ",")},
+		Checkpoint:           *checkpoint,
+		UploadConcurrency:    *concurrency,
+		RecognizeConcurrency: *concurrency,
+		WaitConcurrency:      *concurrency,
+		Wait:                 &inspector.ReportWaitOptions{Timeout: *timeout},
+		Sink: func(ctx context.Context, res *inspector.BatchResult) error {
+			fmt.Fprintf(os.Stderr, "Done: %s\n", res.Record.Key)
+			return enc.Encode(map[string]any{
+				"key":     res.Record.Key,
+				"record":  res.Record,
+				"reports": res.Decoded,
+			})
+		},
+	})
+
+	summary, err := pipeline.Run(ctx, items)
+	if summary != nil {
+		for key, ferr := range summary.Failures {
+			fmt.Fprintf(os.Stderr, "Failed: %s: %v\n", key, ferr)
+		}
+		fmt.Fprintf(os.Stderr, "Total: %d, skipped: %d, done: %d, failed: %d\n",
+			summary.Total, summary.Skipped, summary.Done, summary.Failed)
+	}
+	if err != nil {
+		log.Fatalf("Batch interrupted: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+)
+
+func main() {
+	// Define flags
+	reportID := flag.Int("id", 0, "REALOGRAM report ID (required)")
+	planogramPath := flag.String("planogram", "", "Planogram file, .json or .yaml (required)")
+	flag.Parse()
+
+	// Validate required flags
+	if *reportID == 0 || *planogramPath == "" {
+		fmt.Fprintf(os.Stderr, "Error: -id and -planogram flags are required\n\n")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	planogram, err := inspector.LoadPlanogram(*planogramPath)
+	if err != nil {
+		log.Fatalf("Failed to load planogram: %v", err)
+	}
+
+	// Get credentials from environment
+	apiKey := os.Getenv("API_KEY")
+	instance := os.Getenv("INSTANCE")
+	if apiKey == "" || instance == "" {
+		log.Fatal("API_KEY and INSTANCE environment variables must be set")
+	}
+
+	// Create client
+	client, err := inspector.NewClient(inspector.ClientConf{
+		APIKey:   apiKey,
+		Instance: instance,
+		Timeout:  30 * time.Second,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	report, err := client.Report.GetReport(context.Background(), *reportID)
+	if err != nil {
+		log.Fatalf("Failed to get report: %v", err)
+	}
+	realograms, err := client.Report.ToRealogram(report.Json)
+	if err != nil {
+		log.Fatalf("Failed to parse REALOGRAM report: %v", err)
+	}
+
+	// One compliance result per photo
+	results := make([]*inspector.PlanogramCompliance, 0, len(realograms))
+	for _, r := range realograms {
+		c := inspector.CheckPlanogram(planogram, r, nil)
+		fmt.Fprintf(os.Stderr, "Image %d: score %.2f %v\n", c.Image, c.Score, c.Counts())
+		results = append(results, c)
+	}
+
+	// Output JSON result
+	output, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to marshal result: %v", err)
+	}
+	fmt.Println(string(output))
+}
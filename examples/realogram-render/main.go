@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+)
+
+func main() {
+	// Define flags
+	reportID := flag.Int("id", 0, "REALOGRAM report ID (required)")
+	imageID := flag.Int("image", 0, "Image ID within the report (default: first image)")
+	photo := flag.String("photo", "", "Local photo the report was made from (required for .png output)")
+	out := flag.String("out", "realogram.svg", "Output file, .png or .svg")
+	highlight := flag.String("highlight", "", "Comma-separated SKU IDs to highlight (optional)")
+	flag.Parse()
+
+	// Validate required flags
+	if *reportID == 0 {
+		fmt.Fprintf(os.Stderr, "Error: -id flag is required\n\n")
+		flag.Usage()
+		os.Exit(1)
+	}
+	ext := strings.ToLower(filepath.Ext(*out))
+	if ext != ".png" && ext != ".svg" {
+		log.Fatalf("Unsupported output %s (valid: .png, .svg)", *out)
+	}
+	if ext == ".png" && *photo == "" {
+		log.Fatal("-photo is required for .png output")
+	}
+
+	opts := &inspector.RenderOptions{}
+	if *highlight != "" {
+		for _, s := range strings.Split(*highlight, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				log.Fatalf("Invalid SKU ID %q: %v", s, err)
+			}
+			opts.Highlight = append(opts.Highlight, id)
+		}
+	}
+
+	// Get credentials from environment
+	apiKey := os.Getenv("API_KEY")
+	instance := os.Getenv("INSTANCE")
+	if apiKey == "" || instance == "" {
+		log.Fatal("API_KEY and INSTANCE environment variables must be set")
+	}
+
+	// Create client
+	client, err := inspector.NewClient(inspector.ClientConf{
+		APIKey:   apiKey,
+		Instance: instance,
+		Timeout:  30 * time.Second,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	report, err := client.Report.GetReport(context.Background(), *reportID)
+	if err != nil {
+		log.Fatalf("Failed to get report: %v", err)
+	}
+	realograms, err := client.Report.ToRealogram(report.Json)
+	if err != nil {
+		log.Fatalf("Failed to parse REALOGRAM report: %v", err)
+	}
+	if len(realograms) == 0 {
+		log.Fatal("Report has no realogram images")
+	}
+	realogram := realograms[0]
+	if *imageID != 0 {
+		found := false
+		for _, r := range realograms {
+			if r.Image == *imageID {
+				realogram, found = r, true
+			}
+		}
+		if !found {
+			log.Fatalf("Image %d is not in report %d", *imageID, *reportID)
+		}
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatalf("Failed to create output: %v", err)
+	}
+	defer f.Close()
+
+	img := inspector.Image{ID: realogram.Image}
+	if *photo != "" {
+		base, err := inspector.LoadImageFile(*photo)
+		if err != nil {
+			log.Fatalf("Failed to load photo: %v", err)
+		}
+		if ext == ".png" {
+			err = inspector.RenderRealogramPNG(f, base, realogram, opts)
+			if err != nil {
+				log.Fatalf("Failed to render: %v", err)
+			}
+			fmt.Fprintf(os.Stderr, "Wrote %s\n", *out)
+			return
+		}
+		img.Width, img.Height = base.Bounds().Dx(), base.Bounds().Dy()
+	}
+	if err := inspector.RenderRealogramSVG(f, img, realogram, opts); err != nil {
+		log.Fatalf("Failed to render: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s\n", *out)
+}
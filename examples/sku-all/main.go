@@ -16,6 +16,7 @@ func main() {
 	// Define flags
 	pageSize := flag.Int("page-size", 100, "Items per page")
 	max := flag.Int("max", 0, "Maximum total items to fetch (0 = all)")
+	parallel := flag.Int("parallel", 0, "Fetch pages with this many concurrent requests (0 = sequential, ignores -max)")
 	flag.Parse()
 
 	// Get credentials from environment
@@ -35,8 +36,32 @@ func main() {
 		log.Fatalf("Failed to create client: %v", err)
 	}
 
-	// Fetch SKUs using iterator
 	ctx := context.Background()
+
+	// Fetch SKUs with concurrent page requests
+	if *parallel > 0 {
+		allSKUs, err := client.Sku.GetAllSKUParallel(ctx, &inspector.ParallelFetchOptions{
+			PageSize:    *pageSize,
+			Concurrency: *parallel,
+			MaxRetries:  1,
+			OnWarning: func(w inspector.ConsistencyWarning) {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+			},
+		})
+		if err != nil {
+			log.Fatalf("Failed to fetch SKUs: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Total SKUs retrieved: %d\n", len(allSKUs))
+
+		output, err := json.MarshalIndent(allSKUs, "", "  ")
+		if err != nil {
+			log.Fatalf("Failed to marshal result: %v", err)
+		}
+		fmt.Println(string(output))
+		return
+	}
+
+	// Fetch SKUs using iterator
 	iterator := client.Sku.IterateSKU(ctx, *pageSize)
 
 	var allSKUs []inspector.Sku
@@ -16,6 +16,8 @@ func main() {
 	// Define flags
 	offset := flag.Int("offset", 0, "Pagination offset")
 	limit := flag.Int("limit", 10, "Items per page")
+	search := flag.String("search", "", "Search text (optional)")
+	ean := flag.String("ean", "", "Filter by EAN13 (optional)")
 	flag.Parse()
 
 	// Get credentials from environment
@@ -37,7 +39,8 @@ func main() {
 
 	// Get SKU page
 	ctx := context.Background()
-	pag, err := client.Sku.GetSKU(ctx, *offset, *limit)
+	query := &inspector.SkuQuery{Search: *search, EAN13: *ean}
+	pag, err := client.Sku.GetSKU(ctx, *offset, *limit, query)
 	if err != nil {
 		log.Fatalf("Failed to get SKU list: %v", err)
 	}
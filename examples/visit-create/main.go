@@ -14,6 +14,8 @@ import (
 
 func main() {
 	// Define flags (none required - server assigns defaults)
+	shop := flag.Int("shop", 0, "Shop ID (optional)")
+	agent := flag.String("agent", "", "Agent name/id/route (optional)")
 	flag.Parse()
 
 	// Get credentials from environment
@@ -33,9 +35,12 @@ func main() {
 		log.Fatalf("Failed to create client: %v", err)
 	}
 
-	// Create visit (server assigns defaults)
+	// Create visit (server assigns defaults for omitted fields)
 	ctx := context.Background()
-	visit, err := client.Visit.AddVisit(ctx)
+	visit, err := client.Visit.CreateVisit(ctx, inspector.VisitCreateRequest{
+		Shop:  *shop,
+		Agent: *agent,
+	})
 	if err != nil {
 		log.Fatalf("Failed to create visit: %v", err)
 	}
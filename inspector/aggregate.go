@@ -0,0 +1,443 @@
+package inspector
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Aggregation time buckets
+const (
+	AggregateBucketNONE  = ""      // one bucket for all time
+	AggregateBucketDAY   = "day"   // calendar day
+	AggregateBucketWEEK  = "week"  // ISO week, starting on Monday
+	AggregateBucketMONTH = "month" // calendar month
+)
+
+// AggregateDimension groups aggregation inputs by a key, e.g. the retail chain.
+type AggregateDimension struct {
+	Name string
+	Key  func(in *AggregateInput) string
+}
+
+// Built-in aggregation dimensions. Inputs without the metadata get an empty key.
+var (
+	DimensionRetailChain = AggregateDimension{Name: "retail_chain", Key: func(in *AggregateInput) string {
+		if in.Shop == nil {
+			return ""
+		}
+		return in.Shop.RetailChain
+	}}
+	DimensionShop = AggregateDimension{Name: "shop", Key: func(in *AggregateInput) string {
+		switch {
+		case in.Shop != nil:
+			return strconv.Itoa(in.Shop.ID)
+		case in.Visit != nil && in.Visit.Shop != 0:
+			return strconv.Itoa(in.Visit.Shop)
+		}
+		return ""
+	}}
+	DimensionAgent = AggregateDimension{Name: "agent", Key: func(in *AggregateInput) string {
+		if in.Visit == nil {
+			return ""
+		}
+		return in.Visit.Agent
+	}}
+	DimensionVisit = AggregateDimension{Name: "visit", Key: func(in *AggregateInput) string {
+		if in.Report == nil || in.Report.Visit == 0 {
+			return ""
+		}
+		return strconv.Itoa(in.Report.Visit)
+	}}
+)
+
+var (
+	// ErrAggregateInput is returned by Aggregator.Add for inputs that cannot be aggregated.
+	ErrAggregateInput = errors.New("invalid aggregate input")
+	// ErrAggregateOptions is returned by NewAggregator for an unknown Bucket or FacingsSource.
+	ErrAggregateOptions = errors.New("invalid aggregate options")
+)
+
+// AggregateInput is one decoded report with its visit and shop.
+type AggregateInput struct {
+	Report  *Report   // required
+	Decoded any       // output of ReportService.DecodeReport; decoded from Report.Json when nil
+	Visit   *Visit    // optional
+	Shop    *Shop     // optional; RetailChain and ExternalCode select the must-have list
+	Time    time.Time // defaults to Visit.StartedDate, then Report.CreatedDate
+}
+
+// AggregateOptions configures an Aggregator.
+type AggregateOptions struct {
+	Dimensions    []AggregateDimension
+	Bucket        string         // AggregateBucket*
+	Location      *time.Location // time zone of buckets; default UTC
+	SKUs          SkuLookup      // resolves brands for BrandFacings; optional
+	MHL           *MustHaveList  // must-have list for OSA; optional
+	FacingsSource string         // report type facings and OSA come from: ReportTypeFACING_COUNT (default) or ReportTypeREALOGRAM
+}
+
+// PriceStats summarizes the shelf prices of a SKU.
+type PriceStats struct {
+	Count int     `json:"count"`
+	Sum   float64 `json:"sum"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+}
+
+// Mean returns the average price.
+func (s *PriceStats) Mean() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / float64(s.Count)
+}
+
+func (s *PriceStats) add(o PriceStats) {
+	if o.Count == 0 {
+		return
+	}
+	if s.Count == 0 {
+		*s = o
+		return
+	}
+	s.Count += o.Count
+	s.Sum += o.Sum
+	s.Min = math.Min(s.Min, o.Min)
+	s.Max = math.Max(s.Max, o.Max)
+}
+
+// MHLVisit holds the facings of one visit merged over its reports, so a must-have
+// SKU found on any display of the visit counts as available.
+type MHLVisit struct {
+	RetailChain string      `json:"retail_chain"`
+	Store       string      `json:"store,omitempty"`
+	Facings     map[int]int `json:"facings"` // by SKU ID
+}
+
+// Aggregate holds the metrics of one group. Aggregates are partial results:
+// Merge combines aggregates of the same group computed by different workers.
+// The must-have counts are computed from MHLVisits by Aggregator.Results.
+type Aggregate struct {
+	Key          map[string]string    `json:"key"`              // dimension name to value
+	Bucket       *time.Time           `json:"bucket,omitempty"` // bucket start; nil for AggregateBucketNONE
+	Reports      int                  `json:"reports"`
+	Facings      map[int]int          `json:"facings"`                 // by SKU ID
+	BrandFacings map[int]int          `json:"brand_facings,omitempty"` // by brand ID, 0 for unknown
+	Prices       map[int]*PriceStats  `json:"prices,omitempty"`        // by SKU ID
+	MHLRequired  int                  `json:"mhl_required"`            // must-have items checked
+	MHLAvailable int                  `json:"mhl_available"`           // must-have items with any facing
+	MHLCompliant int                  `json:"mhl_compliant"`           // must-have items with at least MinFacings
+	MHLSkipped   int                  `json:"mhl_skipped"`             // facings reports not checked: no shop or chain not in the list
+	MHLVisits    map[string]*MHLVisit `json:"mhl_visits,omitempty"`    // by visit
+}
+
+func newAggregate(key map[string]string, bucket *time.Time) *Aggregate {
+	if bucket != nil {
+		t := *bucket
+		bucket = &t
+	}
+	return &Aggregate{
+		Key:          key,
+		Bucket:       bucket,
+		Facings:      map[int]int{},
+		BrandFacings: map[int]int{},
+		Prices:       map[int]*PriceStats{},
+		MHLVisits:    map[string]*MHLVisit{},
+	}
+}
+
+// OSA returns the on-shelf availability rate in percent over every must-have check.
+func (a *Aggregate) OSA() float64 {
+	if a.MHLRequired == 0 {
+		return 0
+	}
+	return 100 * float64(a.MHLAvailable) / float64(a.MHLRequired)
+}
+
+// AveragePrice returns the mean shelf price of sku.
+func (a *Aggregate) AveragePrice(sku int) float64 {
+	if s, ok := a.Prices[sku]; ok {
+		return s.Mean()
+	}
+	return 0
+}
+
+// Merge adds the metrics of o, which must belong to the same group.
+func (a *Aggregate) Merge(o *Aggregate) {
+	a.Reports += o.Reports
+	for k, v := range o.Facings {
+		a.Facings[k] += v
+	}
+	for k, v := range o.BrandFacings {
+		a.BrandFacings[k] += v
+	}
+	for k, v := range o.Prices {
+		s, ok := a.Prices[k]
+		if !ok {
+			s = &PriceStats{}
+			a.Prices[k] = s
+		}
+		s.add(*v)
+	}
+	a.MHLRequired += o.MHLRequired
+	a.MHLAvailable += o.MHLAvailable
+	a.MHLCompliant += o.MHLCompliant
+	a.MHLSkipped += o.MHLSkipped
+	for k, v := range o.MHLVisits {
+		if a.MHLVisits == nil {
+			a.MHLVisits = map[string]*MHLVisit{}
+		}
+		dst, ok := a.MHLVisits[k]
+		if !ok {
+			dst = &MHLVisit{RetailChain: v.RetailChain, Store: v.Store, Facings: map[int]int{}}
+			a.MHLVisits[k] = dst
+		}
+		for sku, n := range v.Facings {
+			dst.Facings[sku] += n
+		}
+	}
+}
+
+// groupKey identifies the group of an aggregate independently of dimension order.
+func (a *Aggregate) groupKey() string {
+	names := make([]string, 0, len(a.Key))
+	for name := range a.Key {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s=%q;", name, a.Key[name])
+	}
+	if a.Bucket != nil {
+		b.WriteString(a.Bucket.UTC().Format(time.RFC3339))
+	}
+	return b.String()
+}
+
+// Aggregator rolls up decoded reports into Aggregates grouped by dimensions and
+// time bucket. An Aggregator is not safe for concurrent use: give every worker
+// its own and combine them with Merge.
+type Aggregator struct {
+	opts   AggregateOptions
+	groups map[string]*Aggregate
+}
+
+// NewAggregator makes an Aggregator.
+// Returns ErrAggregateOptions when Bucket or FacingsSource is not one of the supported values.
+func NewAggregator(opts *AggregateOptions) (*Aggregator, error) {
+	a := &Aggregator{groups: map[string]*Aggregate{}}
+	if opts != nil {
+		a.opts = *opts
+	}
+	switch a.opts.Bucket {
+	case AggregateBucketNONE, AggregateBucketDAY, AggregateBucketWEEK, AggregateBucketMONTH:
+	default:
+		return nil, fmt.Errorf("unknown bucket %q:%w", a.opts.Bucket, ErrAggregateOptions)
+	}
+	switch a.opts.FacingsSource {
+	case "":
+		a.opts.FacingsSource = ReportTypeFACING_COUNT
+	case ReportTypeFACING_COUNT, ReportTypeREALOGRAM:
+	default:
+		return nil, fmt.Errorf("unknown facings source %q:%w", a.opts.FacingsSource, ErrAggregateOptions)
+	}
+	if a.opts.Location == nil {
+		a.opts.Location = time.UTC
+	}
+	return a, nil
+}
+
+// Add aggregates one report.
+func (a *Aggregator) Add(in AggregateInput) error {
+	if in.Report == nil {
+		return fmt.Errorf("report is required:%w", ErrAggregateInput)
+	}
+	decoded := in.Decoded
+	if decoded == nil {
+		var srv ReportService
+		var err error
+		if decoded, err = srv.DecodeReport(in.Report); err != nil {
+			return fmt.Errorf("failed to decode report %d:%w", in.Report.ID, err)
+		}
+	}
+
+	key := make(map[string]string, len(a.opts.Dimensions))
+	for _, d := range a.opts.Dimensions {
+		key[d.Name] = d.Key(&in)
+	}
+	part := newAggregate(key, a.bucket(in))
+	part.Reports = 1
+
+	var facings map[int]int
+	switch v := decoded.(type) {
+	case []ReportFacingCountJson:
+		if a.opts.FacingsSource == ReportTypeFACING_COUNT {
+			facings = FacingsFromCounts(v)
+		}
+	case []ReportRealogramJson:
+		if a.opts.FacingsSource == ReportTypeREALOGRAM {
+			facings = FacingsFromRealograms(v, nil)
+		}
+	case []ReportPriceTagsJson:
+		for _, t := range v {
+			s, ok := part.Prices[t.SkuId]
+			if !ok {
+				s = &PriceStats{}
+				part.Prices[t.SkuId] = s
+			}
+			s.add(PriceStats{Count: 1, Sum: t.Price, Min: t.Price, Max: t.Price})
+		}
+	default:
+		return fmt.Errorf("report %d of type %s decoded as %T:%w", in.Report.ID, in.Report.ReportType, decoded, ErrAggregateInput)
+	}
+
+	if facings != nil {
+		for sku, n := range facings {
+			part.Facings[sku] += n
+			if a.opts.SKUs != nil {
+				brand := 0
+				if s, ok := a.opts.SKUs.LookupSKU(sku); ok {
+					brand = derefOrZero(s.Brand)
+				}
+				part.BrandFacings[brand] += n
+			}
+		}
+		if a.opts.MHL != nil {
+			if err := a.addMHLVisit(part, &in, facings); err != nil {
+				return err
+			}
+		}
+	}
+
+	a.AddAggregate(part)
+	return nil
+}
+
+// addMHLVisit adds facings to the must-have visit of in. Reports without a shop
+// or of a chain missing from the list are counted in MHLSkipped.
+func (a *Aggregator) addMHLVisit(part *Aggregate, in *AggregateInput, facings map[int]int) error {
+	if in.Shop == nil {
+		part.MHLSkipped++
+		return nil
+	}
+	if _, err := a.opts.MHL.Items(in.Shop.RetailChain, in.Shop.ExternalCode); err != nil {
+		if errors.Is(err, ErrMHLChainNotFound) {
+			part.MHLSkipped++
+			return nil
+		}
+		return err
+	}
+	v := &MHLVisit{RetailChain: in.Shop.RetailChain, Store: in.Shop.ExternalCode, Facings: map[int]int{}}
+	for sku, n := range facings {
+		v.Facings[sku] += n
+	}
+	part.MHLVisits[a.mhlVisitKey(in)] = v
+	return nil
+}
+
+// mhlVisitKey identifies the visit of in: the visit ID, or the shop and day
+// for reports without a visit.
+func (a *Aggregator) mhlVisitKey(in *AggregateInput) string {
+	switch {
+	case in.Visit != nil && in.Visit.ID != 0:
+		return "visit:" + strconv.Itoa(in.Visit.ID)
+	case in.Report.Visit != 0:
+		return "visit:" + strconv.Itoa(in.Report.Visit)
+	}
+	day := aggregateTime(in).In(a.opts.Location).Format(time.DateOnly)
+	return fmt.Sprintf("shop:%d:%s:%s", in.Shop.ID, in.Shop.ExternalCode, day)
+}
+
+// checkMHL recomputes the must-have counts of g from its visits.
+func (a *Aggregator) checkMHL(g *Aggregate) {
+	g.MHLRequired, g.MHLAvailable, g.MHLCompliant = 0, 0, 0
+	for _, v := range g.MHLVisits {
+		res, err := a.opts.MHL.Check(v.RetailChain, v.Store, v.Facings)
+		if err != nil {
+			continue // chains not in the list are counted in MHLSkipped by Add
+		}
+		g.MHLRequired += len(res.Items)
+		g.MHLAvailable += res.Present + res.UnderFaced
+		g.MHLCompliant += res.Present
+	}
+}
+
+// AddAggregate merges a partial aggregate, e.g. one decoded from another process.
+func (a *Aggregator) AddAggregate(agg *Aggregate) {
+	k := agg.groupKey()
+	g, ok := a.groups[k]
+	if !ok {
+		g = newAggregate(agg.Key, agg.Bucket)
+		a.groups[k] = g
+	}
+	g.Merge(agg)
+}
+
+// Merge adds every aggregate of o.
+func (a *Aggregator) Merge(o *Aggregator) {
+	for _, agg := range o.groups {
+		a.AddAggregate(agg)
+	}
+}
+
+// Results returns the aggregates ordered by bucket and key. With a must-have
+// list, the must-have counts are computed per visit from the merged facings.
+func (a *Aggregator) Results() []*Aggregate {
+	out := make([]*Aggregate, 0, len(a.groups))
+	for _, g := range a.groups {
+		if a.opts.MHL != nil {
+			a.checkMHL(g)
+		}
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		bi, bj := out[i].bucketStart(), out[j].bucketStart()
+		if !bi.Equal(bj) {
+			return bi.Before(bj)
+		}
+		return out[i].groupKey() < out[j].groupKey()
+	})
+	return out
+}
+
+// aggregateTime returns the time of in, see AggregateInput.Time.
+func aggregateTime(in *AggregateInput) time.Time {
+	t := in.Time
+	if t.IsZero() && in.Visit != nil {
+		t = in.Visit.StartedDate
+	}
+	if t.IsZero() {
+		t = in.Report.CreatedDate
+	}
+	return t
+}
+
+// bucketStart returns the bucket start, or the zero time without a bucket.
+func (a *Aggregate) bucketStart() time.Time {
+	if a.Bucket == nil {
+		return time.Time{}
+	}
+	return *a.Bucket
+}
+
+// bucket returns the start of the time bucket of in, or nil for AggregateBucketNONE.
+func (a *Aggregator) bucket(in AggregateInput) *time.Time {
+	if a.opts.Bucket == AggregateBucketNONE {
+		return nil
+	}
+	t := aggregateTime(&in).In(a.opts.Location)
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, a.opts.Location)
+	switch a.opts.Bucket {
+	case AggregateBucketWEEK:
+		start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+	case AggregateBucketMONTH:
+		start = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, a.opts.Location)
+	}
+	return &start
+}
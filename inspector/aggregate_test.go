@@ -0,0 +1,259 @@
+package inspector
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func aggregateTestInputs() []AggregateInput {
+	magnit := &Shop{ID: 10, RetailChain: "magnit", ExternalCode: "MAG-012"}
+	x5 := &Shop{ID: 20, RetailChain: "x5"}
+	mon := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	wed := mon.AddDate(0, 0, 2)
+	next := mon.AddDate(0, 0, 7)
+	return []AggregateInput{
+		{
+			Report:  &Report{ID: 1, ReportType: ReportTypeFACING_COUNT, Visit: 100},
+			Decoded: []ReportFacingCountJson{{SkuId: 1, Count: 2}, {SkuId: 2, Count: 3}},
+			Shop:    magnit, Time: mon,
+		},
+		{
+			Report:  &Report{ID: 2, ReportType: ReportTypePRICE_TAGS, Visit: 100},
+			Decoded: []ReportPriceTagsJson{{SkuId: 1, Price: 100}, {SkuId: 2, Price: 50}},
+			Shop:    magnit, Time: mon,
+		},
+		{
+			Report:  &Report{ID: 3, ReportType: ReportTypeFACING_COUNT, Visit: 101},
+			Decoded: []ReportFacingCountJson{{SkuId: 1, Count: 1}},
+			Visit:   &Visit{ID: 101, StartedDate: wed}, Shop: magnit,
+		},
+		{
+			Report:  &Report{ID: 4, ReportType: ReportTypePRICE_TAGS, Visit: 101, CreatedDate: wed},
+			Decoded: []ReportPriceTagsJson{{SkuId: 1, Price: 120}},
+			Shop:    magnit,
+		},
+		{
+			Report:  &Report{ID: 5, ReportType: ReportTypeFACING_COUNT, Visit: 102},
+			Decoded: []ReportFacingCountJson{{SkuId: 3, Count: 4}},
+			Shop:    x5, Time: next,
+		},
+	}
+}
+
+func TestAggregator_Add(t *testing.T) {
+	mhl, err := DecodeMustHaveList(strings.NewReader(mhlTestYAML), PlanogramFormatYAML)
+	assert.NoError(t, err)
+	brand := 7
+	opts := &AggregateOptions{
+		Dimensions: []AggregateDimension{DimensionRetailChain},
+		Bucket:     AggregateBucketWEEK,
+		SKUs:       SkuIndex{1: {ID: 1, Brand: &brand}, 2: {ID: 2, Brand: &brand}},
+		MHL:        mhl,
+	}
+
+	agg, err := NewAggregator(opts)
+	assert.NoError(t, err)
+	for _, in := range aggregateTestInputs() {
+		assert.NoError(t, agg.Add(in))
+	}
+	res := agg.Results()
+	assert.Len(t, res, 2)
+
+	week := res[0]
+	assert.Equal(t, map[string]string{"retail_chain": "magnit"}, week.Key)
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), *week.Bucket)
+	assert.Equal(t, 4, week.Reports)
+	assert.Equal(t, map[int]int{1: 3, 2: 3}, week.Facings)
+	assert.Equal(t, map[int]int{7: 6}, week.BrandFacings)
+	assert.Equal(t, 110.0, week.AveragePrice(1))
+	assert.Equal(t, PriceStats{Count: 2, Sum: 220, Min: 100, Max: 120}, *week.Prices[1])
+	// MAG-012 requires 1 (x2), 2 (x3) and 4: two facing reports check 3 items each
+	assert.Equal(t, 6, week.MHLRequired)
+	assert.Equal(t, 3, week.MHLAvailable)
+	assert.Equal(t, 2, week.MHLCompliant)
+	assert.Equal(t, 50.0, week.OSA())
+
+	x5 := res[1]
+	assert.Equal(t, map[string]string{"retail_chain": "x5"}, x5.Key)
+	assert.Equal(t, time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), *x5.Bucket)
+	assert.Equal(t, map[int]int{3: 4}, x5.Facings)
+	assert.Equal(t, map[int]int{0: 4}, x5.BrandFacings)
+	assert.Equal(t, 1, x5.MHLRequired)
+	assert.Equal(t, 0.0, x5.OSA())
+
+	t.Run("invalid input", func(t *testing.T) {
+		a, err := NewAggregator(nil)
+		assert.NoError(t, err)
+		assert.ErrorIs(t, a.Add(AggregateInput{}), ErrAggregateInput)
+		assert.ErrorIs(t, a.Add(AggregateInput{Report: &Report{ReportType: "OTHER", Json: "x"}}), ErrAggregateInput)
+	})
+
+	t.Run("realogram facings", func(t *testing.T) {
+		realograms := loadRealogramReport(t)
+		a, err := NewAggregator(&AggregateOptions{FacingsSource: ReportTypeREALOGRAM})
+		assert.NoError(t, err)
+		assert.NoError(t, a.Add(AggregateInput{Report: &Report{ReportType: ReportTypeREALOGRAM}, Decoded: realograms}))
+		assert.NoError(t, a.Add(AggregateInput{Report: &Report{ReportType: ReportTypeFACING_COUNT}, Decoded: []ReportFacingCountJson{{SkuId: 1, Count: 9}}}))
+		res := a.Results()
+		assert.Len(t, res, 1)
+		assert.Equal(t, FacingsFromRealograms(realograms, nil), res[0].Facings)
+		assert.Equal(t, 2, res[0].Reports)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := NewAggregator(&AggregateOptions{Bucket: "weekly"})
+		assert.ErrorIs(t, err, ErrAggregateOptions)
+		_, err = NewAggregator(&AggregateOptions{FacingsSource: ReportTypePRICE_TAGS})
+		assert.ErrorIs(t, err, ErrAggregateOptions)
+	})
+}
+
+func TestAggregator_Bucket(t *testing.T) {
+	at := time.Date(2026, 10, 18, 23, 30, 0, 0, time.UTC) // Sunday
+	msk := time.FixedZone("MSK", 3*3600)
+	tests := []struct {
+		bucket string
+		loc    *time.Location
+		want   time.Time
+	}{
+		{AggregateBucketDAY, nil, time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{AggregateBucketDAY, msk, time.Date(2026, 10, 19, 0, 0, 0, 0, msk)},
+		{AggregateBucketWEEK, nil, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)},
+		{AggregateBucketWEEK, msk, time.Date(2026, 10, 19, 0, 0, 0, 0, msk)},
+		{AggregateBucketMONTH, nil, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		a, err := NewAggregator(&AggregateOptions{Bucket: tt.bucket, Location: tt.loc})
+		assert.NoError(t, err)
+		got := a.bucket(AggregateInput{Report: &Report{CreatedDate: at}})
+		assert.True(t, tt.want.Equal(*got), "%s in %v: got %v", tt.bucket, tt.loc, got)
+	}
+
+	a, err := NewAggregator(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, a.bucket(AggregateInput{Report: &Report{CreatedDate: at}}))
+	assert.NoError(t, a.Add(AggregateInput{Report: &Report{CreatedDate: at}, Decoded: []ReportFacingCountJson{}}))
+	b, err := json.Marshal(a.Results()[0])
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), `"bucket"`)
+}
+
+func TestAggregator_Merge(t *testing.T) {
+	opts := &AggregateOptions{Dimensions: []AggregateDimension{DimensionShop, DimensionVisit}, Bucket: AggregateBucketDAY}
+	inputs := aggregateTestInputs()
+
+	whole, err := NewAggregator(opts)
+	assert.NoError(t, err)
+	for _, in := range inputs {
+		assert.NoError(t, whole.Add(in))
+	}
+
+	// one partial aggregator per worker, merged at the end
+	parts := make([]*Aggregator, 3)
+	var wg sync.WaitGroup
+	for w := range parts {
+		parts[w], err = NewAggregator(opts)
+		assert.NoError(t, err)
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(inputs); i += len(parts) {
+				assert.NoError(t, parts[w].Add(inputs[i]))
+			}
+		}(w)
+	}
+	wg.Wait()
+	merged, err := NewAggregator(opts)
+	assert.NoError(t, err)
+	for _, p := range parts {
+		merged.Merge(p)
+	}
+	assert.Equal(t, whole.Results(), merged.Results())
+
+	t.Run("json round trip", func(t *testing.T) {
+		b, err := json.Marshal(parts[0].Results())
+		assert.NoError(t, err)
+		var decoded []*Aggregate
+		assert.NoError(t, json.Unmarshal(b, &decoded))
+
+		remote, err := NewAggregator(opts)
+		assert.NoError(t, err)
+		for _, agg := range decoded {
+			remote.AddAggregate(agg)
+		}
+		remote.Merge(parts[1])
+		remote.Merge(parts[2])
+		got := remote.Results()
+		want := whole.Results()
+		assert.Len(t, got, len(want))
+		for i := range want {
+			assert.Equal(t, want[i].Key, got[i].Key)
+			assert.True(t, want[i].Bucket.Equal(*got[i].Bucket))
+			assert.Equal(t, want[i].Facings, got[i].Facings)
+			assert.Equal(t, want[i].Prices, got[i].Prices)
+		}
+	})
+}
+
+func TestAggregator_MHLByVisit(t *testing.T) {
+	mhl, err := DecodeMustHaveList(strings.NewReader(mhlTestYAML), PlanogramFormatYAML)
+	assert.NoError(t, err)
+	magnit := &Shop{ID: 10, RetailChain: "magnit", ExternalCode: "MAG-012"}
+	day := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	// MAG-012 requires 1 (x2), 2 (x3) and 4, spread over two displays of visit 100
+	inputs := []AggregateInput{
+		{Report: &Report{ID: 1, ReportType: ReportTypeFACING_COUNT, Visit: 100}, Decoded: []ReportFacingCountJson{{SkuId: 1, Count: 2}}, Shop: magnit, Time: day},
+		{Report: &Report{ID: 2, ReportType: ReportTypeFACING_COUNT, Visit: 100}, Decoded: []ReportFacingCountJson{{SkuId: 2, Count: 3}, {SkuId: 4, Count: 1}}, Shop: magnit, Time: day},
+		// no visit: both reports belong to the shop visit of the day
+		{Report: &Report{ID: 3, ReportType: ReportTypeFACING_COUNT}, Decoded: []ReportFacingCountJson{{SkuId: 1, Count: 1}}, Shop: magnit, Time: day.AddDate(0, 0, 1)},
+		{Report: &Report{ID: 4, ReportType: ReportTypeFACING_COUNT}, Decoded: []ReportFacingCountJson{{SkuId: 1, Count: 1}}, Shop: magnit, Time: day.AddDate(0, 0, 1)},
+		// not checked
+		{Report: &Report{ID: 5, ReportType: ReportTypeFACING_COUNT, Visit: 101}, Decoded: []ReportFacingCountJson{{SkuId: 1, Count: 5}}},
+		{Report: &Report{ID: 6, ReportType: ReportTypeFACING_COUNT, Visit: 102}, Decoded: []ReportFacingCountJson{{SkuId: 1, Count: 5}}, Shop: &Shop{RetailChain: "lenta"}},
+	}
+	opts := &AggregateOptions{MHL: mhl}
+
+	whole, err := NewAggregator(opts)
+	assert.NoError(t, err)
+	for _, in := range inputs {
+		assert.NoError(t, whole.Add(in))
+	}
+	res := whole.Results()
+	assert.Len(t, res, 1)
+	assert.Len(t, res[0].MHLVisits, 2)
+	assert.Equal(t, 6, res[0].MHLRequired)
+	assert.Equal(t, 4, res[0].MHLAvailable) // visit 100: all three, next day: SKU 1
+	assert.Equal(t, 4, res[0].MHLCompliant)
+	assert.Equal(t, 2, res[0].MHLSkipped)
+
+	// the displays of a visit end up with different workers
+	parts := make([]*Aggregator, 2)
+	for w := range parts {
+		parts[w], err = NewAggregator(opts)
+		assert.NoError(t, err)
+		for i := w; i < len(inputs); i += len(parts) {
+			assert.NoError(t, parts[w].Add(inputs[i]))
+		}
+	}
+	b, err := json.Marshal(parts[1].Results())
+	assert.NoError(t, err)
+	var decoded []*Aggregate
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	merged, err := NewAggregator(opts)
+	assert.NoError(t, err)
+	merged.Merge(parts[0])
+	for _, agg := range decoded {
+		merged.AddAggregate(agg)
+	}
+	got := merged.Results()
+	assert.Len(t, got, 1)
+	assert.Equal(t, res[0].MHLRequired, got[0].MHLRequired)
+	assert.Equal(t, res[0].MHLAvailable, got[0].MHLAvailable)
+	assert.Equal(t, res[0].MHLCompliant, got[0].MHLCompliant)
+	assert.Equal(t, res[0].MHLSkipped, got[0].MHLSkipped)
+}
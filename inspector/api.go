@@ -0,0 +1,159 @@
+package inspector
+
+import (
+	"context"
+	"image"
+	"io"
+)
+
+// The service interfaces below let application code depend on behavior instead of
+// the concrete services, so tests can swap them for fakes such as the mocks in
+// package inspectormock. *Client satisfies API.
+
+// ImageAPI is the interface of ImageService: image uploads and downloads.
+type ImageAPI interface {
+	Upload(ctx context.Context, r io.Reader, filename string) (Image, error)
+	UploadByURL(ctx context.Context, url string) (Image, error)
+	GetImage(ctx context.Context, id int) (*Image, error)
+	IterateImages(ctx context.Context, pageSize int) ImageIterator
+	Download(ctx context.Context, img Image) (image.Image, error)
+}
+
+// RecognizeAPI is the interface of RecognizeService: recognition requests.
+type RecognizeAPI interface {
+	Recognize(ctx context.Context, rr RecognizeRequest) (*RecognizeResponse, error)
+	RecognitionError(ctx context.Context, rr *RecognitionErrorRequest) (*RecognitionErrorResponse, error)
+}
+
+// ReportAPI is the interface of ReportService: reports.
+type ReportAPI interface {
+	GetReport(ctx context.Context, id int) (*Report, error)
+	WaitForReport(ctx context.Context, id int, opts *ReportWaitOptions) (*Report, error)
+	ToPriceTags(v any) ([]ReportPriceTagsJson, error)
+	ToFacingCount(v any) ([]ReportFacingCountJson, error)
+	ToRealogram(v any) ([]ReportRealogramJson, error)
+	ParseWebhookReports(b []byte) (*WebhookReports, error)
+	DecodeReport(report *Report) (any, error)
+}
+
+// SkuAPI is the interface of SkuService: the SKU catalog.
+type SkuAPI interface {
+	GetSKU(ctx context.Context, offset, limit int, query ...*SkuQuery) (*Pagination, error)
+	ToSku(v any) ([]Sku, error)
+	IterateSKU(ctx context.Context, pageSize int, query ...*SkuQuery) SKUIterator
+	GetAllSKU(ctx context.Context, pageSize int, query ...*SkuQuery) ([]Sku, error)
+	GetAllSKUParallel(ctx context.Context, opts *ParallelFetchOptions, query ...*SkuQuery) ([]Sku, error)
+	FindByEAN(ctx context.Context, ean13 string) ([]Sku, error)
+	FindByCID(ctx context.Context, cid string) ([]Sku, error)
+	GetSKUByID(ctx context.Context, id int) (*Sku, error)
+	CreateSKU(ctx context.Context, sku Sku) (*Sku, error)
+	UpdateSKU(ctx context.Context, sku Sku) (*Sku, error)
+	PatchSKU(ctx context.Context, id int, patch SkuPatch) (*Sku, error)
+	DeleteSKU(ctx context.Context, id int) error
+	UpsertSKUs(ctx context.Context, desired []Sku, opts *SkuUpsertOptions) (*SkuUpsertReport, error)
+	ExportSKU(ctx context.Context, w io.Writer, opts *SkuExportOptions) (int, error)
+}
+
+// VisitAPI is the interface of VisitService: visits.
+type VisitAPI interface {
+	AddVisit(ctx context.Context) (*Visit, error)
+	CreateVisit(ctx context.Context, vr VisitCreateRequest) (*Visit, error)
+	GetVisit(ctx context.Context, id int) (*Visit, error)
+	UpdateVisit(ctx context.Context, id int, vr VisitUpdateRequest) (*Visit, error)
+	DeleteVisit(ctx context.Context, id int) error
+	IterateVisits(ctx context.Context, pageSize int, query ...*VisitQuery) VisitIterator
+	ListVisits(ctx context.Context, query *VisitQuery) ([]Visit, error)
+	GetVisitReports(ctx context.Context, visitID int) ([]Report, error)
+	GetVisitRecognitions(ctx context.Context, visitID int) ([]RecognizeResponse, error)
+}
+
+// ShopAPI is the interface of ShopService: shops.
+type ShopAPI interface {
+	CreateShop(ctx context.Context, shop Shop) (*Shop, error)
+	GetShop(ctx context.Context, id int) (*Shop, error)
+	UpdateShop(ctx context.Context, shop Shop) (*Shop, error)
+	DeleteShop(ctx context.Context, id int) error
+	IterateShops(ctx context.Context, pageSize int, query ...*ShopQuery) ShopIterator
+	ListShops(ctx context.Context, query *ShopQuery) ([]Shop, error)
+	GetShopByExternalCode(ctx context.Context, code string) (*Shop, error)
+}
+
+// BrandAPI is the interface of BrandService: the brand catalog.
+type BrandAPI interface {
+	GetBrands(ctx context.Context, offset, limit int) (*Pagination, error)
+	ToBrands(v any) ([]Brand, error)
+	IterateBrands(ctx context.Context, pageSize int) BrandIterator
+	GetAllBrands(ctx context.Context, pageSize int) ([]Brand, error)
+	GetBrand(ctx context.Context, id int) (*Brand, error)
+}
+
+// CategoryAPI is the interface of CategoryService: the category catalog.
+type CategoryAPI interface {
+	GetCategories(ctx context.Context, offset, limit int) (*Pagination, error)
+	ToCategories(v any) ([]Category, error)
+	IterateCategories(ctx context.Context, pageSize int) CategoryIterator
+	GetAllCategories(ctx context.Context, pageSize int) ([]Category, error)
+	GetCategory(ctx context.Context, id int) (*Category, error)
+}
+
+// ManufacturerAPI is the interface of ManufacturerService: the manufacturer catalog.
+type ManufacturerAPI interface {
+	GetManufacturers(ctx context.Context, offset, limit int) (*Pagination, error)
+	ToManufacturers(v any) ([]Manufacturer, error)
+	IterateManufacturers(ctx context.Context, pageSize int) ManufacturerIterator
+	GetAllManufacturers(ctx context.Context, pageSize int) ([]Manufacturer, error)
+	GetManufacturer(ctx context.Context, id int) (*Manufacturer, error)
+}
+
+// API is the aggregate interface of Client.
+type API interface {
+	Images() ImageAPI
+	Recognizer() RecognizeAPI
+	Reports() ReportAPI
+	SKUs() SkuAPI
+	Visits() VisitAPI
+	Shops() ShopAPI
+	Brands() BrandAPI
+	Categories() CategoryAPI
+	Manufacturers() ManufacturerAPI
+}
+
+var (
+	_ ImageAPI        = (*ImageService)(nil)
+	_ RecognizeAPI    = (*RecognizeService)(nil)
+	_ ReportAPI       = (*ReportService)(nil)
+	_ SkuAPI          = (*SkuService)(nil)
+	_ VisitAPI        = (*VisitService)(nil)
+	_ ShopAPI         = (*ShopService)(nil)
+	_ BrandAPI        = (*BrandService)(nil)
+	_ CategoryAPI     = (*CategoryService)(nil)
+	_ ManufacturerAPI = (*ManufacturerService)(nil)
+	_ API             = (*Client)(nil)
+)
+
+// Images returns the Image service as ImageAPI.
+func (c *Client) Images() ImageAPI { return c.Image }
+
+// Recognizer returns the Recognize service as RecognizeAPI.
+func (c *Client) Recognizer() RecognizeAPI { return c.Recognize }
+
+// Reports returns the Report service as ReportAPI.
+func (c *Client) Reports() ReportAPI { return c.Report }
+
+// SKUs returns the Sku service as SkuAPI.
+func (c *Client) SKUs() SkuAPI { return c.Sku }
+
+// Visits returns the Visit service as VisitAPI.
+func (c *Client) Visits() VisitAPI { return c.Visit }
+
+// Shops returns the Shop service as ShopAPI.
+func (c *Client) Shops() ShopAPI { return c.Shop }
+
+// Brands returns the Brand service as BrandAPI.
+func (c *Client) Brands() BrandAPI { return c.Brand }
+
+// Categories returns the Category service as CategoryAPI.
+func (c *Client) Categories() CategoryAPI { return c.Category }
+
+// Manufacturers returns the Manufacturer service as ManufacturerAPI.
+func (c *Client) Manufacturers() ManufacturerAPI { return c.Manufacturer }
@@ -0,0 +1,491 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Batch item statuses recorded in the checkpoint
+const (
+	BatchStatusUPLOADED   = "UPLOADED"   // image uploaded, see BatchRecord.ImageID
+	BatchStatusRECOGNIZED = "RECOGNIZED" // recognition started, see BatchRecord.Reports
+	BatchStatusDONE       = "DONE"       // reports decoded and written to the sink
+	BatchStatusFAILED     = "FAILED"     // a stage failed, see BatchRecord.Error; retried on resume
+)
+
+// BatchItem is an entry of a batch manifest: an image URL or a local file path.
+type BatchItem struct {
+	Key  string `json:"key,omitempty"`  // unique item key (default: URL or Path)
+	URL  string `json:"url,omitempty"`  // uploaded with UploadByURL
+	Path string `json:"path,omitempty"` // uploaded with Upload
+}
+
+func (item BatchItem) key() string {
+	switch {
+	case item.Key != "":
+		return item.Key
+	case item.URL != "":
+		return item.URL
+	}
+	return item.Path
+}
+
+// ReadBatchManifest reads a manifest with one item per line: an http(s) URL,
+// a file path, or a JSON BatchItem. Blank lines and lines starting with # are skipped.
+func ReadBatchManifest(r io.Reader) ([]BatchItem, error) {
+	var items []BatchItem
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		s := strings.TrimSpace(sc.Text())
+		switch {
+		case s == "" || strings.HasPrefix(s, "#"):
+			continue
+		case strings.HasPrefix(s, "{"):
+			var item BatchItem
+			if err := json.Unmarshal([]byte(s), &item); err != nil {
+				return nil, fmt.Errorf("failed to decode manifest line %d:%w", line, err)
+			}
+			items = append(items, item)
+		case strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://"):
+			items = append(items, BatchItem{URL: s})
+		default:
+			items = append(items, BatchItem{Path: s})
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read manifest:%w", err)
+	}
+	return items, nil
+}
+
+// BatchRecord is the checkpoint state of one item.
+type BatchRecord struct {
+	Key           string         `json:"key"`
+	Status        string         `json:"status"`
+	ImageID       int            `json:"image_id,omitempty"`
+	RecognitionID int            `json:"recognition_id,omitempty"`
+	Reports       map[string]int `json:"reports,omitempty"` // report IDs by report type
+	Error         string         `json:"error,omitempty"`
+}
+
+// BatchResult is passed to the sink for every item whose reports are ready.
+type BatchResult struct {
+	Item    BatchItem
+	Record  BatchRecord
+	Reports map[string]*Report // ready reports by report type
+	Decoded map[string]any     // decoded report payloads by report type
+}
+
+// BatchDecodeFunc decodes a ready report.
+type BatchDecodeFunc func(report *Report) (any, error)
+
+// BatchSink receives finished items. An item is checkpointed as done only after the sink returns nil.
+type BatchSink func(ctx context.Context, res *BatchResult) error
+
+// BatchOptions configures a BatchPipeline.
+type BatchOptions struct {
+	Request              RecognizeRequest   // template of every recognition; Images is set per item
+	Checkpoint           string             // checkpoint file path; empty disables resuming
+	UploadConcurrency    int                // default: DefaultBatchConcurrency
+	RecognizeConcurrency int                // default: DefaultBatchConcurrency
+	WaitConcurrency      int                // default: DefaultBatchConcurrency
+	DecodeConcurrency    int                // default: DefaultBatchConcurrency
+	SinkConcurrency      int                // default: 1, so sinks need not be goroutine-safe
+	Wait                 *ReportWaitOptions // report polling options, see WaitForReport
+	Decode               BatchDecodeFunc    // default: typed decoding by report type, see DecodeReport
+	Sink                 BatchSink          // optional
+}
+
+// BatchSummary represents the outcome of BatchPipeline.Run.
+type BatchSummary struct {
+	Total    int              // items in the manifest
+	Skipped  int              // items already done in the checkpoint
+	Done     int              // items finished by this run
+	Failed   int              // items failed by this run
+	Failures map[string]error // errors by item key
+}
+
+// BatchPipeline runs upload → recognize → wait → decode → sink over a manifest,
+// recording progress in a checkpoint so an interrupted batch resumes where it stopped.
+type BatchPipeline struct {
+	client API
+	opts   BatchOptions
+
+	mu      sync.Mutex
+	f       *os.File
+	records map[string]BatchRecord
+	summary *BatchSummary
+}
+
+// NewBatchPipeline makes a new BatchPipeline using the image, recognize and report services of c.
+func NewBatchPipeline(c API, opts *BatchOptions) *BatchPipeline {
+	var o BatchOptions
+	if opts != nil {
+		o = *opts
+	}
+	if len(o.Request.ReportTypes) == 0 {
+		o.Request.ReportTypes = []string{ReportTypeFACING_COUNT}
+	}
+	for _, n := range []*int{&o.UploadConcurrency, &o.RecognizeConcurrency, &o.WaitConcurrency, &o.DecodeConcurrency} {
+		if *n <= 0 {
+			*n = DefaultBatchConcurrency
+		}
+	}
+	if o.SinkConcurrency <= 0 {
+		o.SinkConcurrency = 1
+	}
+	p := &BatchPipeline{client: c, opts: o}
+	if p.opts.Decode == nil {
+		p.opts.Decode = p.client.Reports().DecodeReport
+	}
+	return p
+}
+
+// DecodeReport decodes Report.Json by report type: FACING_COUNT, PRICE_TAGS and
+// REALOGRAM into their typed slices, anything else is returned as is.
+func (srv *ReportService) DecodeReport(report *Report) (any, error) {
+	switch report.ReportType {
+	case ReportTypeFACING_COUNT:
+		return srv.ToFacingCount(report.Json)
+	case ReportTypePRICE_TAGS:
+		return srv.ToPriceTags(report.Json)
+	case ReportTypeREALOGRAM:
+		return srv.ToRealogram(report.Json)
+	}
+	return report.Json, nil
+}
+
+// LoadBatchCheckpoint reads the latest record of every item from a checkpoint file.
+// A torn last line left by a crash is ignored.
+func LoadBatchCheckpoint(path string) (map[string]BatchRecord, error) {
+	records := make(map[string]BatchRecord)
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint %s:%w", path, err)
+	}
+
+	lines := bytes.Split(b, []byte("\n"))
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var rec BatchRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			if i == len(lines)-1 {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode checkpoint %s line %d:%w", path, i+1, err)
+		}
+		records[rec.Key] = rec
+	}
+	return records, nil
+}
+
+// batchTask carries an item through the stages.
+type batchTask struct {
+	item    BatchItem
+	rec     BatchRecord
+	reports map[string]*Report
+	decoded map[string]any
+}
+
+// Run processes the items. Items marked done in the checkpoint are skipped; other
+// checkpointed items continue from their last finished stage. Item failures are
+// reported in the summary; an error is returned for an invalid manifest, checkpoint
+// problems or when ctx is done.
+func (p *BatchPipeline) Run(ctx context.Context, items []BatchItem) (*BatchSummary, error) {
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		k := item.key()
+		if k == "" {
+			return nil, fmt.Errorf("failed to run batch: item %d has no URL or Path", i)
+		}
+		if seen[k] {
+			return nil, fmt.Errorf("failed to run batch: duplicate item %q", k)
+		}
+		seen[k] = true
+	}
+
+	if err := p.openCheckpoint(); err != nil {
+		return nil, err
+	}
+	defer p.closeCheckpoint()
+
+	p.summary = &BatchSummary{Total: len(items), Failures: make(map[string]error)}
+
+	// workers update p.records under p.mu, so the feeder works on a copy
+	resume := make(map[string]BatchRecord, len(p.records))
+	for k, rec := range p.records {
+		resume[k] = rec
+	}
+
+	tasks := make(chan *batchTask)
+	go func() {
+		defer close(tasks)
+		for _, item := range items {
+			rec, ok := resume[item.key()]
+			if ok && rec.Status == BatchStatusDONE {
+				p.mu.Lock()
+				p.summary.Skipped++
+				p.mu.Unlock()
+				continue
+			}
+			if !ok {
+				rec = BatchRecord{Key: item.key()}
+			}
+			select {
+			case tasks <- &batchTask{item: item, rec: rec}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	uploaded := batchStage(ctx, tasks, p.opts.UploadConcurrency, p.upload)
+	recognized := batchStage(ctx, uploaded, p.opts.RecognizeConcurrency, p.recognize)
+	ready := batchStage(ctx, recognized, p.opts.WaitConcurrency, p.wait)
+	decoded := batchStage(ctx, ready, p.opts.DecodeConcurrency, p.decode)
+	done := batchStage(ctx, decoded, p.opts.SinkConcurrency, p.sink)
+	for range done {
+	}
+
+	if err := ctx.Err(); err != nil {
+		return p.summary, fmt.Errorf("failed to run batch:%w", err)
+	}
+	return p.summary, nil
+}
+
+// batchStage runs fn on n workers; tasks for which fn returns true are passed on.
+func batchStage(ctx context.Context, in <-chan *batchTask, n int, fn func(context.Context, *batchTask) bool) <-chan *batchTask {
+	out := make(chan *batchTask)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range in {
+				if ctx.Err() != nil || !fn(ctx, t) {
+					continue
+				}
+				select {
+				case out <- t:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+func (p *BatchPipeline) upload(ctx context.Context, t *batchTask) bool {
+	if t.rec.ImageID != 0 {
+		return true
+	}
+
+	var (
+		img Image
+		err error
+	)
+	if t.item.URL != "" {
+		img, err = p.client.Images().UploadByURL(ctx, t.item.URL)
+	} else {
+		var f *os.File
+		f, err = os.Open(t.item.Path)
+		if err == nil {
+			img, err = p.client.Images().Upload(ctx, f, filepath.Base(t.item.Path))
+			f.Close()
+		}
+	}
+	if err != nil {
+		return p.fail(t, err)
+	}
+
+	t.rec.ImageID = img.ID
+	return p.checkpoint(t, BatchStatusUPLOADED)
+}
+
+func (p *BatchPipeline) recognize(ctx context.Context, t *batchTask) bool {
+	if t.rec.RecognitionID != 0 {
+		return true
+	}
+
+	rr := p.opts.Request
+	rr.Images = []int{t.rec.ImageID}
+	rec, err := p.client.Recognizer().Recognize(ctx, rr)
+	if err != nil {
+		return p.fail(t, err)
+	}
+
+	t.rec.RecognitionID = rec.ID
+	t.rec.Reports = rec.Reports
+	return p.checkpoint(t, BatchStatusRECOGNIZED)
+}
+
+func (p *BatchPipeline) wait(ctx context.Context, t *batchTask) bool {
+	t.reports = make(map[string]*Report, len(t.rec.Reports))
+	for reportType, id := range t.rec.Reports {
+		report, err := p.client.Reports().WaitForReport(ctx, id, p.opts.Wait)
+		if err != nil {
+			if errors.Is(err, ErrReportFailed) {
+				// polling the failed report again never succeeds, so a resume recognizes anew
+				t.rec.RecognitionID = 0
+				t.rec.Reports = nil
+			}
+			return p.fail(t, fmt.Errorf("report %s:%w", reportType, err))
+		}
+		t.reports[reportType] = report
+	}
+	return true
+}
+
+func (p *BatchPipeline) decode(ctx context.Context, t *batchTask) bool {
+	t.decoded = make(map[string]any, len(t.reports))
+	for reportType, report := range t.reports {
+		v, err := p.opts.Decode(report)
+		if err != nil {
+			return p.fail(t, fmt.Errorf("failed to decode report %s:%w", reportType, err))
+		}
+		t.decoded[reportType] = v
+	}
+	return true
+}
+
+func (p *BatchPipeline) sink(ctx context.Context, t *batchTask) bool {
+	res := &BatchResult{
+		Item:    t.item,
+		Record:  t.rec,
+		Reports: t.reports,
+		Decoded: t.decoded,
+	}
+
+	if p.opts.Sink != nil {
+		if err := p.opts.Sink(ctx, res); err != nil {
+			return p.fail(t, fmt.Errorf("failed to sink:%w", err))
+		}
+	}
+
+	t.rec.Error = ""
+	if !p.checkpoint(t, BatchStatusDONE) {
+		return false
+	}
+	p.mu.Lock()
+	p.summary.Done++
+	p.mu.Unlock()
+	return true
+}
+
+// fail records the failure in the checkpoint and the summary, keeping the IDs
+// reached so far so a resumed run continues from there.
+func (p *BatchPipeline) fail(t *batchTask, err error) bool {
+	t.rec.Error = err.Error()
+	werr := p.write(t.rec, BatchStatusFAILED)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.summary.Failed++
+	p.summary.Failures[t.rec.Key] = errors.Join(err, werr)
+	return false
+}
+
+func (p *BatchPipeline) checkpoint(t *batchTask, status string) bool {
+	t.rec.Status = status
+	if err := p.write(t.rec, status); err != nil {
+		return p.fail(t, err)
+	}
+	return true
+}
+
+func (p *BatchPipeline) write(rec BatchRecord, status string) error {
+	rec.Status = status
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.records[rec.Key] = rec
+	if p.f == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint %q:%w", rec.Key, err)
+	}
+	if _, err := p.f.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("failed to write checkpoint %q:%w", rec.Key, err)
+	}
+	if err := p.f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync checkpoint %q:%w", rec.Key, err)
+	}
+	return nil
+}
+
+func (p *BatchPipeline) openCheckpoint() error {
+	p.records = make(map[string]BatchRecord)
+	if p.opts.Checkpoint == "" {
+		return nil
+	}
+
+	records, err := LoadBatchCheckpoint(p.opts.Checkpoint)
+	if err != nil {
+		return err
+	}
+	p.records = records
+
+	// rewrite the checkpoint with one line per item, dropping a torn last line
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, rec := range records {
+		if err := enc.Encode(rec); err != nil {
+			return fmt.Errorf("failed to encode checkpoint %q:%w", rec.Key, err)
+		}
+	}
+	// the new file is synced before it replaces the old one, so a crash leaves either complete
+	tmp := p.opts.Checkpoint + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write checkpoint %s:%w", tmp, err)
+	}
+	_, err = f.Write(buf.Bytes())
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write checkpoint %s:%w", tmp, err)
+	}
+	if err := os.Rename(tmp, p.opts.Checkpoint); err != nil {
+		return fmt.Errorf("failed to replace checkpoint %s:%w", p.opts.Checkpoint, err)
+	}
+
+	p.f, err = os.OpenFile(p.opts.Checkpoint, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open checkpoint %s:%w", p.opts.Checkpoint, err)
+	}
+	return nil
+}
+
+func (p *BatchPipeline) closeCheckpoint() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.f != nil {
+		p.f.Close()
+		p.f = nil
+	}
+}
@@ -0,0 +1,305 @@
+package inspector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadBatchManifest(t *testing.T) {
+	items, err := ReadBatchManifest(strings.NewReader(`
+# archived photos
+https://example.com/a.jpg
+/data/b.jpg
+{"key":"c","url":"https://example.com/c.jpg"}
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []BatchItem{
+		{URL: "https://example.com/a.jpg"},
+		{Path: "/data/b.jpg"},
+		{Key: "c", URL: "https://example.com/c.jpg"},
+	}, items)
+
+	_, err = ReadBatchManifest(strings.NewReader(`{"url":`))
+	assert.Error(t, err)
+}
+
+func TestBatchPipeline_Run(t *testing.T) {
+	var uploads, recognitions int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+endpointUploads, func(w http.ResponseWriter, r *http.Request) {
+		id := atomic.AddInt32(&uploads, 1)
+		fmt.Fprintf(w, `{"id":%d}`, id)
+	})
+	mux.HandleFunc("/"+endpointUploadsByURL, func(w http.ResponseWriter, r *http.Request) {
+		id := atomic.AddInt32(&uploads, 1)
+		fmt.Fprintf(w, `{"id":%d}`, id)
+	})
+	mux.HandleFunc("/"+endpointRecognize, func(w http.ResponseWriter, r *http.Request) {
+		var rr RecognizeRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&rr))
+		assert.Equal(t, "RU", rr.CountryCode)
+		assert.Len(t, rr.Images, 1)
+		atomic.AddInt32(&recognitions, 1)
+		fmt.Fprintf(w, `{"id":%d,"images":[%d],"reports":{"FACING_COUNT":%d}}`, rr.Images[0], rr.Images[0], rr.Images[0]*10)
+	})
+	mux.HandleFunc("/reports/", func(w http.ResponseWriter, r *http.Request) {
+		var id int
+		fmt.Sscanf(r.URL.Path, "/reports/%d/", &id)
+		fmt.Fprintf(w, `{"id":%d,"status":"READY","report_type":"FACING_COUNT","json":[{"sku_id":%d,"count":3}]}`, id, id)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: ""})
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	photo := filepath.Join(dir, "shelf.jpg")
+	assert.NoError(t, os.WriteFile(photo, []byte("jpeg"), 0o600))
+	items := []BatchItem{
+		{URL: "https://example.com/a.jpg"},
+		{Key: "b", URL: "https://example.com/b.jpg"},
+		{Path: photo},
+		{Path: filepath.Join(dir, "missing.jpg")},
+	}
+
+	var (
+		mu     sync.Mutex
+		sunk   []string
+		failB  = true
+		checkp = filepath.Join(dir, "batch.checkpoint")
+	)
+	opts := &BatchOptions{
+		Request:    RecognizeRequest{CountryCode: "RU"},
+		Checkpoint: checkp,
+		Wait:       &ReportWaitOptions{Interval: time.Millisecond, Timeout: time.Second},
+		Sink: func(ctx context.Context, res *BatchResult) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if res.Record.Key == "b" && failB {
+				return errors.New("disk full")
+			}
+			fc, ok := res.Decoded[ReportTypeFACING_COUNT].([]ReportFacingCountJson)
+			assert.True(t, ok)
+			assert.Equal(t, 3, fc[0].Count)
+			sunk = append(sunk, res.Record.Key)
+			return nil
+		},
+	}
+
+	summary, err := NewBatchPipeline(client, opts).Run(context.Background(), items)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, summary.Total)
+	assert.Equal(t, 2, summary.Done)
+	assert.Equal(t, 2, summary.Failed)
+	assert.Contains(t, summary.Failures["b"].Error(), "disk full")
+	assert.Error(t, summary.Failures[filepath.Join(dir, "missing.jpg")])
+	assert.ElementsMatch(t, []string{"https://example.com/a.jpg", photo}, sunk)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&uploads))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&recognitions))
+
+	records, err := LoadBatchCheckpoint(checkp)
+	assert.NoError(t, err)
+	assert.Equal(t, BatchStatusDONE, records["https://example.com/a.jpg"].Status)
+	assert.Equal(t, BatchStatusFAILED, records["b"].Status)
+	assert.NotZero(t, records["b"].RecognitionID)
+	assert.NotEmpty(t, records["b"].Reports)
+
+	// resume: only the failed items run again, b continues from its recognition
+	failB = false
+	sunk = nil
+	summary, err = NewBatchPipeline(client, opts).Run(context.Background(), items[:3])
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.Skipped)
+	assert.Equal(t, 1, summary.Done)
+	assert.Equal(t, 0, summary.Failed)
+	assert.Equal(t, []string{"b"}, sunk)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&uploads))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&recognitions))
+}
+
+func TestBatchPipeline_RunMany(t *testing.T) {
+	var nextID int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+endpointUploadsByURL, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id":%d}`, atomic.AddInt32(&nextID, 1))
+	})
+	mux.HandleFunc("/"+endpointRecognize, func(w http.ResponseWriter, r *http.Request) {
+		id := atomic.AddInt32(&nextID, 1)
+		fmt.Fprintf(w, `{"id":%d,"reports":{"FACING_COUNT":%d}}`, id, id)
+	})
+	mux.HandleFunc("/reports/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":1,"status":"READY","report_type":"FACING_COUNT","json":[]}`)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: ""})
+	assert.NoError(t, err)
+
+	// the feeder reads resume state while workers checkpoint, see go test -race
+	items := make([]BatchItem, 300)
+	for i := range items {
+		items[i] = BatchItem{URL: fmt.Sprintf("https://example.com/%d.jpg", i)}
+	}
+	summary, err := NewBatchPipeline(client, &BatchOptions{
+		Checkpoint:           filepath.Join(t.TempDir(), "batch.checkpoint"),
+		UploadConcurrency:    8,
+		RecognizeConcurrency: 8,
+		WaitConcurrency:      8,
+		Wait:                 &ReportWaitOptions{Interval: time.Millisecond, Timeout: time.Second},
+	}).Run(context.Background(), items)
+	assert.NoError(t, err)
+	assert.Equal(t, 300, summary.Done)
+	assert.Equal(t, 0, summary.Failed)
+}
+
+func TestBatchPipeline_ReportFailed(t *testing.T) {
+	var uploads, recognitions int32
+	var failReports atomic.Bool
+	failReports.Store(true)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+endpointUploadsByURL, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id":%d}`, atomic.AddInt32(&uploads, 1))
+	})
+	mux.HandleFunc("/"+endpointRecognize, func(w http.ResponseWriter, r *http.Request) {
+		id := atomic.AddInt32(&recognitions, 1)
+		fmt.Fprintf(w, `{"id":%d,"reports":{"FACING_COUNT":%d}}`, id, id)
+	})
+	mux.HandleFunc("/reports/", func(w http.ResponseWriter, r *http.Request) {
+		status := ReportStatusREADY
+		if failReports.Load() {
+			status = ReportStatusERROR
+		}
+		fmt.Fprintf(w, `{"id":1,"status":%q,"report_type":"FACING_COUNT","json":[]}`, status)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: ""})
+	assert.NoError(t, err)
+
+	checkp := filepath.Join(t.TempDir(), "batch.checkpoint")
+	opts := &BatchOptions{
+		Checkpoint: checkp,
+		Wait:       &ReportWaitOptions{Interval: time.Millisecond, Timeout: time.Second},
+	}
+	items := []BatchItem{{URL: "https://example.com/a.jpg"}}
+
+	summary, err := NewBatchPipeline(client, opts).Run(context.Background(), items)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Failed)
+	assert.ErrorIs(t, summary.Failures["https://example.com/a.jpg"], ErrReportFailed)
+
+	records, err := LoadBatchCheckpoint(checkp)
+	assert.NoError(t, err)
+	rec := records["https://example.com/a.jpg"]
+	assert.Equal(t, BatchStatusFAILED, rec.Status)
+	assert.Equal(t, 1, rec.ImageID)
+	assert.Zero(t, rec.RecognitionID)
+	assert.Empty(t, rec.Reports)
+
+	// resume: the image is kept, the recognition runs again
+	failReports.Store(false)
+	summary, err = NewBatchPipeline(client, opts).Run(context.Background(), items)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Done)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&uploads))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&recognitions))
+}
+
+func TestBatchPipeline_Decode(t *testing.T) {
+	var nextID, recognitions int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+endpointUploadsByURL, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id":%d}`, atomic.AddInt32(&nextID, 1))
+	})
+	mux.HandleFunc("/"+endpointRecognize, func(w http.ResponseWriter, r *http.Request) {
+		id := atomic.AddInt32(&recognitions, 1)
+		fmt.Fprintf(w, `{"id":%d,"reports":{"FACING_COUNT":%d}}`, id, id)
+	})
+	mux.HandleFunc("/reports/", func(w http.ResponseWriter, r *http.Request) {
+		var id int
+		fmt.Sscanf(r.URL.Path, "/reports/%d/", &id)
+		fmt.Fprintf(w, `{"id":%d,"status":"READY","report_type":"FACING_COUNT","json":[]}`, id)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: ""})
+	assert.NoError(t, err)
+
+	items := make([]BatchItem, 12)
+	for i := range items {
+		items[i] = BatchItem{URL: fmt.Sprintf("https://example.com/%d.jpg", i)}
+	}
+	var inFlight, maxInFlight, sinks int32
+	summary, err := NewBatchPipeline(client, &BatchOptions{
+		Checkpoint:        filepath.Join(t.TempDir(), "batch.checkpoint"),
+		WaitConcurrency:   8,
+		DecodeConcurrency: 2,
+		Wait:              &ReportWaitOptions{Interval: time.Millisecond, Timeout: time.Second},
+		Decode: func(r *Report) (any, error) {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				m := atomic.LoadInt32(&maxInFlight)
+				if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			if r.ID%4 == 0 {
+				return nil, errors.New("bad report")
+			}
+			return r.ID, nil
+		},
+		Sink: func(ctx context.Context, res *BatchResult) error {
+			atomic.AddInt32(&sinks, 1)
+			assert.Equal(t, res.Record.Reports[ReportTypeFACING_COUNT], res.Decoded[ReportTypeFACING_COUNT])
+			return nil
+		},
+	}).Run(context.Background(), items)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, summary.Done+summary.Failed)
+	assert.Equal(t, 3, summary.Failed)
+	assert.Equal(t, int32(summary.Done), atomic.LoadInt32(&sinks)) // failed decodes never reach the sink
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+	for _, err := range summary.Failures {
+		assert.Contains(t, err.Error(), "failed to decode report FACING_COUNT")
+	}
+}
+
+func TestBatchPipeline_DuplicateItems(t *testing.T) {
+	client, err := NewClient(ClintConf{Instance: "http://localhost", APIKey: ""})
+	assert.NoError(t, err)
+
+	_, err = NewBatchPipeline(client, nil).Run(context.Background(), []BatchItem{{URL: "u"}, {Key: "u", Path: "p"}})
+	assert.Error(t, err)
+}
+
+func TestLoadBatchCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "batch.checkpoint")
+	data := `{"key":"a","status":"UPLOADED","image_id":1}
+{"key":"a","status":"RECOGNIZED","image_id":1,"recognition_id":2,"reports":{"FACING_COUNT":3}}
+{"key":"b","sta`
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	records, err := LoadBatchCheckpoint(path)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, BatchRecord{Key: "a", Status: BatchStatusRECOGNIZED, ImageID: 1, RecognitionID: 2, Reports: map[string]int{"FACING_COUNT": 3}}, records["a"])
+}
@@ -0,0 +1,388 @@
+package inspector
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Cassette modes
+const (
+	CassetteModeRecord = "record" // forward requests to the API and save every interaction
+	CassetteModeReplay = "replay" // answer requests from the cassette file
+)
+
+// cassetteRedacted replaces redacted values in cassette files.
+const cassetteRedacted = "REDACTED"
+
+// ErrCassetteMiss is returned in strict replay mode when no recorded interaction matches a request.
+var ErrCassetteMiss = errors.New("no matching cassette interaction")
+
+// CassetteConf configures the record/replay transport, see ClientConf.Cassette.
+type CassetteConf struct {
+	Path          string   // cassette file
+	Mode          string   // CassetteModeRecord or CassetteModeReplay
+	Strict        bool     // replay: fail unmatched requests with ErrCassetteMiss instead of sending them to the API
+	RedactFields  []string // JSON body fields and query parameters replaced with REDACTED
+	RedactHeaders []string // headers replaced with REDACTED in addition to Authorization
+}
+
+// Cassette is the content of a cassette file.
+type Cassette struct {
+	Version      int                   `json:"version"`
+	Interactions []CassetteInteraction `json:"interactions"`
+}
+
+// CassetteInteraction is a recorded request/response pair.
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest is the matching key of an interaction. Body holds canonical JSON;
+// multipart bodies are stored as their fields with file contents replaced by SHA-256 hashes.
+type CassetteRequest struct {
+	Method  string              `json:"method"`
+	Path    string              `json:"path"`
+	Query   string              `json:"query,omitempty"` // encoded with sorted keys
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    string              `json:"body,omitempty"`
+}
+
+// CassetteResponse is a recorded response. Text bodies are stored in Body;
+// bodies that are not valid UTF-8, e.g. downloaded images, in BodyBase64.
+type CassetteResponse struct {
+	Status     int                 `json:"status"`
+	Headers    map[string][]string `json:"headers,omitempty"`
+	Body       string              `json:"body,omitempty"`
+	BodyBase64 []byte              `json:"body_base64,omitempty"`
+}
+
+func (r CassetteRequest) matches(o CassetteRequest) bool {
+	return r.Method == o.Method && r.Path == o.Path && r.Query == o.Query && r.Body == o.Body
+}
+
+// LoadCassette reads a cassette file.
+func LoadCassette(path string) (*Cassette, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette %s:%w", path, err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette %s:%w", path, err)
+	}
+	return &c, nil
+}
+
+// cassetteTransport records or replays interactions.
+type cassetteTransport struct {
+	conf   CassetteConf
+	apiKey string
+	next   http.RoundTripper
+	fields map[string]bool
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+func newCassetteTransport(conf CassetteConf, apiKey string, next http.RoundTripper) (*cassetteTransport, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	t := &cassetteTransport{
+		conf:     conf,
+		apiKey:   apiKey,
+		next:     next,
+		fields:   make(map[string]bool, len(conf.RedactFields)),
+		cassette: Cassette{Version: 1},
+	}
+	for _, f := range conf.RedactFields {
+		t.fields[f] = true
+	}
+
+	switch conf.Mode {
+	case CassetteModeRecord:
+		if err := t.save(); err != nil {
+			return nil, err
+		}
+	case CassetteModeReplay:
+		c, err := LoadCassette(conf.Path)
+		if err != nil {
+			return nil, err
+		}
+		t.cassette = *c
+		t.used = make([]bool, len(c.Interactions))
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %q", conf.Mode)
+	}
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, err := t.requestKey(req)
+	if err != nil {
+		return nil, err
+	}
+	if t.conf.Mode == CassetteModeRecord {
+		return t.record(req, key)
+	}
+
+	if resp, ok := t.replay(req, key); ok {
+		return resp, nil
+	}
+	if t.conf.Strict {
+		return nil, fmt.Errorf("%s %s?%s:%w", key.Method, key.Path, key.Query, ErrCassetteMiss)
+	}
+	return t.next.RoundTrip(req)
+}
+
+func (t *cassetteTransport) record(req *http.Request, key CassetteRequest) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response for cassette:%w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := CassetteInteraction{
+		Request: key,
+		Response: CassetteResponse{
+			Status:  resp.StatusCode,
+			Headers: t.redactHeaders(resp.Header),
+		},
+	}
+	if utf8.Valid(body) {
+		interaction.Response.Body = t.redactBody(body)
+	} else {
+		interaction.Response.BodyBase64 = body
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cassette.Interactions = append(t.cassette.Interactions, interaction)
+	if err := t.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// replay answers with the first unused matching interaction. When all matches were
+// used the last one is repeated, so polling loops keep getting the final state.
+func (t *cassetteTransport) replay(req *http.Request, key CassetteRequest) (*http.Response, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	last := -1
+	for i, in := range t.cassette.Interactions {
+		if !in.Request.matches(key) {
+			continue
+		}
+		last = i
+		if !t.used[i] {
+			t.used[i] = true
+			return cassetteResponse(req, in.Response), true
+		}
+	}
+	if last < 0 {
+		return nil, false
+	}
+	return cassetteResponse(req, t.cassette.Interactions[last].Response), true
+}
+
+func cassetteResponse(req *http.Request, r CassetteResponse) *http.Response {
+	header := http.Header{}
+	for k, v := range r.Headers {
+		header[k] = append([]string(nil), v...)
+	}
+	body := []byte(r.Body)
+	if r.BodyBase64 != nil {
+		body = r.BodyBase64
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// save writes the cassette. Callers hold t.mu or own t exclusively.
+func (t *cassetteTransport) save() error {
+	b, err := json.MarshalIndent(t.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette %s:%w", t.conf.Path, err)
+	}
+	if err := os.WriteFile(t.conf.Path, append(b, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write cassette %s:%w", t.conf.Path, err)
+	}
+	return nil
+}
+
+// requestKey builds the redacted, canonical form of req. The request body is restored for sending.
+func (t *cassetteTransport) requestKey(req *http.Request) (CassetteRequest, error) {
+	key := CassetteRequest{
+		Method:  req.Method,
+		Path:    req.URL.Path,
+		Query:   t.redactQuery(req.URL.Query()).Encode(),
+		Headers: t.redactHeaders(req.Header),
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return key, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return key, fmt.Errorf("failed to read request for cassette:%w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	mediaType, params, _ := mime.ParseMediaType(req.Header.Get(headerContentType))
+	if strings.HasPrefix(mediaType, "multipart/") {
+		key.Body, err = t.multipartKey(body, params["boundary"])
+		if err != nil {
+			return key, err
+		}
+		return key, nil
+	}
+	key.Body = t.redactBody(body)
+	return key, nil
+}
+
+// multipartKey describes a multipart body as canonical JSON with file contents hashed.
+func (t *cassetteTransport) multipartKey(body []byte, boundary string) (string, error) {
+	type part struct {
+		Name     string `json:"name"`
+		Filename string `json:"filename,omitempty"`
+		Value    string `json:"value,omitempty"`
+		SHA256   string `json:"sha256,omitempty"`
+	}
+	var parts []part
+	r := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read multipart request for cassette:%w", err)
+		}
+		data, err := io.ReadAll(p)
+		if err != nil {
+			return "", fmt.Errorf("failed to read multipart request for cassette:%w", err)
+		}
+		pt := part{Name: p.FormName(), Filename: p.FileName()}
+		switch {
+		case pt.Filename != "":
+			sum := sha256.Sum256(data)
+			pt.SHA256 = hex.EncodeToString(sum[:])
+		case t.fields[pt.Name]:
+			pt.Value = cassetteRedacted
+		default:
+			pt.Value = string(data)
+		}
+		parts = append(parts, pt)
+	}
+	b, err := json.Marshal(parts)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode multipart request for cassette:%w", err)
+	}
+	return string(b), nil
+}
+
+// redactBody returns JSON bodies re-encoded with sorted keys and redacted fields;
+// other bodies are returned as is. The API key is never stored.
+func (t *cassetteTransport) redactBody(body []byte) string {
+	var v any
+	if err := json.Unmarshal(body, &v); err == nil {
+		if b, err := json.Marshal(t.redactValue(v)); err == nil {
+			body = b
+		}
+	}
+	return t.redactKey(string(body))
+}
+
+func (t *cassetteTransport) redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			if t.fields[k] {
+				v[k] = cassetteRedacted
+				continue
+			}
+			v[k] = t.redactValue(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = t.redactValue(item)
+		}
+	}
+	return v
+}
+
+func (t *cassetteTransport) redactQuery(q url.Values) url.Values {
+	for k := range q {
+		if t.fields[k] {
+			q[k] = []string{cassetteRedacted}
+			continue
+		}
+		for i, v := range q[k] {
+			q[k][i] = t.redactKey(v)
+		}
+	}
+	return q
+}
+
+func (t *cassetteTransport) redactHeaders(h http.Header) map[string][]string {
+	if len(h) == 0 {
+		return nil
+	}
+	redact := map[string]bool{headerAuthorization: true}
+	for _, name := range t.conf.RedactHeaders {
+		redact[http.CanonicalHeaderKey(name)] = true
+	}
+	out := make(map[string][]string, len(h))
+	for k, v := range h {
+		if redact[http.CanonicalHeaderKey(k)] {
+			out[k] = []string{cassetteRedacted}
+			continue
+		}
+		vals := make([]string, len(v))
+		for i, s := range v {
+			vals[i] = t.redactKey(s)
+		}
+		out[k] = vals
+	}
+	return out
+}
+
+func (t *cassetteTransport) redactKey(s string) string {
+	if t.apiKey == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, t.apiKey, cassetteRedacted)
+}
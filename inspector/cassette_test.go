@@ -0,0 +1,175 @@
+package inspector
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCassette_RecordReplay(t *testing.T) {
+	polls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+endpointUploads, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":1,"url":"https://storage/1.jpg"}`)
+	})
+	mux.HandleFunc("/"+endpointRecognize, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":2,"images":[1],"scene":"s","reports":{"FACING_COUNT":3}}`)
+	})
+	mux.HandleFunc("/reports/3/", func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		status := ReportStatusNOT_READY
+		if polls > 1 {
+			status = ReportStatusREADY
+		}
+		fmt.Fprintf(w, `{"id":3,"status":%q,"report_type":"FACING_COUNT","json":[]}`, status)
+	})
+	mux.HandleFunc("/"+endpointSKU, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"count":1,"results":[{"id":9,"cid":"C9","name":"Cola"}]}`)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "flow.json")
+	run := func(instance string, mode string) (*RecognizeResponse, *Report, []Sku, error) {
+		client, err := NewClient(ClientConf{
+			Instance: instance,
+			APIKey:   "secret-key",
+			Cassette: &CassetteConf{Path: path, Mode: mode, Strict: true, RedactFields: []string{"webhook"}},
+		})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		ctx := context.Background()
+		img, err := client.Image.Upload(ctx, strings.NewReader("jpeg bytes"), "shelf.jpg")
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		rec, err := client.Recognize.Recognize(ctx, RecognizeRequest{
+			Images:      []int{img.ID},
+			ReportTypes: []string{ReportTypeFACING_COUNT},
+			Webhook:     "https://hooks.example.com/token-123",
+		})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		report, err := client.Report.WaitForReport(ctx, rec.Reports[ReportTypeFACING_COUNT], &ReportWaitOptions{Interval: time.Millisecond})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		skus, err := client.Sku.FindByCID(ctx, "C9")
+		return rec, report, skus, err
+	}
+
+	rec, report, skus, err := run(ts.URL, CassetteModeRecord)
+	assert.NoError(t, err)
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "secret-key")
+	assert.NotContains(t, string(b), "token-123")
+	assert.NotContains(t, string(b), "jpeg bytes")
+	assert.Contains(t, string(b), cassetteRedacted)
+
+	cassette, err := LoadCassette(path)
+	assert.NoError(t, err)
+	assert.Len(t, cassette.Interactions, 5)
+
+	// replay without a server: the closed port proves nothing hits the network
+	ts.Close()
+	rec2, report2, skus2, err := run(ts.URL, CassetteModeReplay)
+	assert.NoError(t, err)
+	assert.Equal(t, rec, rec2)
+	assert.Equal(t, report.Status, report2.Status)
+	assert.Equal(t, skus, skus2)
+}
+
+func TestCassette_BinaryBody(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	src.Set(1, 1, color.RGBA{R: 0xff, A: 0xff})
+	var body bytes.Buffer
+	assert.NoError(t, png.Encode(&body, src))
+	assert.False(t, utf8.Valid(body.Bytes()))
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(body.Bytes())
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "download.json")
+	img := Image{ID: 1, URL: ts.URL + "/media/1.png"}
+	download := func(mode string) (image.Image, error) {
+		client, err := NewClient(ClientConf{Instance: ts.URL, Cassette: &CassetteConf{Path: path, Mode: mode, Strict: true}})
+		if err != nil {
+			return nil, err
+		}
+		return client.Image.Download(context.Background(), img)
+	}
+
+	recorded, err := download(CassetteModeRecord)
+	assert.NoError(t, err)
+	cassette, err := LoadCassette(path)
+	assert.NoError(t, err)
+	assert.Len(t, cassette.Interactions, 1)
+	assert.Empty(t, cassette.Interactions[0].Response.Body)
+	assert.Equal(t, body.Bytes(), cassette.Interactions[0].Response.BodyBase64)
+
+	ts.Close()
+	replayed, err := download(CassetteModeReplay)
+	assert.NoError(t, err)
+	assert.Equal(t, recorded, replayed)
+	assert.Equal(t, color.RGBA{R: 0xff, A: 0xff}, color.RGBAModel.Convert(replayed.At(1, 1)))
+}
+
+func TestCassette_ReplayMiss(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "upload.json")
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":1}`)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClientConf{Instance: ts.URL, Cassette: &CassetteConf{Path: path, Mode: CassetteModeRecord}})
+	assert.NoError(t, err)
+	_, err = client.Image.Upload(context.Background(), strings.NewReader("a"), "a.jpg")
+	assert.NoError(t, err)
+
+	t.Run("strict", func(t *testing.T) {
+		client, err := NewClient(ClientConf{Instance: ts.URL, Cassette: &CassetteConf{Path: path, Mode: CassetteModeReplay, Strict: true}})
+		assert.NoError(t, err)
+
+		_, err = client.Image.Upload(context.Background(), strings.NewReader("a"), "a.jpg")
+		assert.NoError(t, err)
+		_, err = client.Image.Upload(context.Background(), strings.NewReader("other file"), "a.jpg")
+		assert.True(t, errors.Is(err, ErrCassetteMiss))
+	})
+
+	t.Run("passthrough", func(t *testing.T) {
+		client, err := NewClient(ClientConf{Instance: ts.URL, Cassette: &CassetteConf{Path: path, Mode: CassetteModeReplay}})
+		assert.NoError(t, err)
+
+		img, err := client.Image.UploadByURL(context.Background(), "https://example.com/a.jpg")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, img.ID)
+	})
+
+	t.Run("bad config", func(t *testing.T) {
+		_, err := NewClient(ClientConf{Instance: ts.URL, Cassette: &CassetteConf{Path: path, Mode: "rewind"}})
+		assert.Error(t, err)
+		_, err = NewClient(ClientConf{Instance: ts.URL, Cassette: &CassetteConf{Path: path + ".missing", Mode: CassetteModeReplay}})
+		assert.Error(t, err)
+	})
+}
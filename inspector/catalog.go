@@ -0,0 +1,338 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// Brand represents a IC brand
+type Brand struct {
+	ID           int    `json:"id"`                     // Unique brand ID
+	Name         string `json:"name"`                   // Human readable brand name
+	Manufacturer *int   `json:"manufacturer,omitempty"` // Manufacturer ID
+}
+
+// Category represents a IC product category
+type Category struct {
+	ID     int    `json:"id"`               // Unique category ID
+	Name   string `json:"name"`             // Human readable category name
+	Parent *int   `json:"parent,omitempty"` // Parent category ID, nil for root categories
+}
+
+// Manufacturer represents a IC manufacturer
+type Manufacturer struct {
+	ID   int    `json:"id"`   // Unique manufacturer ID
+	Name string `json:"name"` // Human readable manufacturer name
+}
+
+// BrandService provides access to the Brand catalog functions in the IC API.
+type BrandService struct {
+	client *Client
+}
+
+// CategoryService provides access to the Category catalog functions in the IC API.
+type CategoryService struct {
+	client *Client
+}
+
+// ManufacturerService provides access to the Manufacturer catalog functions in the IC API.
+type ManufacturerService struct {
+	client *Client
+}
+
+// BrandIterator provides paginated iteration over brands.
+type BrandIterator = Iterator[Brand]
+
+// CategoryIterator provides paginated iteration over categories.
+type CategoryIterator = Iterator[Category]
+
+// ManufacturerIterator provides paginated iteration over manufacturers.
+type ManufacturerIterator = Iterator[Manufacturer]
+
+// GetBrands requests list of brands.
+// Return Pagination for the given offset and limit, decode its Results with ToBrands.
+func (srv *BrandService) GetBrands(ctx context.Context, offset, limit int) (*Pagination, error) {
+	return srv.client.getPage(ctx, endpointBrands, nil, offset, limit)
+}
+
+// ToBrands parses json to []Brand
+func (srv *BrandService) ToBrands(v any) ([]Brand, error) {
+	return decodeCatalog[Brand](v)
+}
+
+// IterateBrands returns an iterator for paginated brand retrieval.
+func (srv *BrandService) IterateBrands(ctx context.Context, pageSize int) BrandIterator {
+	return NewPaginator[Brand](ctx, srv.client, endpointBrands, nil, pageSize)
+}
+
+// GetAllBrands fetches all brands using automatic pagination.
+func (srv *BrandService) GetAllBrands(ctx context.Context, pageSize int) ([]Brand, error) {
+	return srv.IterateBrands(ctx, pageSize).Collect()
+}
+
+// GetBrand requests brand for the given id
+func (srv *BrandService) GetBrand(ctx context.Context, id int) (*Brand, error) {
+	var b Brand
+	if err := srv.client.getObject(ctx, fmt.Sprintf(endpointBrandByID, id), &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// GetCategories requests list of categories.
+// Return Pagination for the given offset and limit, decode its Results with ToCategories.
+func (srv *CategoryService) GetCategories(ctx context.Context, offset, limit int) (*Pagination, error) {
+	return srv.client.getPage(ctx, endpointCategories, nil, offset, limit)
+}
+
+// ToCategories parses json to []Category
+func (srv *CategoryService) ToCategories(v any) ([]Category, error) {
+	return decodeCatalog[Category](v)
+}
+
+// IterateCategories returns an iterator for paginated category retrieval.
+func (srv *CategoryService) IterateCategories(ctx context.Context, pageSize int) CategoryIterator {
+	return NewPaginator[Category](ctx, srv.client, endpointCategories, nil, pageSize)
+}
+
+// GetAllCategories fetches all categories using automatic pagination.
+func (srv *CategoryService) GetAllCategories(ctx context.Context, pageSize int) ([]Category, error) {
+	return srv.IterateCategories(ctx, pageSize).Collect()
+}
+
+// GetCategory requests category for the given id
+func (srv *CategoryService) GetCategory(ctx context.Context, id int) (*Category, error) {
+	var c Category
+	if err := srv.client.getObject(ctx, fmt.Sprintf(endpointCategoryByID, id), &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetManufacturers requests list of manufacturers.
+// Return Pagination for the given offset and limit, decode its Results with ToManufacturers.
+func (srv *ManufacturerService) GetManufacturers(ctx context.Context, offset, limit int) (*Pagination, error) {
+	return srv.client.getPage(ctx, endpointManufacturers, nil, offset, limit)
+}
+
+// ToManufacturers parses json to []Manufacturer
+func (srv *ManufacturerService) ToManufacturers(v any) ([]Manufacturer, error) {
+	return decodeCatalog[Manufacturer](v)
+}
+
+// IterateManufacturers returns an iterator for paginated manufacturer retrieval.
+func (srv *ManufacturerService) IterateManufacturers(ctx context.Context, pageSize int) ManufacturerIterator {
+	return NewPaginator[Manufacturer](ctx, srv.client, endpointManufacturers, nil, pageSize)
+}
+
+// GetAllManufacturers fetches all manufacturers using automatic pagination.
+func (srv *ManufacturerService) GetAllManufacturers(ctx context.Context, pageSize int) ([]Manufacturer, error) {
+	return srv.IterateManufacturers(ctx, pageSize).Collect()
+}
+
+// GetManufacturer requests manufacturer for the given id
+func (srv *ManufacturerService) GetManufacturer(ctx context.Context, id int) (*Manufacturer, error) {
+	var m Manufacturer
+	if err := srv.client.getObject(ctx, fmt.Sprintf(endpointManufacturerByID, id), &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// decodeCatalog converts decoded JSON ([]any of maps) to []T.
+func decodeCatalog[T any](v any) ([]T, error) {
+	var r []T
+	if err := mapstructure.Decode(v, &r); err != nil {
+		return r, fmt.Errorf("failed to Decode %v:%w", v, err)
+	}
+	return r, nil
+}
+
+// EnrichedSku represents a Sku with catalog IDs resolved to names.
+type EnrichedSku struct {
+	Sku
+	BrandName        string   `json:"brand_name,omitempty"`
+	ManufacturerName string   `json:"manufacturer_name,omitempty"`
+	CategoryName     string   `json:"category_name,omitempty"`
+	CategoryPath     []string `json:"category_path,omitempty"` // category names from root to leaf
+}
+
+// CatalogResolver resolves brand, category and manufacturer IDs to names.
+// Looked up objects are cached; it is safe for concurrent use.
+type CatalogResolver struct {
+	client API
+
+	mu            sync.Mutex
+	brands        map[int]Brand
+	categories    map[int]Category
+	manufacturers map[int]Manufacturer
+}
+
+// NewCatalogResolver makes a new CatalogResolver using the catalog services of c.
+func NewCatalogResolver(c API) *CatalogResolver {
+	return &CatalogResolver{
+		client:        c,
+		brands:        make(map[int]Brand),
+		categories:    make(map[int]Category),
+		manufacturers: make(map[int]Manufacturer),
+	}
+}
+
+// Preload fetches complete brand, category and manufacturer catalogs into the cache.
+// Without Preload objects are requested one by one on first use.
+func (r *CatalogResolver) Preload(ctx context.Context) error {
+	brands, err := r.client.Brands().GetAllBrands(ctx, DefaultPageSize)
+	if err != nil {
+		return fmt.Errorf("failed to Preload brands:%w", err)
+	}
+	categories, err := r.client.Categories().GetAllCategories(ctx, DefaultPageSize)
+	if err != nil {
+		return fmt.Errorf("failed to Preload categories:%w", err)
+	}
+	manufacturers, err := r.client.Manufacturers().GetAllManufacturers(ctx, DefaultPageSize)
+	if err != nil {
+		return fmt.Errorf("failed to Preload manufacturers:%w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range brands {
+		r.brands[b.ID] = b
+	}
+	for _, c := range categories {
+		r.categories[c.ID] = c
+	}
+	for _, m := range manufacturers {
+		r.manufacturers[m.ID] = m
+	}
+	return nil
+}
+
+// Brand returns brand for the given id.
+func (r *CatalogResolver) Brand(ctx context.Context, id int) (Brand, error) {
+	r.mu.Lock()
+	b, ok := r.brands[id]
+	r.mu.Unlock()
+	if ok {
+		return b, nil
+	}
+
+	fetched, err := r.client.Brands().GetBrand(ctx, id)
+	if err != nil {
+		return Brand{}, err
+	}
+	r.mu.Lock()
+	r.brands[id] = *fetched
+	r.mu.Unlock()
+	return *fetched, nil
+}
+
+// Category returns category for the given id.
+func (r *CatalogResolver) Category(ctx context.Context, id int) (Category, error) {
+	r.mu.Lock()
+	c, ok := r.categories[id]
+	r.mu.Unlock()
+	if ok {
+		return c, nil
+	}
+
+	fetched, err := r.client.Categories().GetCategory(ctx, id)
+	if err != nil {
+		return Category{}, err
+	}
+	r.mu.Lock()
+	r.categories[id] = *fetched
+	r.mu.Unlock()
+	return *fetched, nil
+}
+
+// Manufacturer returns manufacturer for the given id.
+func (r *CatalogResolver) Manufacturer(ctx context.Context, id int) (Manufacturer, error) {
+	r.mu.Lock()
+	m, ok := r.manufacturers[id]
+	r.mu.Unlock()
+	if ok {
+		return m, nil
+	}
+
+	fetched, err := r.client.Manufacturers().GetManufacturer(ctx, id)
+	if err != nil {
+		return Manufacturer{}, err
+	}
+	r.mu.Lock()
+	r.manufacturers[id] = *fetched
+	r.mu.Unlock()
+	return *fetched, nil
+}
+
+// CategoryPath returns categories from the root to the given category.
+func (r *CatalogResolver) CategoryPath(ctx context.Context, id int) ([]Category, error) {
+	var path []Category
+	seen := make(map[int]bool)
+	for next := &id; next != nil; {
+		if seen[*next] {
+			return nil, fmt.Errorf("detected category hierarchy loop at category %d", *next)
+		}
+		seen[*next] = true
+
+		c, err := r.Category(ctx, *next)
+		if err != nil {
+			return nil, err
+		}
+		path = append([]Category{c}, path...)
+		next = c.Parent
+	}
+	return path, nil
+}
+
+// Enrich expands sku with brand, manufacturer and category names.
+// When sku has no manufacturer, the manufacturer of its brand is used.
+func (r *CatalogResolver) Enrich(ctx context.Context, sku Sku) (*EnrichedSku, error) {
+	e := &EnrichedSku{Sku: sku}
+
+	manufacturer := sku.Manufacturer
+	if sku.Brand != nil {
+		b, err := r.Brand(ctx, *sku.Brand)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve brand %d of SKU %d:%w", *sku.Brand, sku.ID, err)
+		}
+		e.BrandName = b.Name
+		if manufacturer == nil {
+			manufacturer = b.Manufacturer
+		}
+	}
+	if manufacturer != nil {
+		m, err := r.Manufacturer(ctx, *manufacturer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve manufacturer %d of SKU %d:%w", *manufacturer, sku.ID, err)
+		}
+		e.ManufacturerName = m.Name
+	}
+	if sku.Category != nil {
+		path, err := r.CategoryPath(ctx, *sku.Category)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve category %d of SKU %d:%w", *sku.Category, sku.ID, err)
+		}
+		for _, c := range path {
+			e.CategoryPath = append(e.CategoryPath, c.Name)
+		}
+		e.CategoryName = path[len(path)-1].Name
+	}
+	return e, nil
+}
+
+// EnrichAll expands every SKU of skus, see Enrich.
+func (r *CatalogResolver) EnrichAll(ctx context.Context, skus []Sku) ([]EnrichedSku, error) {
+	enriched := make([]EnrichedSku, 0, len(skus))
+	for _, sku := range skus {
+		e, err := r.Enrich(ctx, sku)
+		if err != nil {
+			return nil, err
+		}
+		enriched = append(enriched, *e)
+	}
+	return enriched, nil
+}
@@ -0,0 +1,175 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCatalogServer(t *testing.T, calls map[string]int) *httptest.Server {
+	mux := http.NewServeMux()
+	handle := func(path, body string) {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, methodGET, r.Method)
+			calls[r.URL.Path]++
+			w.Header().Set(headerContentType, contentTypeJSON)
+			fmt.Fprint(w, body)
+		})
+	}
+	handle("/brand/", `{"count":2,"next":null,"previous":null,"results":[
+		{"id": 25, "name": "Heineken", "manufacturer": 3},
+		{"id": 26, "name": "Amstel", "manufacturer": 3}]}`)
+	handle("/brand/25/", `{"id": 25, "name": "Heineken", "manufacturer": 3}`)
+	handle("/category/", `{"count":3,"next":null,"previous":null,"results":[
+		{"id": 1, "name": "Drinks"},
+		{"id": 7, "name": "Beer", "parent": 1},
+		{"id": 9, "name": "Lager", "parent": 7}]}`)
+	handle("/category/9/", `{"id": 9, "name": "Lager", "parent": 7}`)
+	handle("/category/7/", `{"id": 7, "name": "Beer", "parent": 1}`)
+	handle("/category/1/", `{"id": 1, "name": "Drinks"}`)
+	handle("/manufacturer/", `{"count":1,"next":null,"previous":null,"results":[{"id": 3, "name": "Heineken N.V."}]}`)
+	handle("/manufacturer/3/", `{"id": 3, "name": "Heineken N.V."}`)
+	return httptest.NewServer(mux)
+}
+
+func TestBrandService_GetAllBrands(t *testing.T) {
+	calls := map[string]int{}
+	ts := newCatalogServer(t, calls)
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+	assert.NoError(t, err)
+
+	brands, err := client.Brand.GetAllBrands(context.Background(), 10)
+	assert.NoError(t, err)
+	manufacturer := 3
+	assert.Equal(t, []Brand{
+		{ID: 25, Name: "Heineken", Manufacturer: &manufacturer},
+		{ID: 26, Name: "Amstel", Manufacturer: &manufacturer},
+	}, brands)
+
+	brand, err := client.Brand.GetBrand(context.Background(), 25)
+	assert.NoError(t, err)
+	assert.Equal(t, "Heineken", brand.Name)
+}
+
+func TestBrandService_GetBrands(t *testing.T) {
+	calls := map[string]int{}
+	ts := newCatalogServer(t, calls)
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+	assert.NoError(t, err)
+
+	page, err := client.Brand.GetBrands(context.Background(), 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, page.Count)
+	assert.IsType(t, []any{}, page.Results) // decoded JSON, the same as GetSKU
+	brands, err := client.Brand.ToBrands(page.Results)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{25, 26}, []int{brands[0].ID, brands[1].ID})
+
+	t.Run("decoded json", func(t *testing.T) {
+		brands, err := client.Brand.ToBrands([]any{map[string]any{"id": 7, "name": "Amstel"}})
+		assert.NoError(t, err)
+		assert.Equal(t, []Brand{{ID: 7, Name: "Amstel"}}, brands)
+	})
+}
+
+func TestCategoryService_GetAllCategories(t *testing.T) {
+	calls := map[string]int{}
+	ts := newCatalogServer(t, calls)
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+	assert.NoError(t, err)
+
+	categories, err := client.Category.GetAllCategories(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(categories))
+	assert.Nil(t, categories[0].Parent)
+	assert.Equal(t, 7, *categories[2].Parent)
+}
+
+func TestManufacturerService_GetManufacturer(t *testing.T) {
+	calls := map[string]int{}
+	ts := newCatalogServer(t, calls)
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+	assert.NoError(t, err)
+
+	m, err := client.Manufacturer.GetManufacturer(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, &Manufacturer{ID: 3, Name: "Heineken N.V."}, m)
+
+	all, err := client.Manufacturer.GetAllManufacturers(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(all))
+}
+
+func TestCatalogResolver_Enrich(t *testing.T) {
+	brand, category := 25, 9
+	sku := Sku{ID: 26, CID: "4601501027624", Name: "Heineken 0.5", Brand: &brand, Category: &category}
+	want := &EnrichedSku{
+		Sku:              sku,
+		BrandName:        "Heineken",
+		ManufacturerName: "Heineken N.V.",
+		CategoryName:     "Lager",
+		CategoryPath:     []string{"Drinks", "Beer", "Lager"},
+	}
+
+	t.Run("lazy lookups are cached", func(t *testing.T) {
+		calls := map[string]int{}
+		ts := newCatalogServer(t, calls)
+		defer ts.Close()
+
+		client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+		assert.NoError(t, err)
+
+		resolver := NewCatalogResolver(client)
+		got, err := resolver.Enrich(context.Background(), sku)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+
+		all, err := resolver.EnrichAll(context.Background(), []Sku{sku, {ID: 1, Name: "No catalog data"}})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(all))
+		assert.Equal(t, "", all[1].BrandName)
+
+		assert.Equal(t, 1, calls["/brand/25/"])
+		assert.Equal(t, 1, calls["/category/9/"])
+		assert.Equal(t, 1, calls["/manufacturer/3/"])
+	})
+
+	t.Run("preload", func(t *testing.T) {
+		calls := map[string]int{}
+		ts := newCatalogServer(t, calls)
+		defer ts.Close()
+
+		client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+		assert.NoError(t, err)
+
+		resolver := NewCatalogResolver(client)
+		assert.NoError(t, resolver.Preload(context.Background()))
+		got, err := resolver.Enrich(context.Background(), sku)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.Equal(t, 0, calls["/brand/25/"])
+		assert.Equal(t, 0, calls["/category/9/"])
+	})
+
+	t.Run("category loop", func(t *testing.T) {
+		resolver := NewCatalogResolver(nil)
+		a, b := 1, 2
+		resolver.categories[1] = Category{ID: 1, Name: "A", Parent: &b}
+		resolver.categories[2] = Category{ID: 2, Name: "B", Parent: &a}
+		_, err := resolver.CategoryPath(context.Background(), 1)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "loop")
+	})
+}
@@ -23,12 +23,17 @@ type Client struct {
 	APIKey      string
 	httpClient  *httpclient.Client
 	httpTimeout time.Duration
+	mediaClient *http.Client // plain client for image downloads, sends no API key
 
-	Image     *ImageService
-	Recognize *RecognizeService
-	Report    *ReportService
-	Sku       *SkuService
-	Visit     *VisitService
+	Image        *ImageService
+	Recognize    *RecognizeService
+	Report       *ReportService
+	Sku          *SkuService
+	Visit        *VisitService
+	Brand        *BrandService
+	Category     *CategoryService
+	Manufacturer *ManufacturerService
+	Shop         *ShopService
 }
 
 // ClientConf holds all of the configuration options for Client.
@@ -38,6 +43,7 @@ type ClientConf struct {
 	Verbose    bool
 	HTTPClient *http.Client
 	Timeout    time.Duration
+	Cassette   *CassetteConf // optional record/replay of HTTP interactions
 }
 
 // ClintConf is kept for backward compatibility with the historical typo.
@@ -55,6 +61,15 @@ func NewClient(cfg ClientConf) (*Client, error) {
 	} else {
 		httpc = cfg.HTTPClient
 	}
+	if cfg.Cassette != nil {
+		c := *httpc
+		tr, err := newCassetteTransport(*cfg.Cassette, cfg.APIKey, c.Transport)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build cassette transport:%w", err)
+		}
+		c.Transport = tr
+		httpc = &c
+	}
 
 	cl, err := httpclient.New(
 		httpc,
@@ -75,12 +90,17 @@ func NewClient(cfg ClientConf) (*Client, error) {
 		Instance:    cfg.Instance,
 		httpClient:  cl,
 		httpTimeout: httpc.Timeout,
+		mediaClient: httpc,
 	}
 	c.Image = &ImageService{client: c}
 	c.Recognize = &RecognizeService{client: c}
 	c.Report = &ReportService{client: c}
 	c.Sku = &SkuService{client: c}
 	c.Visit = &VisitService{client: c}
+	c.Brand = &BrandService{client: c}
+	c.Category = &CategoryService{client: c}
+	c.Manufacturer = &ManufacturerService{client: c}
+	c.Shop = &ShopService{client: c}
 
 	return c, nil
 }
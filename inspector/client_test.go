@@ -18,6 +18,10 @@ func TestNewClient_DefaultsAndServices(t *testing.T) {
 	assert.NotNil(t, c.Report)
 	assert.NotNil(t, c.Sku)
 	assert.NotNil(t, c.Visit)
+	assert.NotNil(t, c.Brand)
+	assert.NotNil(t, c.Category)
+	assert.NotNil(t, c.Manufacturer)
+	assert.NotNil(t, c.Shop)
 }
 
 func TestNewClient_UsesCustomHTTPClient(t *testing.T) {
@@ -32,3 +36,19 @@ func TestNewClient_VerboseAddsInterceptor(t *testing.T) {
 	_, err := NewClient(ClientConf{Instance: "https://example.com", APIKey: "abc", Verbose: true})
 	assert.NoError(t, err)
 }
+
+func TestClient_API(t *testing.T) {
+	c, err := NewClient(ClientConf{Instance: "https://example.com", APIKey: "abc"})
+	assert.NoError(t, err)
+
+	var api API = c
+	assert.Same(t, c.Image, api.Images())
+	assert.Same(t, c.Recognize, api.Recognizer())
+	assert.Same(t, c.Report, api.Reports())
+	assert.Same(t, c.Sku, api.SKUs())
+	assert.Same(t, c.Visit, api.Visits())
+	assert.Same(t, c.Shop, api.Shops())
+	assert.Same(t, c.Brand, api.Brands())
+	assert.Same(t, c.Category, api.Categories())
+	assert.Same(t, c.Manufacturer, api.Manufacturers())
+}
@@ -16,19 +16,35 @@ const (
 	// Image endpoints
 	endpointUploads      = "uploads/"
 	endpointUploadsByURL = "uploads/upload_by_url/"
+	endpointUploadByID   = "uploads/%d/" // formatted with image ID
 
 	// Recognition endpoints
 	endpointRecognize        = "recognize/"
 	endpointRecognitionError = "recognition_error/"
 
 	// Report endpoints
-	endpointReports = "reports/%d/" // formatted with report ID
+	endpointReports    = "reports/%d/" // formatted with report ID
+	endpointReportList = "reports/"
 
 	// SKU endpoints
-	endpointSKU = "sku/"
+	endpointSKU     = "sku/"
+	endpointSKUByID = "sku/%d/" // formatted with SKU ID
+
+	// Catalog endpoints
+	endpointBrands           = "brand/"
+	endpointBrandByID        = "brand/%d/" // formatted with brand ID
+	endpointCategories       = "category/"
+	endpointCategoryByID     = "category/%d/" // formatted with category ID
+	endpointManufacturers    = "manufacturer/"
+	endpointManufacturerByID = "manufacturer/%d/" // formatted with manufacturer ID
+
+	// Shop endpoints
+	endpointShops    = "shops/"
+	endpointShopByID = "shops/%d/" // formatted with shop ID
 
 	// Visit endpoints
-	endpointVisits = "visits/"
+	endpointVisits    = "visits/"
+	endpointVisitByID = "visits/%d/" // formatted with visit ID
 )
 
 // Default timeouts and intervals
@@ -41,6 +57,15 @@ const (
 
 	// DefaultPollingTimeout is the default overall timeout for polling
 	DefaultPollingTimeout = 60 * time.Second
+
+	// DefaultJobBaseBackoff is the default delay before the first JobQueue retry
+	DefaultJobBaseBackoff = 1 * time.Second
+
+	// DefaultJobMaxBackoff is the default upper bound of the JobQueue retry delay
+	DefaultJobMaxBackoff = 5 * time.Minute
+
+	// DefaultJobPollInterval is the default interval idle JobQueue workers re-check the queue
+	DefaultJobPollInterval = 1 * time.Second
 )
 
 // Authentication scheme
@@ -56,6 +81,61 @@ const (
 	// MaxPaginationPages is the maximum number of pages to fetch
 	// to prevent infinite loops in pagination
 	MaxPaginationPages = 1000
+
+	// DefaultFetchConcurrency is the default number of concurrent page requests
+	// for parallel list fetching
+	DefaultFetchConcurrency = 4
+
+	// DefaultSessionConcurrency is the default number of displays
+	// processed concurrently by VisitSession
+	DefaultSessionConcurrency = 4
+
+	// DefaultJobMaxAttempts is the default number of attempts of a JobQueue job
+	DefaultJobMaxAttempts = 10
+
+	// DefaultJobCompactEvery is the default number of records appended
+	// to the JobQueue log before it is compacted
+	DefaultJobCompactEvery = 1000
+
+	// DefaultBatchConcurrency is the default number of workers
+	// per stage of BatchPipeline
+	DefaultBatchConcurrency = 4
+
+	// DefaultFeedbackConcurrency is the default number of recognition error
+	// requests sent concurrently by RecognitionFeedback
+	DefaultFeedbackConcurrency = 4
+
+	// DefaultStraddleTolerance is the default fraction of a product's height
+	// that may cross a shelf line before the product is reported as straddling it
+	DefaultStraddleTolerance = 0.2
+
+	// DefaultRenderLineWidth is the default stroke width of realogram overlays in pixels
+	DefaultRenderLineWidth = 3
+
+	// DefaultRenderFontScale is the default size of a PNG label font pixel
+	DefaultRenderFontScale = 3
+)
+
+// Query parameter names
+const (
+	queryParamLimit         = "limit"
+	queryParamOffset        = "offset"
+	queryParamCursor        = "cursor"
+	queryParamSearch        = "search"
+	queryParamEAN13         = "ean13"
+	queryParamCID           = "cid"
+	queryParamBrand         = "brand"
+	queryParamCategory      = "category"
+	queryParamManufacturer  = "manufacturer"
+	queryParamOrdering      = "ordering"
+	queryParamModifiedSince = "modified_since"
+	queryParamShop          = "shop"
+	queryParamAgent         = "agent"
+	queryParamStartedAfter  = "started_date_after"
+	queryParamStartedBefore = "started_date_before"
+	queryParamVisit         = "visit"
+	queryParamRetailChain   = "retail_chain"
+	queryParamExternalCode  = "external_code"
 )
 
 // HTTP header names
@@ -0,0 +1,291 @@
+package inspector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Change kinds of report diffs
+const (
+	ChangeADDED   = "added"   // SKU only in the later report
+	ChangeREMOVED = "removed" // SKU only in the earlier report
+	ChangeCHANGED = "changed" // facings or price changed
+	ChangeMOVED   = "moved"   // realogram: SKU is on other shelves
+)
+
+// FacingChange is the facing count change of one SKU.
+type FacingChange struct {
+	SkuId  int    `json:"sku_id"`
+	Kind   string `json:"kind"`
+	Before int    `json:"before"`
+	After  int    `json:"after"`
+	Delta  int    `json:"delta"`
+}
+
+// FacingDiff is the change set between two FACING_COUNT reports.
+type FacingDiff struct {
+	Changes []FacingChange `json:"changes"` // ordered by SKU ID
+	Gained  int            `json:"gained"`  // facings gained over all SKUs
+	Lost    int            `json:"lost"`    // facings lost over all SKUs
+}
+
+// DiffFacingCounts compares facing counts per SKU of an earlier and a later report.
+func DiffFacingCounts(before, after []ReportFacingCountJson) *FacingDiff {
+	b, a := FacingsFromCounts(before), FacingsFromCounts(after)
+	d := &FacingDiff{}
+	for _, sku := range unionKeys(b, a) {
+		c := FacingChange{SkuId: sku, Before: b[sku], After: a[sku]}
+		c.Delta = c.After - c.Before
+		switch {
+		case c.Delta == 0:
+			continue
+		case c.Before == 0:
+			c.Kind = ChangeADDED
+		case c.After == 0:
+			c.Kind = ChangeREMOVED
+		default:
+			c.Kind = ChangeCHANGED
+		}
+		if c.Delta > 0 {
+			d.Gained += c.Delta
+		} else {
+			d.Lost -= c.Delta
+		}
+		d.Changes = append(d.Changes, c)
+	}
+	return d
+}
+
+// Empty reports whether nothing changed.
+func (d *FacingDiff) Empty() bool { return len(d.Changes) == 0 }
+
+// Summary describes the change set as text, one line per SKU.
+func (d *FacingDiff) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "facings: %d SKUs changed, +%d/-%d\n", len(d.Changes), d.Gained, d.Lost)
+	for _, c := range d.Changes {
+		fmt.Fprintf(&b, "  SKU %d %s: %d -> %d (%+d)\n", c.SkuId, c.Kind, c.Before, c.After, c.Delta)
+	}
+	return b.String()
+}
+
+// PriceChange is the shelf price change of one SKU.
+type PriceChange struct {
+	SkuId       int      `json:"sku_id"`
+	Name        string   `json:"name,omitempty"`
+	Kind        string   `json:"kind"`
+	Before      *float64 `json:"before,omitempty"`
+	After       *float64 `json:"after,omitempty"`
+	PromoBefore bool     `json:"promo_before"`
+	PromoAfter  bool     `json:"promo_after"`
+	Delta       float64  `json:"delta"`     // After - Before, 0 unless both are set
+	DeltaPct    float64  `json:"delta_pct"` // in percent of Before
+}
+
+// PriceDiff is the change set between two PRICE_TAGS reports.
+type PriceDiff struct {
+	Changes []PriceChange `json:"changes"` // ordered by SKU ID
+}
+
+// DiffPriceTags compares shelf prices per SKU of an earlier and a later report.
+// A SKU with several tags is represented by its lowest price. A change of the
+// promo flag alone is a change too.
+func DiffPriceTags(before, after []ReportPriceTagsJson) *PriceDiff {
+	b, a := lowestPriceTags(before), lowestPriceTags(after)
+	d := &PriceDiff{}
+	for _, sku := range unionKeys(b, a) {
+		tb, inBefore := b[sku]
+		ta, inAfter := a[sku]
+		c := PriceChange{SkuId: sku, Name: ta.Name, PromoBefore: tb.IsPromo(), PromoAfter: ta.IsPromo()}
+		if c.Name == "" {
+			c.Name = tb.Name
+		}
+		if inBefore {
+			c.Before = &tb.Price
+		}
+		if inAfter {
+			c.After = &ta.Price
+		}
+		switch {
+		case !inBefore:
+			c.Kind = ChangeADDED
+		case !inAfter:
+			c.Kind = ChangeREMOVED
+		case tb.Price == ta.Price && c.PromoBefore == c.PromoAfter:
+			continue
+		default:
+			c.Kind = ChangeCHANGED
+			c.Delta = ta.Price - tb.Price
+			if tb.Price != 0 {
+				c.DeltaPct = 100 * c.Delta / tb.Price
+			}
+		}
+		d.Changes = append(d.Changes, c)
+	}
+	return d
+}
+
+func lowestPriceTags(tags []ReportPriceTagsJson) map[int]ReportPriceTagsJson {
+	m := make(map[int]ReportPriceTagsJson, len(tags))
+	for _, t := range tags {
+		if cur, ok := m[t.SkuId]; !ok || t.Price < cur.Price {
+			m[t.SkuId] = t
+		}
+	}
+	return m
+}
+
+// Empty reports whether nothing changed.
+func (d *PriceDiff) Empty() bool { return len(d.Changes) == 0 }
+
+// Summary describes the change set as text, one line per SKU.
+func (d *PriceDiff) Summary() string {
+	price := func(p *float64, promo bool) string {
+		if p == nil {
+			return "-"
+		}
+		if promo {
+			return fmt.Sprintf("%.2f (promo)", *p)
+		}
+		return fmt.Sprintf("%.2f", *p)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "prices: %d SKUs changed\n", len(d.Changes))
+	for _, c := range d.Changes {
+		fmt.Fprintf(&b, "  SKU %d %s: %s -> %s", c.SkuId, c.Kind, price(c.Before, c.PromoBefore), price(c.After, c.PromoAfter))
+		if c.Kind == ChangeCHANGED && c.Delta != 0 {
+			fmt.Fprintf(&b, " (%+.2f, %+.1f%%)", c.Delta, c.DeltaPct)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// RealogramChange is the shelf placement change of one SKU.
+// Shelf indexes are those of AnalyzeRealogram; -1 stands for unassigned products.
+type RealogramChange struct {
+	SkuId         int    `json:"sku_id"`
+	Name          string `json:"name,omitempty"`
+	Kind          string `json:"kind"` // added, removed or moved
+	ShelvesBefore []int  `json:"shelves_before,omitempty"`
+	ShelvesAfter  []int  `json:"shelves_after,omitempty"`
+	FacingsBefore int    `json:"facings_before"`
+	FacingsAfter  int    `json:"facings_after"`
+}
+
+// RealogramDiff is the change set between two realograms of the same display.
+type RealogramDiff struct {
+	ImageBefore int               `json:"image_before"`
+	ImageAfter  int               `json:"image_after"`
+	Changes     []RealogramChange `json:"changes"` // ordered by SKU ID
+}
+
+// skuPlacement is where a SKU stands on a realogram.
+type skuPlacement struct {
+	name    string
+	shelves map[int]bool
+	facings int
+}
+
+// DiffRealograms compares two photos of the same display: SKUs that appeared,
+// disappeared or stand on other shelves. Only front-row facings are compared;
+// facing count changes on the same shelves are left to DiffFacingCounts.
+func DiffRealograms(before, after ReportRealogramJson, opts *RealogramOptions) *RealogramDiff {
+	b, a := realogramPlacements(before, opts), realogramPlacements(after, opts)
+	d := &RealogramDiff{ImageBefore: before.Image, ImageAfter: after.Image}
+	for _, sku := range unionKeys(b, a) {
+		pb, pa := b[sku], a[sku]
+		c := RealogramChange{SkuId: sku}
+		if pb != nil {
+			c.Name, c.ShelvesBefore, c.FacingsBefore = pb.name, sortedKeys(pb.shelves), pb.facings
+		}
+		if pa != nil {
+			c.Name, c.ShelvesAfter, c.FacingsAfter = pa.name, sortedKeys(pa.shelves), pa.facings
+		}
+		switch {
+		case pb == nil:
+			c.Kind = ChangeADDED
+		case pa == nil:
+			c.Kind = ChangeREMOVED
+		case fmt.Sprint(c.ShelvesBefore) != fmt.Sprint(c.ShelvesAfter):
+			c.Kind = ChangeMOVED
+		default:
+			continue
+		}
+		d.Changes = append(d.Changes, c)
+	}
+	return d
+}
+
+func realogramPlacements(r ReportRealogramJson, opts *RealogramOptions) map[int]*skuPlacement {
+	includeDuplicates := opts != nil && opts.IncludeDuplicates
+	m := map[int]*skuPlacement{}
+	add := func(p ShelfProduct, shelf int) {
+		if p.Stacked || (p.Duplicate && !includeDuplicates) {
+			return
+		}
+		pl, ok := m[p.SkuId]
+		if !ok {
+			pl = &skuPlacement{name: p.Name, shelves: map[int]bool{}}
+			m[p.SkuId] = pl
+		}
+		pl.shelves[shelf] = true
+		pl.facings++
+	}
+	layout := AnalyzeRealogram(r, opts)
+	for _, s := range layout.Shelves {
+		for _, p := range s.Products {
+			add(p, s.Index)
+		}
+	}
+	for _, p := range layout.Unassigned {
+		add(p, -1)
+	}
+	return m
+}
+
+// Empty reports whether nothing changed.
+func (d *RealogramDiff) Empty() bool { return len(d.Changes) == 0 }
+
+// Summary describes the change set as text, one line per SKU.
+func (d *RealogramDiff) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "realogram %d -> %d: %d SKUs changed\n", d.ImageBefore, d.ImageAfter, len(d.Changes))
+	for _, c := range d.Changes {
+		fmt.Fprintf(&b, "  SKU %d %s", c.SkuId, c.Kind)
+		if c.Name != "" {
+			fmt.Fprintf(&b, " %q", c.Name)
+		}
+		switch c.Kind {
+		case ChangeADDED:
+			fmt.Fprintf(&b, ": shelves %v, %d facings\n", c.ShelvesAfter, c.FacingsAfter)
+		case ChangeREMOVED:
+			fmt.Fprintf(&b, ": was on shelves %v, %d facings\n", c.ShelvesBefore, c.FacingsBefore)
+		default:
+			fmt.Fprintf(&b, ": shelves %v -> %v\n", c.ShelvesBefore, c.ShelvesAfter)
+		}
+	}
+	return b.String()
+}
+
+// unionKeys returns the keys of both maps in ascending order.
+func unionKeys[V any](a, b map[int]V) []int {
+	seen := make(map[int]bool, len(a)+len(b))
+	for k := range a {
+		seen[k] = true
+	}
+	for k := range b {
+		seen[k] = true
+	}
+	return sortedKeys(seen)
+}
+
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
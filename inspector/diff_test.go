@@ -0,0 +1,114 @@
+package inspector
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffFacingCounts(t *testing.T) {
+	before := []ReportFacingCountJson{{SkuId: 1, Count: 2}, {SkuId: 2, Count: 3}, {SkuId: 3, Count: 1}}
+	after := []ReportFacingCountJson{{SkuId: 1, Count: 5}, {SkuId: 3, Count: 1}, {SkuId: 4, Count: 2}}
+
+	d := DiffFacingCounts(before, after)
+	assert.Equal(t, []FacingChange{
+		{SkuId: 1, Kind: ChangeCHANGED, Before: 2, After: 5, Delta: 3},
+		{SkuId: 2, Kind: ChangeREMOVED, Before: 3, After: 0, Delta: -3},
+		{SkuId: 4, Kind: ChangeADDED, Before: 0, After: 2, Delta: 2},
+	}, d.Changes)
+	assert.Equal(t, 5, d.Gained)
+	assert.Equal(t, 3, d.Lost)
+	assert.Equal(t, "facings: 3 SKUs changed, +5/-3\n"+
+		"  SKU 1 changed: 2 -> 5 (+3)\n"+
+		"  SKU 2 removed: 3 -> 0 (-3)\n"+
+		"  SKU 4 added: 0 -> 2 (+2)\n", d.Summary())
+
+	assert.True(t, DiffFacingCounts(before, before).Empty())
+}
+
+func TestDiffPriceTags(t *testing.T) {
+	before := []ReportPriceTagsJson{
+		{SkuId: 1, Name: "A", Price: 100},
+		{SkuId: 1, Name: "A", Price: 90}, // lowest tag represents the SKU
+		{SkuId: 2, Name: "B", Price: 50},
+		{SkuId: 3, Name: "C", Price: 10},
+		{SkuId: 5, Name: "E", Price: 70},
+	}
+	after := []ReportPriceTagsJson{
+		{SkuId: 1, Name: "A", Price: 99},
+		{SkuId: 2, Name: "B", Price: 50, Promo: "1"},
+		{SkuId: 4, Name: "D", Price: 20},
+		{SkuId: 5, Name: "E", Price: 70},
+	}
+
+	d := DiffPriceTags(before, after)
+	assert.Len(t, d.Changes, 4)
+	assert.Equal(t, ChangeCHANGED, d.Changes[0].Kind)
+	assert.InDelta(t, 9, d.Changes[0].Delta, 1e-9)
+	assert.InDelta(t, 10, d.Changes[0].DeltaPct, 1e-9)
+	assert.Equal(t, PriceChange{SkuId: 2, Name: "B", Kind: ChangeCHANGED, Before: d.Changes[1].Before, After: d.Changes[1].After, PromoAfter: true}, d.Changes[1])
+	assert.Equal(t, ChangeREMOVED, d.Changes[2].Kind)
+	assert.Nil(t, d.Changes[2].After)
+	assert.Equal(t, "C", d.Changes[2].Name)
+	assert.Equal(t, ChangeADDED, d.Changes[3].Kind)
+	assert.Nil(t, d.Changes[3].Before)
+
+	assert.Equal(t, "prices: 4 SKUs changed\n"+
+		"  SKU 1 changed: 90.00 -> 99.00 (+9.00, +10.0%)\n"+
+		"  SKU 2 changed: 50.00 -> 50.00 (promo)\n"+
+		"  SKU 3 removed: 10.00 -> -\n"+
+		"  SKU 4 added: - -> 20.00\n", d.Summary())
+
+	b, err := json.Marshal(d)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `{"sku_id":3,"name":"C","kind":"removed","before":10,"promo_before":false,"promo_after":false,"delta":0,"delta_pct":0}`)
+
+	t.Run("webhook promo change", func(t *testing.T) {
+		before := loadWebhookReports(t)
+		b, err := os.ReadFile("testdata/webhook_reports.json")
+		assert.NoError(t, err)
+		// the promo of SKU 9859 ends at the same price
+		var srv ReportService
+		after, err := srv.ParseWebhookReports(bytes.Replace(b, []byte(`"promo": true`), []byte(`"promo": false`), 1))
+		assert.NoError(t, err)
+
+		d := DiffPriceTags(before.Reports.PriceTags, after.Reports.PriceTags)
+		assert.Len(t, d.Changes, 1)
+		assert.Equal(t, 9859, d.Changes[0].SkuId)
+		assert.Equal(t, ChangeCHANGED, d.Changes[0].Kind)
+		assert.True(t, d.Changes[0].PromoBefore)
+		assert.False(t, d.Changes[0].PromoAfter)
+	})
+}
+
+func TestDiffRealograms(t *testing.T) {
+	product := func(i, shelf, sku int) ReportRealogramAnnotations {
+		return ReportRealogramAnnotations{X: 50 + 100*i, Y: 150 + 200*shelf, W: 100, H: 100, SkuId: sku, Name: "P"}
+	}
+	shelves := []ReportRealogramShelfAnnotations{{X1: 0, Y1: 200, X2: 1000, Y2: 200}, {X1: 0, Y1: 400, X2: 1000, Y2: 400}}
+	before := ReportRealogramJson{Image: 1, ShelfAnnotations: shelves, Annotations: []ReportRealogramAnnotations{
+		product(0, 0, 1), product(1, 0, 2), product(0, 1, 3),
+	}}
+	after := ReportRealogramJson{Image: 2, ShelfAnnotations: shelves, Annotations: []ReportRealogramAnnotations{
+		product(0, 0, 1), product(1, 0, 1), product(0, 1, 2), product(1, 1, 4),
+	}}
+
+	d := DiffRealograms(before, after, nil)
+	assert.Equal(t, []RealogramChange{
+		{SkuId: 2, Name: "P", Kind: ChangeMOVED, ShelvesBefore: []int{0}, ShelvesAfter: []int{1}, FacingsBefore: 1, FacingsAfter: 1},
+		{SkuId: 3, Name: "P", Kind: ChangeREMOVED, ShelvesBefore: []int{1}, FacingsBefore: 1},
+		{SkuId: 4, Name: "P", Kind: ChangeADDED, ShelvesAfter: []int{1}, FacingsAfter: 1},
+	}, d.Changes)
+	assert.Equal(t, "realogram 1 -> 2: 3 SKUs changed\n"+
+		"  SKU 2 moved \"P\": shelves [0] -> [1]\n"+
+		"  SKU 3 removed \"P\": was on shelves [1], 1 facings\n"+
+		"  SKU 4 added \"P\": shelves [1], 1 facings\n", d.Summary())
+
+	t.Run("same photo", func(t *testing.T) {
+		r := loadRealogramReport(t)[0]
+		assert.True(t, DiffRealograms(r, r, nil).Empty())
+	})
+}
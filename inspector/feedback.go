@@ -0,0 +1,252 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Recognition correction kinds
+const (
+	CorrectionWRONG_SKU      = "WRONG_SKU"      // annotated product is another SKU
+	CorrectionFALSE_POSITIVE = "FALSE_POSITIVE" // annotation is not a product
+	CorrectionMISSED         = "MISSED"         // product on the photo without annotation
+)
+
+// ErrInvalidCorrection is returned for corrections that do not match the realogram.
+var ErrInvalidCorrection = errors.New("invalid correction")
+
+// Correction is a reviewer's fix of one realogram box.
+type Correction struct {
+	Kind       string `json:"kind"`
+	Annotation int    `json:"annotation"`       // WRONG_SKU, FALSE_POSITIVE: index into ReportRealogramJson.Annotations
+	SkuId      int    `json:"sku_id,omitempty"` // WRONG_SKU, MISSED: the correct SKU
+	X          int    `json:"x,omitempty"`      // MISSED: box center and size, as in annotations
+	Y          int    `json:"y,omitempty"`
+	W          int    `json:"w,omitempty"`
+	H          int    `json:"h,omitempty"`
+	Note       string `json:"note,omitempty"` // reviewer comment appended to the message
+}
+
+// FeedbackOptions configures RecognitionFeedback.Submit.
+type FeedbackOptions struct {
+	Concurrency int // requests sent concurrently (default: DefaultFeedbackConcurrency)
+}
+
+// FeedbackResult is the outcome of one recognition error request.
+type FeedbackResult struct {
+	Request            *RecognitionErrorRequest `json:"request,omitempty"` // nil for invalid corrections
+	Corrections        []int                    `json:"corrections"`       // indexes of the covered corrections
+	RecognitionErrorID int                      `json:"recognition_error_id,omitempty"`
+	Err                error                    `json:"-"`
+}
+
+// FeedbackResults is the outcome of RecognitionFeedback.Submit.
+type FeedbackResults []FeedbackResult
+
+// IDs returns the IDs of the saved recognition errors.
+func (r FeedbackResults) IDs() []int {
+	var ids []int
+	for _, res := range r {
+		if res.Err == nil && res.RecognitionErrorID != 0 {
+			ids = append(ids, res.RecognitionErrorID)
+		}
+	}
+	return ids
+}
+
+// Err joins all per-item errors, nil if every correction was submitted.
+func (r FeedbackResults) Err() error {
+	var errs []error
+	for _, res := range r {
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("corrections %v: %w", res.Corrections, res.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RecognitionFeedback collects corrections of a realogram and turns them into
+// recognition error requests: one request per correction kind and SKU, listing
+// every box of that SKU in the message.
+type RecognitionFeedback struct {
+	Scene       string              // scene uuid, see RecognizeResponse.Scene
+	Realogram   ReportRealogramJson // the corrected realogram image
+	Corrections []Correction
+}
+
+// NewRecognitionFeedback makes a RecognitionFeedback for a realogram image of scene.
+func NewRecognitionFeedback(scene string, realogram ReportRealogramJson) *RecognitionFeedback {
+	return &RecognitionFeedback{Scene: scene, Realogram: realogram}
+}
+
+// WrongSKU records that annotation shows sku rather than the recognized SKU.
+func (f *RecognitionFeedback) WrongSKU(annotation, sku int, note string) *RecognitionFeedback {
+	f.Corrections = append(f.Corrections, Correction{Kind: CorrectionWRONG_SKU, Annotation: annotation, SkuId: sku, Note: note})
+	return f
+}
+
+// FalsePositive records that annotation is not a product.
+func (f *RecognitionFeedback) FalsePositive(annotation int, note string) *RecognitionFeedback {
+	f.Corrections = append(f.Corrections, Correction{Kind: CorrectionFALSE_POSITIVE, Annotation: annotation, Note: note})
+	return f
+}
+
+// Missed records a product of sku that was not recognized, boxed by center x, y and size w, h.
+func (f *RecognitionFeedback) Missed(sku, x, y, w, h int, note string) *RecognitionFeedback {
+	f.Corrections = append(f.Corrections, Correction{Kind: CorrectionMISSED, SkuId: sku, X: x, Y: y, W: w, H: h, Note: note})
+	return f
+}
+
+// feedbackKey identifies a request: the correction kind and the SKU it is about,
+// the recognized one or the missed one.
+type feedbackKey struct {
+	kind string
+	sku  int
+}
+
+// feedbackGroup is the corrections of one request.
+type feedbackGroup struct {
+	feedbackKey
+	items []int
+}
+
+// Build groups the corrections into requests. Invalid corrections get a result
+// with Err and no request; the others get a result with the request to send.
+func (f *RecognitionFeedback) Build() FeedbackResults {
+	var results FeedbackResults
+	groups := map[feedbackKey]*feedbackGroup{}
+	for i, c := range f.Corrections {
+		sku, err := f.subject(c)
+		if err != nil {
+			results = append(results, FeedbackResult{Corrections: []int{i}, Err: fmt.Errorf("correction %d:%w", i, err)})
+			continue
+		}
+		k := feedbackKey{kind: c.Kind, sku: sku}
+		g, ok := groups[k]
+		if !ok {
+			g = &feedbackGroup{feedbackKey: k}
+			groups[k] = g
+		}
+		g.items = append(g.items, i)
+	}
+
+	ordered := make([]*feedbackGroup, 0, len(groups))
+	for _, g := range groups {
+		ordered = append(ordered, g)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].items[0] < ordered[j].items[0] })
+	for _, g := range ordered {
+		results = append(results, FeedbackResult{
+			Request: &RecognitionErrorRequest{
+				Images:  []int{f.Realogram.Image},
+				SkuId:   g.sku,
+				Scene:   f.Scene,
+				Message: f.message(g),
+			},
+			Corrections: g.items,
+		})
+	}
+	return results
+}
+
+// subject validates c and returns the SKU its request is about.
+func (f *RecognitionFeedback) subject(c Correction) (int, error) {
+	switch c.Kind {
+	case CorrectionWRONG_SKU, CorrectionFALSE_POSITIVE:
+		if c.Annotation < 0 || c.Annotation >= len(f.Realogram.Annotations) {
+			return 0, fmt.Errorf("annotation %d out of range:%w", c.Annotation, ErrInvalidCorrection)
+		}
+		a := f.Realogram.Annotations[c.Annotation]
+		if c.Kind == CorrectionWRONG_SKU && (c.SkuId <= 0 || c.SkuId == a.SkuId) {
+			return 0, fmt.Errorf("annotation %d needs a correct SKU other than %d:%w", c.Annotation, a.SkuId, ErrInvalidCorrection)
+		}
+		return a.SkuId, nil
+	case CorrectionMISSED:
+		if c.SkuId <= 0 || c.W <= 0 || c.H <= 0 {
+			return 0, fmt.Errorf("missed product needs a SKU and a box:%w", ErrInvalidCorrection)
+		}
+		return c.SkuId, nil
+	}
+	return 0, fmt.Errorf("unknown kind %q:%w", c.Kind, ErrInvalidCorrection)
+}
+
+// message describes the corrections of g, one line per box.
+func (f *RecognitionFeedback) message(g *feedbackGroup) string {
+	box := func(x, y, w, h int) string { return fmt.Sprintf("box x=%d y=%d w=%d h=%d", x, y, w, h) }
+	var b strings.Builder
+	switch g.kind {
+	case CorrectionWRONG_SKU:
+		fmt.Fprintf(&b, "SKU %d recognized in place of other products on image %d:", g.sku, f.Realogram.Image)
+	case CorrectionFALSE_POSITIVE:
+		fmt.Fprintf(&b, "SKU %d recognized where there is no product on image %d:", g.sku, f.Realogram.Image)
+	case CorrectionMISSED:
+		fmt.Fprintf(&b, "SKU %d not recognized on image %d:", g.sku, f.Realogram.Image)
+	}
+	for _, i := range g.items {
+		c := f.Corrections[i]
+		b.WriteString("\n- ")
+		switch g.kind {
+		case CorrectionWRONG_SKU:
+			a := f.Realogram.Annotations[c.Annotation]
+			fmt.Fprintf(&b, "%s: %q is SKU %d", box(a.X, a.Y, a.W, a.H), a.Name, c.SkuId)
+		case CorrectionFALSE_POSITIVE:
+			a := f.Realogram.Annotations[c.Annotation]
+			fmt.Fprintf(&b, "%s: %q is not a product", box(a.X, a.Y, a.W, a.H), a.Name)
+		case CorrectionMISSED:
+			b.WriteString(box(c.X, c.Y, c.W, c.H))
+		}
+		if c.Note != "" {
+			fmt.Fprintf(&b, " (%s)", c.Note)
+		}
+	}
+	return b.String()
+}
+
+// Submit builds the requests and sends them concurrently. Per-item failures,
+// including invalid corrections, are recorded in the results; an error is
+// returned only when ctx is done.
+func (f *RecognitionFeedback) Submit(ctx context.Context, api RecognizeAPI, opts *FeedbackOptions) (FeedbackResults, error) {
+	concurrency := DefaultFeedbackConcurrency
+	if opts != nil && opts.Concurrency > 0 {
+		concurrency = opts.Concurrency
+	}
+
+	results := f.Build()
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range results {
+		if results[i].Request == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(res *FeedbackResult) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if err := ctx.Err(); err != nil {
+				res.Err = err
+				return
+			}
+			resp, err := api.RecognitionError(ctx, res.Request)
+			if err != nil {
+				res.Err = err
+				return
+			}
+			if resp == nil {
+				res.Err = errors.New("empty recognition error response")
+				return
+			}
+			res.RecognitionErrorID = resp.RecognitionErrorID
+		}(&results[i])
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return results, fmt.Errorf("failed to submit recognition feedback:%w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,119 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// feedbackRecognizer is a RecognizeAPI that saves recognition errors in memory.
+type feedbackRecognizer struct {
+	RecognizeAPI
+	mu       sync.Mutex
+	requests []*RecognitionErrorRequest
+	fail     int // SKU whose requests fail
+	empty    int // SKU whose requests get no response
+}
+
+func (f *feedbackRecognizer) RecognitionError(ctx context.Context, rr *RecognitionErrorRequest) (*RecognitionErrorResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if rr.SkuId == f.fail {
+		return nil, errors.New("bad request")
+	}
+	if rr.SkuId == f.empty {
+		return nil, nil
+	}
+	f.requests = append(f.requests, rr)
+	return &RecognitionErrorResponse{RecognitionErrorID: 1000 + rr.SkuId}, nil
+}
+
+func feedbackTestRealogram() ReportRealogramJson {
+	return ReportRealogramJson{
+		Image: 5,
+		Annotations: []ReportRealogramAnnotations{
+			{X: 50, Y: 100, W: 40, H: 80, SkuId: 1, Name: "Cola 0.5"},
+			{X: 90, Y: 100, W: 40, H: 80, SkuId: 1, Name: "Cola 0.5"},
+			{X: 130, Y: 100, W: 40, H: 80, SkuId: 2, Name: "Fanta 0.5"},
+			{X: 170, Y: 100, W: 40, H: 80, SkuId: 3, Name: "Sprite 0.5"},
+		},
+	}
+}
+
+func TestRecognitionFeedback_Build(t *testing.T) {
+	f := NewRecognitionFeedback("scene-1", feedbackTestRealogram()).
+		WrongSKU(0, 7, "").
+		FalsePositive(3, "price tag").
+		WrongSKU(1, 8, "zero sugar").
+		Missed(9, 210, 100, 40, 80, "").
+		WrongSKU(2, 2, "").
+		FalsePositive(12, "")
+
+	got := f.Build()
+	assert.Len(t, got, 5)
+
+	assert.Equal(t, []int{4}, got[0].Corrections)
+	assert.ErrorIs(t, got[0].Err, ErrInvalidCorrection)
+	assert.Nil(t, got[0].Request)
+	assert.Equal(t, []int{5}, got[1].Corrections)
+	assert.ErrorIs(t, got[1].Err, ErrInvalidCorrection)
+
+	assert.Equal(t, []int{0, 2}, got[2].Corrections)
+	assert.Equal(t, &RecognitionErrorRequest{
+		Images: []int{5},
+		SkuId:  1,
+		Scene:  "scene-1",
+		Message: "SKU 1 recognized in place of other products on image 5:\n" +
+			"- box x=50 y=100 w=40 h=80: \"Cola 0.5\" is SKU 7\n" +
+			"- box x=90 y=100 w=40 h=80: \"Cola 0.5\" is SKU 8 (zero sugar)",
+	}, got[2].Request)
+
+	assert.Equal(t, []int{1}, got[3].Corrections)
+	assert.Equal(t, 3, got[3].Request.SkuId)
+	assert.Equal(t, "SKU 3 recognized where there is no product on image 5:\n"+
+		"- box x=170 y=100 w=40 h=80: \"Sprite 0.5\" is not a product (price tag)", got[3].Request.Message)
+
+	assert.Equal(t, []int{3}, got[4].Corrections)
+	assert.Equal(t, 9, got[4].Request.SkuId)
+	assert.Equal(t, "SKU 9 not recognized on image 5:\n- box x=210 y=100 w=40 h=80", got[4].Request.Message)
+}
+
+func TestRecognitionFeedback_Submit(t *testing.T) {
+	f := NewRecognitionFeedback("scene-1", feedbackTestRealogram()).
+		WrongSKU(0, 7, "").
+		FalsePositive(2, "").
+		FalsePositive(3, "").
+		Missed(9, 210, 100, 40, 80, "").
+		Missed(0, 0, 0, 0, 0, "")
+
+	api := &feedbackRecognizer{fail: 3}
+	got, err := f.Submit(context.Background(), api, &FeedbackOptions{Concurrency: 2})
+	assert.NoError(t, err)
+	assert.Len(t, api.requests, 3)
+	assert.ElementsMatch(t, []int{1001, 1002, 1009}, got.IDs())
+
+	err = got.Err()
+	assert.ErrorIs(t, err, ErrInvalidCorrection)
+	assert.Contains(t, err.Error(), "corrections [2]: bad request")
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		api := &feedbackRecognizer{}
+		got, err := f.Submit(ctx, api, nil)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, api.requests)
+		assert.Empty(t, got.IDs())
+	})
+
+	t.Run("empty response", func(t *testing.T) {
+		api := &feedbackRecognizer{empty: 3}
+		got, err := f.Submit(context.Background(), api, nil)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []int{1001, 1002, 1009}, got.IDs())
+		assert.Contains(t, got.Err().Error(), "corrections [2]: empty recognition error response")
+	})
+}
@@ -0,0 +1,63 @@
+package inspector
+
+import (
+	"image"
+	"image/color"
+	"unicode"
+)
+
+// glyphs is a 3x5 bitmap font for PNG labels. Each row holds three bits, the
+// leftmost pixel being 4. Lowercase letters are drawn as uppercase; runes
+// without a glyph are drawn as '?'.
+var glyphs = map[rune][5]uint8{
+	' ': {0, 0, 0, 0, 0},
+	'0': {7, 5, 5, 5, 7}, '1': {2, 6, 2, 2, 7}, '2': {7, 1, 7, 4, 7}, '3': {7, 1, 3, 1, 7},
+	'4': {5, 5, 7, 1, 1}, '5': {7, 4, 7, 1, 7}, '6': {7, 4, 7, 5, 7}, '7': {7, 1, 1, 1, 1},
+	'8': {7, 5, 7, 5, 7}, '9': {7, 5, 7, 1, 7},
+	'A': {2, 5, 7, 5, 5}, 'B': {6, 5, 6, 5, 6}, 'C': {3, 4, 4, 4, 3}, 'D': {6, 5, 5, 5, 6},
+	'E': {7, 4, 6, 4, 7}, 'F': {7, 4, 6, 4, 4}, 'G': {3, 4, 5, 5, 3}, 'H': {5, 5, 7, 5, 5},
+	'I': {7, 2, 2, 2, 7}, 'J': {1, 1, 1, 5, 2}, 'K': {5, 5, 6, 5, 5}, 'L': {4, 4, 4, 4, 7},
+	'M': {5, 7, 7, 5, 5}, 'N': {6, 5, 5, 5, 5}, 'O': {2, 5, 5, 5, 2}, 'P': {6, 5, 6, 4, 4},
+	'Q': {2, 5, 5, 6, 3}, 'R': {6, 5, 6, 5, 5}, 'S': {3, 4, 2, 1, 6}, 'T': {7, 2, 2, 2, 2},
+	'U': {5, 5, 5, 5, 7}, 'V': {5, 5, 5, 5, 2}, 'W': {5, 5, 7, 7, 5}, 'X': {5, 5, 2, 5, 5},
+	'Y': {5, 5, 2, 2, 2}, 'Z': {7, 1, 2, 4, 7},
+	'-': {0, 0, 7, 0, 0}, '.': {0, 0, 0, 0, 2}, ',': {0, 0, 0, 2, 4}, ':': {0, 2, 0, 2, 0},
+	'/': {1, 1, 2, 4, 4}, '#': {5, 7, 5, 7, 5}, '%': {5, 1, 2, 4, 5}, '(': {1, 2, 2, 2, 1},
+	')': {4, 2, 2, 2, 4}, '+': {0, 2, 7, 2, 0}, '\'': {2, 2, 0, 0, 0}, '&': {2, 5, 2, 5, 3},
+	'?': {7, 1, 2, 0, 2},
+}
+
+// Glyph metrics in font pixels, including one pixel of spacing.
+const (
+	glyphAdvance = 4
+	glyphHeight  = 6
+)
+
+// textWidth returns the width of s in pixels at the given scale.
+func textWidth(s string, scale int) int {
+	n := 0
+	for range s {
+		n++
+	}
+	return n * glyphAdvance * scale
+}
+
+// drawText draws s with its top-left corner at (x, y) and returns the x after the last glyph.
+func drawText(dst *image.RGBA, x, y int, s string, scale int, c color.Color) int {
+	for _, r := range s {
+		g, ok := glyphs[unicode.ToUpper(r)]
+		if !ok {
+			g = glyphs['?']
+		}
+		for row, bits := range g {
+			for col := 0; col < 3; col++ {
+				if bits&(4>>col) == 0 {
+					continue
+				}
+				fillRect(dst, image.Rect(x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale), c)
+			}
+		}
+		x += glyphAdvance * scale
+	}
+	return x
+}
@@ -2,13 +2,22 @@ package inspector
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"image"
+	_ "image/jpeg" // register JPEG for Download and LoadImageFile
+	_ "image/png"  // register PNG for Download and LoadImageFile
 	"io"
+	"net/http"
+	"os"
 	"time"
 
 	httpclient "github.com/germangorelkin/http-client"
 )
 
+// ErrImageNoURL is returned by Download for images without a URL.
+var ErrImageNoURL = errors.New("image has no url")
+
 // ImageService provides access to the Image Uploads functions in the IC API.
 type ImageService struct {
 	client *Client
@@ -64,3 +73,60 @@ func (srv *ImageService) UploadByURL(ctx context.Context, url string) (Image, er
 
 	return img, nil
 }
+
+// GetImage requests an uploaded image by ID.
+func (srv *ImageService) GetImage(ctx context.Context, id int) (*Image, error) {
+	var img Image
+	if err := srv.client.getObject(ctx, fmt.Sprintf(endpointUploadByID, id), &img); err != nil {
+		return nil, err
+	}
+	return &img, nil
+}
+
+// ImageIterator provides paginated iteration over uploaded images.
+type ImageIterator = Iterator[Image]
+
+// IterateImages returns an iterator over uploaded images.
+// pageSize controls how many items are fetched per page (default: 100).
+func (srv *ImageService) IterateImages(ctx context.Context, pageSize int) ImageIterator {
+	return NewPaginator[Image](ctx, srv.client, endpointUploads, nil, pageSize)
+}
+
+// Download fetches and decodes the JPEG or PNG behind img.URL.
+// The request goes through the client's HTTP client without the API authorization header.
+func (srv *ImageService) Download(ctx context.Context, img Image) (image.Image, error) {
+	if img.URL == "" {
+		return nil, fmt.Errorf("failed to download image %d:%w", img.ID, ErrImageNoURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, methodGET, img.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to NewRequest(%s, %s):%w", methodGET, img.URL, err)
+	}
+	resp, err := srv.client.mediaClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download image %s:%w", img.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download image %s: status %d", img.URL, resp.StatusCode)
+	}
+	decoded, _, err := image.Decode(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image %s:%w", img.URL, err)
+	}
+	return decoded, nil
+}
+
+// LoadImageFile reads and decodes a local JPEG or PNG file.
+func LoadImageFile(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image %s:%w", path, err)
+	}
+	defer f.Close()
+	decoded, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image %s:%w", path, err)
+	}
+	return decoded, nil
+}
@@ -1,9 +1,12 @@
 package inspector
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"image"
+	"image/png"
 	"io"
 	"io/ioutil"
 	"mime"
@@ -116,3 +119,63 @@ func TestImageService_Upload(t *testing.T) {
 	}
 	assert.Equal(t, want, img)
 }
+
+func TestImageService_GetImage(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/uploads/156673/" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"detail":"Not found."}`)
+			return
+		}
+		assert.Equal(t, methodGET, r.Method)
+		fmt.Fprint(w, `{"id":156673,"url":"https://test.inspector-cloud.com/media/1.jpg","width":720,"height":1280}`)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClientConf{Instance: ts.URL})
+	assert.NoError(t, err)
+
+	img, err := client.Image.GetImage(context.Background(), 156673)
+	assert.NoError(t, err)
+	assert.Equal(t, &Image{ID: 156673, URL: "https://test.inspector-cloud.com/media/1.jpg", Width: 720, Height: 1280}, img)
+
+	_, err = client.Image.GetImage(context.Background(), 1)
+	assert.Error(t, err)
+}
+
+func TestImageService_Download(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, src))
+
+	var auth string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if r.URL.Path != "/media/1.png" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "secret"})
+	assert.NoError(t, err)
+
+	t.Run("decodes the image without sending the API key", func(t *testing.T) {
+		img, err := client.Image.Download(context.Background(), Image{ID: 1, URL: ts.URL + "/media/1.png"})
+		assert.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 4, 3), img.Bounds())
+		assert.Empty(t, auth)
+	})
+
+	t.Run("status error", func(t *testing.T) {
+		_, err := client.Image.Download(context.Background(), Image{ID: 2, URL: ts.URL + "/media/2.png"})
+		assert.Error(t, err)
+	})
+
+	t.Run("no url", func(t *testing.T) {
+		_, err := client.Image.Download(context.Background(), Image{ID: 3})
+		assert.ErrorIs(t, err, ErrImageNoURL)
+	})
+}
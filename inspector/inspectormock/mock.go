@@ -0,0 +1,142 @@
+// Package inspectormock provides mocks of the inspector service interfaces.
+//
+// Every mock records its calls and answers with the matching XxxFunc field.
+// Methods without a configured func return zero values and ErrUnexpectedCall
+// (Iterate methods return an iterator whose Next fails with it), except pure
+// converters (ToSku, ToFacingCount, DecodeReport, ...), which fall back to the
+// real implementation.
+//
+//	images := &inspectormock.ImageAPI{
+//		UploadByURLFunc: func(ctx context.Context, url string) (inspector.Image, error) {
+//			return inspector.Image{ID: 1, URL: url}, nil
+//		},
+//	}
+//	api := inspectormock.NewClient()
+//	api.Image = images
+//	// ... run code that takes an inspector.API ...
+//	calls := images.CallsTo("UploadByURL")
+package inspectormock
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+)
+
+// ErrUnexpectedCall is returned by mock methods whose func field is nil.
+var ErrUnexpectedCall = errors.New("unexpected call")
+
+func unexpected(method string) error {
+	return fmt.Errorf("inspectormock: %s:%w", method, ErrUnexpectedCall)
+}
+
+// unexpectedIterator returns an iterator whose Next fails with ErrUnexpectedCall.
+func unexpectedIterator[T any](method string) inspector.Iterator[T] {
+	return inspector.IteratorFunc[T](func() ([]T, error) {
+		return nil, unexpected(method)
+	})
+}
+
+// Call is a recorded method call.
+type Call struct {
+	Method string
+	Args   []any // arguments in order; variadic arguments are recorded as one slice
+}
+
+// Recorder records calls of a mock. It is safe for concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *Recorder) record(method string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns all recorded calls in order.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns the recorded calls of method in order.
+func (r *Recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// Client is a mock of inspector.API holding one mock per service.
+type Client struct {
+	Image        *ImageAPI
+	Recognize    *RecognizeAPI
+	Report       *ReportAPI
+	Sku          *SkuAPI
+	Visit        *VisitAPI
+	Shop         *ShopAPI
+	Brand        *BrandAPI
+	Category     *CategoryAPI
+	Manufacturer *ManufacturerAPI
+}
+
+var _ inspector.API = (*Client)(nil)
+
+// NewClient makes a Client with empty service mocks.
+func NewClient() *Client {
+	return &Client{
+		Image:        &ImageAPI{},
+		Recognize:    &RecognizeAPI{},
+		Report:       &ReportAPI{},
+		Sku:          &SkuAPI{},
+		Visit:        &VisitAPI{},
+		Shop:         &ShopAPI{},
+		Brand:        &BrandAPI{},
+		Category:     &CategoryAPI{},
+		Manufacturer: &ManufacturerAPI{},
+	}
+}
+
+// Images implements inspector.API.
+func (c *Client) Images() inspector.ImageAPI { return c.Image }
+
+// Recognizer implements inspector.API.
+func (c *Client) Recognizer() inspector.RecognizeAPI { return c.Recognize }
+
+// Reports implements inspector.API.
+func (c *Client) Reports() inspector.ReportAPI { return c.Report }
+
+// SKUs implements inspector.API.
+func (c *Client) SKUs() inspector.SkuAPI { return c.Sku }
+
+// Visits implements inspector.API.
+func (c *Client) Visits() inspector.VisitAPI { return c.Visit }
+
+// Shops implements inspector.API.
+func (c *Client) Shops() inspector.ShopAPI { return c.Shop }
+
+// Brands implements inspector.API.
+func (c *Client) Brands() inspector.BrandAPI { return c.Brand }
+
+// Categories implements inspector.API.
+func (c *Client) Categories() inspector.CategoryAPI { return c.Category }
+
+// Manufacturers implements inspector.API.
+func (c *Client) Manufacturers() inspector.ManufacturerAPI { return c.Manufacturer }
@@ -0,0 +1,119 @@
+package inspectormock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+	"github.com/stretchr/testify/assert"
+)
+
+// recognizeURL is application code written against inspector.API.
+func recognizeURL(ctx context.Context, api inspector.API, url string) (int, error) {
+	img, err := api.Images().UploadByURL(ctx, url)
+	if err != nil {
+		return 0, err
+	}
+	rec, err := api.Recognizer().Recognize(ctx, inspector.RecognizeRequest{
+		Images:      []int{img.ID},
+		ReportTypes: []string{inspector.ReportTypeFACING_COUNT},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rec.Reports[inspector.ReportTypeFACING_COUNT], nil
+}
+
+func TestClient_ProgrammedResponses(t *testing.T) {
+	api := NewClient()
+	api.Image.UploadByURLFunc = func(ctx context.Context, url string) (inspector.Image, error) {
+		return inspector.Image{ID: 11, URL: url}, nil
+	}
+	api.Recognize.RecognizeFunc = func(ctx context.Context, rr inspector.RecognizeRequest) (*inspector.RecognizeResponse, error) {
+		return &inspector.RecognizeResponse{ID: 1, Images: rr.Images, Reports: map[string]int{inspector.ReportTypeFACING_COUNT: 99}}, nil
+	}
+
+	reportID, err := recognizeURL(context.Background(), api, "https://example.com/a.jpg")
+	assert.NoError(t, err)
+	assert.Equal(t, 99, reportID)
+
+	calls := api.Image.CallsTo("UploadByURL")
+	assert.Len(t, calls, 1)
+	assert.Equal(t, "https://example.com/a.jpg", calls[0].Args[1])
+	rr := api.Recognize.Calls()[0].Args[1].(inspector.RecognizeRequest)
+	assert.Equal(t, []int{11}, rr.Images)
+
+	api.Image.Reset()
+	assert.Empty(t, api.Image.Calls())
+}
+
+func TestClient_UnexpectedCall(t *testing.T) {
+	api := NewClient()
+
+	_, err := recognizeURL(context.Background(), api, "https://example.com/a.jpg")
+	assert.True(t, errors.Is(err, ErrUnexpectedCall))
+	assert.Empty(t, api.Recognize.Calls())
+
+	assert.True(t, errors.Is(api.Sku.DeleteSKU(context.Background(), 1), ErrUnexpectedCall))
+	_, err = api.Sku.IterateSKU(context.Background(), 10).Next()
+	assert.True(t, errors.Is(err, ErrUnexpectedCall))
+}
+
+func TestClient_ProgrammedIterator(t *testing.T) {
+	api := NewClient()
+	api.Sku.IterateSKUFunc = func(ctx context.Context, pageSize int, query ...*inspector.SkuQuery) inspector.SKUIterator {
+		return inspector.SliceIterator([]inspector.Sku{{ID: 1}, {ID: 2}}, []inspector.Sku{{ID: 3}})
+	}
+
+	var ids []int
+	for sku, err := range api.SKUs().IterateSKU(context.Background(), 2).All() {
+		assert.NoError(t, err)
+		ids = append(ids, sku.ID)
+	}
+	assert.Equal(t, []int{1, 2, 3}, ids)
+
+	it := api.SKUs().IterateSKU(context.Background(), 2)
+	page, err := it.Next()
+	assert.NoError(t, err)
+	assert.Len(t, page, 2)
+	rest, err := it.Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []inspector.Sku{{ID: 3}}, rest)
+	page, err = it.Next()
+	assert.NoError(t, err)
+	assert.Nil(t, page)
+	assert.Len(t, api.Sku.CallsTo("IterateSKU"), 2)
+}
+
+func TestClient_ConvertersFallBackToRealImplementation(t *testing.T) {
+	api := NewClient()
+
+	fc, err := api.Reports().ToFacingCount([]map[string]any{{"sku_id": 7, "count": 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, []inspector.ReportFacingCountJson{{SkuId: 7, Count: 2}}, fc)
+
+	skus, err := api.SKUs().ToSku([]map[string]any{{"id": 1, "cid": "C1"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "C1", skus[0].CID)
+	assert.Len(t, api.Sku.CallsTo("ToSku"), 1)
+}
+
+func TestRecorder_Concurrent(t *testing.T) {
+	m := &VisitAPI{
+		GetVisitFunc: func(ctx context.Context, id int) (*inspector.Visit, error) {
+			return &inspector.Visit{ID: id}, nil
+		},
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			_, _ = m.GetVisit(context.Background(), id)
+		}(i)
+	}
+	wg.Wait()
+	assert.Len(t, m.CallsTo("GetVisit"), 20)
+}
@@ -0,0 +1,662 @@
+package inspectormock
+
+import (
+	"context"
+	"image"
+	"io"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+)
+
+// ImageAPI is a mock of inspector.ImageAPI.
+type ImageAPI struct {
+	Recorder
+	UploadFunc        func(ctx context.Context, r io.Reader, filename string) (inspector.Image, error)
+	UploadByURLFunc   func(ctx context.Context, url string) (inspector.Image, error)
+	GetImageFunc      func(ctx context.Context, id int) (*inspector.Image, error)
+	IterateImagesFunc func(ctx context.Context, pageSize int) inspector.ImageIterator
+	DownloadFunc      func(ctx context.Context, img inspector.Image) (image.Image, error)
+}
+
+var _ inspector.ImageAPI = (*ImageAPI)(nil)
+
+// Upload records the call and calls UploadFunc, or returns ErrUnexpectedCall if UploadFunc is nil.
+func (m *ImageAPI) Upload(ctx context.Context, r io.Reader, filename string) (inspector.Image, error) {
+	m.record("Upload", ctx, r, filename)
+	if m.UploadFunc != nil {
+		return m.UploadFunc(ctx, r, filename)
+	}
+	return inspector.Image{}, unexpected("ImageAPI.Upload")
+}
+
+// UploadByURL records the call and calls UploadByURLFunc, or returns ErrUnexpectedCall if UploadByURLFunc is nil.
+func (m *ImageAPI) UploadByURL(ctx context.Context, url string) (inspector.Image, error) {
+	m.record("UploadByURL", ctx, url)
+	if m.UploadByURLFunc != nil {
+		return m.UploadByURLFunc(ctx, url)
+	}
+	return inspector.Image{}, unexpected("ImageAPI.UploadByURL")
+}
+
+// GetImage records the call and calls GetImageFunc, or returns ErrUnexpectedCall if GetImageFunc is nil.
+func (m *ImageAPI) GetImage(ctx context.Context, id int) (*inspector.Image, error) {
+	m.record("GetImage", ctx, id)
+	if m.GetImageFunc != nil {
+		return m.GetImageFunc(ctx, id)
+	}
+	return nil, unexpected("ImageAPI.GetImage")
+}
+
+// IterateImages records the call and calls IterateImagesFunc, or returns an iterator failing with ErrUnexpectedCall if IterateImagesFunc is nil.
+func (m *ImageAPI) IterateImages(ctx context.Context, pageSize int) inspector.ImageIterator {
+	m.record("IterateImages", ctx, pageSize)
+	if m.IterateImagesFunc != nil {
+		return m.IterateImagesFunc(ctx, pageSize)
+	}
+	return unexpectedIterator[inspector.Image]("ImageAPI.IterateImages")
+}
+
+// Download records the call and calls DownloadFunc, or returns ErrUnexpectedCall if DownloadFunc is nil.
+func (m *ImageAPI) Download(ctx context.Context, img inspector.Image) (image.Image, error) {
+	m.record("Download", ctx, img)
+	if m.DownloadFunc != nil {
+		return m.DownloadFunc(ctx, img)
+	}
+	return nil, unexpected("ImageAPI.Download")
+}
+
+// RecognizeAPI is a mock of inspector.RecognizeAPI.
+type RecognizeAPI struct {
+	Recorder
+	RecognizeFunc        func(ctx context.Context, rr inspector.RecognizeRequest) (*inspector.RecognizeResponse, error)
+	RecognitionErrorFunc func(ctx context.Context, rr *inspector.RecognitionErrorRequest) (*inspector.RecognitionErrorResponse, error)
+}
+
+var _ inspector.RecognizeAPI = (*RecognizeAPI)(nil)
+
+// Recognize records the call and calls RecognizeFunc, or returns ErrUnexpectedCall if RecognizeFunc is nil.
+func (m *RecognizeAPI) Recognize(ctx context.Context, rr inspector.RecognizeRequest) (*inspector.RecognizeResponse, error) {
+	m.record("Recognize", ctx, rr)
+	if m.RecognizeFunc != nil {
+		return m.RecognizeFunc(ctx, rr)
+	}
+	return nil, unexpected("RecognizeAPI.Recognize")
+}
+
+// RecognitionError records the call and calls RecognitionErrorFunc, or returns ErrUnexpectedCall if RecognitionErrorFunc is nil.
+func (m *RecognizeAPI) RecognitionError(ctx context.Context, rr *inspector.RecognitionErrorRequest) (*inspector.RecognitionErrorResponse, error) {
+	m.record("RecognitionError", ctx, rr)
+	if m.RecognitionErrorFunc != nil {
+		return m.RecognitionErrorFunc(ctx, rr)
+	}
+	return nil, unexpected("RecognizeAPI.RecognitionError")
+}
+
+// ReportAPI is a mock of inspector.ReportAPI.
+type ReportAPI struct {
+	Recorder
+	GetReportFunc           func(ctx context.Context, id int) (*inspector.Report, error)
+	WaitForReportFunc       func(ctx context.Context, id int, opts *inspector.ReportWaitOptions) (*inspector.Report, error)
+	ToPriceTagsFunc         func(v any) ([]inspector.ReportPriceTagsJson, error)
+	ToFacingCountFunc       func(v any) ([]inspector.ReportFacingCountJson, error)
+	ToRealogramFunc         func(v any) ([]inspector.ReportRealogramJson, error)
+	ParseWebhookReportsFunc func(b []byte) (*inspector.WebhookReports, error)
+	DecodeReportFunc        func(report *inspector.Report) (any, error)
+}
+
+var _ inspector.ReportAPI = (*ReportAPI)(nil)
+
+// GetReport records the call and calls GetReportFunc, or returns ErrUnexpectedCall if GetReportFunc is nil.
+func (m *ReportAPI) GetReport(ctx context.Context, id int) (*inspector.Report, error) {
+	m.record("GetReport", ctx, id)
+	if m.GetReportFunc != nil {
+		return m.GetReportFunc(ctx, id)
+	}
+	return nil, unexpected("ReportAPI.GetReport")
+}
+
+// WaitForReport records the call and calls WaitForReportFunc, or returns ErrUnexpectedCall if WaitForReportFunc is nil.
+func (m *ReportAPI) WaitForReport(ctx context.Context, id int, opts *inspector.ReportWaitOptions) (*inspector.Report, error) {
+	m.record("WaitForReport", ctx, id, opts)
+	if m.WaitForReportFunc != nil {
+		return m.WaitForReportFunc(ctx, id, opts)
+	}
+	return nil, unexpected("ReportAPI.WaitForReport")
+}
+
+// ToPriceTags records the call and calls ToPriceTagsFunc, or the real ReportService.ToPriceTags if ToPriceTagsFunc is nil.
+func (m *ReportAPI) ToPriceTags(v any) ([]inspector.ReportPriceTagsJson, error) {
+	m.record("ToPriceTags", v)
+	if m.ToPriceTagsFunc != nil {
+		return m.ToPriceTagsFunc(v)
+	}
+	return (&inspector.ReportService{}).ToPriceTags(v)
+}
+
+// ToFacingCount records the call and calls ToFacingCountFunc, or the real ReportService.ToFacingCount if ToFacingCountFunc is nil.
+func (m *ReportAPI) ToFacingCount(v any) ([]inspector.ReportFacingCountJson, error) {
+	m.record("ToFacingCount", v)
+	if m.ToFacingCountFunc != nil {
+		return m.ToFacingCountFunc(v)
+	}
+	return (&inspector.ReportService{}).ToFacingCount(v)
+}
+
+// ToRealogram records the call and calls ToRealogramFunc, or the real ReportService.ToRealogram if ToRealogramFunc is nil.
+func (m *ReportAPI) ToRealogram(v any) ([]inspector.ReportRealogramJson, error) {
+	m.record("ToRealogram", v)
+	if m.ToRealogramFunc != nil {
+		return m.ToRealogramFunc(v)
+	}
+	return (&inspector.ReportService{}).ToRealogram(v)
+}
+
+// ParseWebhookReports records the call and calls ParseWebhookReportsFunc, or the real ReportService.ParseWebhookReports if ParseWebhookReportsFunc is nil.
+func (m *ReportAPI) ParseWebhookReports(b []byte) (*inspector.WebhookReports, error) {
+	m.record("ParseWebhookReports", b)
+	if m.ParseWebhookReportsFunc != nil {
+		return m.ParseWebhookReportsFunc(b)
+	}
+	return (&inspector.ReportService{}).ParseWebhookReports(b)
+}
+
+// DecodeReport records the call and calls DecodeReportFunc, or the real ReportService.DecodeReport if DecodeReportFunc is nil.
+func (m *ReportAPI) DecodeReport(report *inspector.Report) (any, error) {
+	m.record("DecodeReport", report)
+	if m.DecodeReportFunc != nil {
+		return m.DecodeReportFunc(report)
+	}
+	return (&inspector.ReportService{}).DecodeReport(report)
+}
+
+// SkuAPI is a mock of inspector.SkuAPI.
+type SkuAPI struct {
+	Recorder
+	GetSKUFunc            func(ctx context.Context, offset, limit int, query ...*inspector.SkuQuery) (*inspector.Pagination, error)
+	ToSkuFunc             func(v any) ([]inspector.Sku, error)
+	IterateSKUFunc        func(ctx context.Context, pageSize int, query ...*inspector.SkuQuery) inspector.SKUIterator
+	GetAllSKUFunc         func(ctx context.Context, pageSize int, query ...*inspector.SkuQuery) ([]inspector.Sku, error)
+	GetAllSKUParallelFunc func(ctx context.Context, opts *inspector.ParallelFetchOptions, query ...*inspector.SkuQuery) ([]inspector.Sku, error)
+	FindByEANFunc         func(ctx context.Context, ean13 string) ([]inspector.Sku, error)
+	FindByCIDFunc         func(ctx context.Context, cid string) ([]inspector.Sku, error)
+	GetSKUByIDFunc        func(ctx context.Context, id int) (*inspector.Sku, error)
+	CreateSKUFunc         func(ctx context.Context, sku inspector.Sku) (*inspector.Sku, error)
+	UpdateSKUFunc         func(ctx context.Context, sku inspector.Sku) (*inspector.Sku, error)
+	PatchSKUFunc          func(ctx context.Context, id int, patch inspector.SkuPatch) (*inspector.Sku, error)
+	DeleteSKUFunc         func(ctx context.Context, id int) error
+	UpsertSKUsFunc        func(ctx context.Context, desired []inspector.Sku, opts *inspector.SkuUpsertOptions) (*inspector.SkuUpsertReport, error)
+	ExportSKUFunc         func(ctx context.Context, w io.Writer, opts *inspector.SkuExportOptions) (int, error)
+}
+
+var _ inspector.SkuAPI = (*SkuAPI)(nil)
+
+// GetSKU records the call and calls GetSKUFunc, or returns ErrUnexpectedCall if GetSKUFunc is nil.
+func (m *SkuAPI) GetSKU(ctx context.Context, offset, limit int, query ...*inspector.SkuQuery) (*inspector.Pagination, error) {
+	m.record("GetSKU", ctx, offset, limit, query)
+	if m.GetSKUFunc != nil {
+		return m.GetSKUFunc(ctx, offset, limit, query...)
+	}
+	return nil, unexpected("SkuAPI.GetSKU")
+}
+
+// ToSku records the call and calls ToSkuFunc, or the real SkuService.ToSku if ToSkuFunc is nil.
+func (m *SkuAPI) ToSku(v any) ([]inspector.Sku, error) {
+	m.record("ToSku", v)
+	if m.ToSkuFunc != nil {
+		return m.ToSkuFunc(v)
+	}
+	return (&inspector.SkuService{}).ToSku(v)
+}
+
+// IterateSKU records the call and calls IterateSKUFunc, or returns an iterator failing with ErrUnexpectedCall if IterateSKUFunc is nil.
+func (m *SkuAPI) IterateSKU(ctx context.Context, pageSize int, query ...*inspector.SkuQuery) inspector.SKUIterator {
+	m.record("IterateSKU", ctx, pageSize, query)
+	if m.IterateSKUFunc != nil {
+		return m.IterateSKUFunc(ctx, pageSize, query...)
+	}
+	return unexpectedIterator[inspector.Sku]("SkuAPI.IterateSKU")
+}
+
+// GetAllSKU records the call and calls GetAllSKUFunc, or returns ErrUnexpectedCall if GetAllSKUFunc is nil.
+func (m *SkuAPI) GetAllSKU(ctx context.Context, pageSize int, query ...*inspector.SkuQuery) ([]inspector.Sku, error) {
+	m.record("GetAllSKU", ctx, pageSize, query)
+	if m.GetAllSKUFunc != nil {
+		return m.GetAllSKUFunc(ctx, pageSize, query...)
+	}
+	return nil, unexpected("SkuAPI.GetAllSKU")
+}
+
+// GetAllSKUParallel records the call and calls GetAllSKUParallelFunc, or returns ErrUnexpectedCall if GetAllSKUParallelFunc is nil.
+func (m *SkuAPI) GetAllSKUParallel(ctx context.Context, opts *inspector.ParallelFetchOptions, query ...*inspector.SkuQuery) ([]inspector.Sku, error) {
+	m.record("GetAllSKUParallel", ctx, opts, query)
+	if m.GetAllSKUParallelFunc != nil {
+		return m.GetAllSKUParallelFunc(ctx, opts, query...)
+	}
+	return nil, unexpected("SkuAPI.GetAllSKUParallel")
+}
+
+// FindByEAN records the call and calls FindByEANFunc, or returns ErrUnexpectedCall if FindByEANFunc is nil.
+func (m *SkuAPI) FindByEAN(ctx context.Context, ean13 string) ([]inspector.Sku, error) {
+	m.record("FindByEAN", ctx, ean13)
+	if m.FindByEANFunc != nil {
+		return m.FindByEANFunc(ctx, ean13)
+	}
+	return nil, unexpected("SkuAPI.FindByEAN")
+}
+
+// FindByCID records the call and calls FindByCIDFunc, or returns ErrUnexpectedCall if FindByCIDFunc is nil.
+func (m *SkuAPI) FindByCID(ctx context.Context, cid string) ([]inspector.Sku, error) {
+	m.record("FindByCID", ctx, cid)
+	if m.FindByCIDFunc != nil {
+		return m.FindByCIDFunc(ctx, cid)
+	}
+	return nil, unexpected("SkuAPI.FindByCID")
+}
+
+// GetSKUByID records the call and calls GetSKUByIDFunc, or returns ErrUnexpectedCall if GetSKUByIDFunc is nil.
+func (m *SkuAPI) GetSKUByID(ctx context.Context, id int) (*inspector.Sku, error) {
+	m.record("GetSKUByID", ctx, id)
+	if m.GetSKUByIDFunc != nil {
+		return m.GetSKUByIDFunc(ctx, id)
+	}
+	return nil, unexpected("SkuAPI.GetSKUByID")
+}
+
+// CreateSKU records the call and calls CreateSKUFunc, or returns ErrUnexpectedCall if CreateSKUFunc is nil.
+func (m *SkuAPI) CreateSKU(ctx context.Context, sku inspector.Sku) (*inspector.Sku, error) {
+	m.record("CreateSKU", ctx, sku)
+	if m.CreateSKUFunc != nil {
+		return m.CreateSKUFunc(ctx, sku)
+	}
+	return nil, unexpected("SkuAPI.CreateSKU")
+}
+
+// UpdateSKU records the call and calls UpdateSKUFunc, or returns ErrUnexpectedCall if UpdateSKUFunc is nil.
+func (m *SkuAPI) UpdateSKU(ctx context.Context, sku inspector.Sku) (*inspector.Sku, error) {
+	m.record("UpdateSKU", ctx, sku)
+	if m.UpdateSKUFunc != nil {
+		return m.UpdateSKUFunc(ctx, sku)
+	}
+	return nil, unexpected("SkuAPI.UpdateSKU")
+}
+
+// PatchSKU records the call and calls PatchSKUFunc, or returns ErrUnexpectedCall if PatchSKUFunc is nil.
+func (m *SkuAPI) PatchSKU(ctx context.Context, id int, patch inspector.SkuPatch) (*inspector.Sku, error) {
+	m.record("PatchSKU", ctx, id, patch)
+	if m.PatchSKUFunc != nil {
+		return m.PatchSKUFunc(ctx, id, patch)
+	}
+	return nil, unexpected("SkuAPI.PatchSKU")
+}
+
+// DeleteSKU records the call and calls DeleteSKUFunc, or returns ErrUnexpectedCall if DeleteSKUFunc is nil.
+func (m *SkuAPI) DeleteSKU(ctx context.Context, id int) error {
+	m.record("DeleteSKU", ctx, id)
+	if m.DeleteSKUFunc != nil {
+		return m.DeleteSKUFunc(ctx, id)
+	}
+	return unexpected("SkuAPI.DeleteSKU")
+}
+
+// UpsertSKUs records the call and calls UpsertSKUsFunc, or returns ErrUnexpectedCall if UpsertSKUsFunc is nil.
+func (m *SkuAPI) UpsertSKUs(ctx context.Context, desired []inspector.Sku, opts *inspector.SkuUpsertOptions) (*inspector.SkuUpsertReport, error) {
+	m.record("UpsertSKUs", ctx, desired, opts)
+	if m.UpsertSKUsFunc != nil {
+		return m.UpsertSKUsFunc(ctx, desired, opts)
+	}
+	return nil, unexpected("SkuAPI.UpsertSKUs")
+}
+
+// ExportSKU records the call and calls ExportSKUFunc, or returns ErrUnexpectedCall if ExportSKUFunc is nil.
+func (m *SkuAPI) ExportSKU(ctx context.Context, w io.Writer, opts *inspector.SkuExportOptions) (int, error) {
+	m.record("ExportSKU", ctx, w, opts)
+	if m.ExportSKUFunc != nil {
+		return m.ExportSKUFunc(ctx, w, opts)
+	}
+	return 0, unexpected("SkuAPI.ExportSKU")
+}
+
+// VisitAPI is a mock of inspector.VisitAPI.
+type VisitAPI struct {
+	Recorder
+	AddVisitFunc             func(ctx context.Context) (*inspector.Visit, error)
+	CreateVisitFunc          func(ctx context.Context, vr inspector.VisitCreateRequest) (*inspector.Visit, error)
+	GetVisitFunc             func(ctx context.Context, id int) (*inspector.Visit, error)
+	UpdateVisitFunc          func(ctx context.Context, id int, vr inspector.VisitUpdateRequest) (*inspector.Visit, error)
+	DeleteVisitFunc          func(ctx context.Context, id int) error
+	IterateVisitsFunc        func(ctx context.Context, pageSize int, query ...*inspector.VisitQuery) inspector.VisitIterator
+	ListVisitsFunc           func(ctx context.Context, query *inspector.VisitQuery) ([]inspector.Visit, error)
+	GetVisitReportsFunc      func(ctx context.Context, visitID int) ([]inspector.Report, error)
+	GetVisitRecognitionsFunc func(ctx context.Context, visitID int) ([]inspector.RecognizeResponse, error)
+}
+
+var _ inspector.VisitAPI = (*VisitAPI)(nil)
+
+// AddVisit records the call and calls AddVisitFunc, or returns ErrUnexpectedCall if AddVisitFunc is nil.
+func (m *VisitAPI) AddVisit(ctx context.Context) (*inspector.Visit, error) {
+	m.record("AddVisit", ctx)
+	if m.AddVisitFunc != nil {
+		return m.AddVisitFunc(ctx)
+	}
+	return nil, unexpected("VisitAPI.AddVisit")
+}
+
+// CreateVisit records the call and calls CreateVisitFunc, or returns ErrUnexpectedCall if CreateVisitFunc is nil.
+func (m *VisitAPI) CreateVisit(ctx context.Context, vr inspector.VisitCreateRequest) (*inspector.Visit, error) {
+	m.record("CreateVisit", ctx, vr)
+	if m.CreateVisitFunc != nil {
+		return m.CreateVisitFunc(ctx, vr)
+	}
+	return nil, unexpected("VisitAPI.CreateVisit")
+}
+
+// GetVisit records the call and calls GetVisitFunc, or returns ErrUnexpectedCall if GetVisitFunc is nil.
+func (m *VisitAPI) GetVisit(ctx context.Context, id int) (*inspector.Visit, error) {
+	m.record("GetVisit", ctx, id)
+	if m.GetVisitFunc != nil {
+		return m.GetVisitFunc(ctx, id)
+	}
+	return nil, unexpected("VisitAPI.GetVisit")
+}
+
+// UpdateVisit records the call and calls UpdateVisitFunc, or returns ErrUnexpectedCall if UpdateVisitFunc is nil.
+func (m *VisitAPI) UpdateVisit(ctx context.Context, id int, vr inspector.VisitUpdateRequest) (*inspector.Visit, error) {
+	m.record("UpdateVisit", ctx, id, vr)
+	if m.UpdateVisitFunc != nil {
+		return m.UpdateVisitFunc(ctx, id, vr)
+	}
+	return nil, unexpected("VisitAPI.UpdateVisit")
+}
+
+// DeleteVisit records the call and calls DeleteVisitFunc, or returns ErrUnexpectedCall if DeleteVisitFunc is nil.
+func (m *VisitAPI) DeleteVisit(ctx context.Context, id int) error {
+	m.record("DeleteVisit", ctx, id)
+	if m.DeleteVisitFunc != nil {
+		return m.DeleteVisitFunc(ctx, id)
+	}
+	return unexpected("VisitAPI.DeleteVisit")
+}
+
+// IterateVisits records the call and calls IterateVisitsFunc, or returns an iterator failing with ErrUnexpectedCall if IterateVisitsFunc is nil.
+func (m *VisitAPI) IterateVisits(ctx context.Context, pageSize int, query ...*inspector.VisitQuery) inspector.VisitIterator {
+	m.record("IterateVisits", ctx, pageSize, query)
+	if m.IterateVisitsFunc != nil {
+		return m.IterateVisitsFunc(ctx, pageSize, query...)
+	}
+	return unexpectedIterator[inspector.Visit]("VisitAPI.IterateVisits")
+}
+
+// ListVisits records the call and calls ListVisitsFunc, or returns ErrUnexpectedCall if ListVisitsFunc is nil.
+func (m *VisitAPI) ListVisits(ctx context.Context, query *inspector.VisitQuery) ([]inspector.Visit, error) {
+	m.record("ListVisits", ctx, query)
+	if m.ListVisitsFunc != nil {
+		return m.ListVisitsFunc(ctx, query)
+	}
+	return nil, unexpected("VisitAPI.ListVisits")
+}
+
+// GetVisitReports records the call and calls GetVisitReportsFunc, or returns ErrUnexpectedCall if GetVisitReportsFunc is nil.
+func (m *VisitAPI) GetVisitReports(ctx context.Context, visitID int) ([]inspector.Report, error) {
+	m.record("GetVisitReports", ctx, visitID)
+	if m.GetVisitReportsFunc != nil {
+		return m.GetVisitReportsFunc(ctx, visitID)
+	}
+	return nil, unexpected("VisitAPI.GetVisitReports")
+}
+
+// GetVisitRecognitions records the call and calls GetVisitRecognitionsFunc, or returns ErrUnexpectedCall if GetVisitRecognitionsFunc is nil.
+func (m *VisitAPI) GetVisitRecognitions(ctx context.Context, visitID int) ([]inspector.RecognizeResponse, error) {
+	m.record("GetVisitRecognitions", ctx, visitID)
+	if m.GetVisitRecognitionsFunc != nil {
+		return m.GetVisitRecognitionsFunc(ctx, visitID)
+	}
+	return nil, unexpected("VisitAPI.GetVisitRecognitions")
+}
+
+// ShopAPI is a mock of inspector.ShopAPI.
+type ShopAPI struct {
+	Recorder
+	CreateShopFunc            func(ctx context.Context, shop inspector.Shop) (*inspector.Shop, error)
+	GetShopFunc               func(ctx context.Context, id int) (*inspector.Shop, error)
+	UpdateShopFunc            func(ctx context.Context, shop inspector.Shop) (*inspector.Shop, error)
+	DeleteShopFunc            func(ctx context.Context, id int) error
+	IterateShopsFunc          func(ctx context.Context, pageSize int, query ...*inspector.ShopQuery) inspector.ShopIterator
+	ListShopsFunc             func(ctx context.Context, query *inspector.ShopQuery) ([]inspector.Shop, error)
+	GetShopByExternalCodeFunc func(ctx context.Context, code string) (*inspector.Shop, error)
+}
+
+var _ inspector.ShopAPI = (*ShopAPI)(nil)
+
+// CreateShop records the call and calls CreateShopFunc, or returns ErrUnexpectedCall if CreateShopFunc is nil.
+func (m *ShopAPI) CreateShop(ctx context.Context, shop inspector.Shop) (*inspector.Shop, error) {
+	m.record("CreateShop", ctx, shop)
+	if m.CreateShopFunc != nil {
+		return m.CreateShopFunc(ctx, shop)
+	}
+	return nil, unexpected("ShopAPI.CreateShop")
+}
+
+// GetShop records the call and calls GetShopFunc, or returns ErrUnexpectedCall if GetShopFunc is nil.
+func (m *ShopAPI) GetShop(ctx context.Context, id int) (*inspector.Shop, error) {
+	m.record("GetShop", ctx, id)
+	if m.GetShopFunc != nil {
+		return m.GetShopFunc(ctx, id)
+	}
+	return nil, unexpected("ShopAPI.GetShop")
+}
+
+// UpdateShop records the call and calls UpdateShopFunc, or returns ErrUnexpectedCall if UpdateShopFunc is nil.
+func (m *ShopAPI) UpdateShop(ctx context.Context, shop inspector.Shop) (*inspector.Shop, error) {
+	m.record("UpdateShop", ctx, shop)
+	if m.UpdateShopFunc != nil {
+		return m.UpdateShopFunc(ctx, shop)
+	}
+	return nil, unexpected("ShopAPI.UpdateShop")
+}
+
+// DeleteShop records the call and calls DeleteShopFunc, or returns ErrUnexpectedCall if DeleteShopFunc is nil.
+func (m *ShopAPI) DeleteShop(ctx context.Context, id int) error {
+	m.record("DeleteShop", ctx, id)
+	if m.DeleteShopFunc != nil {
+		return m.DeleteShopFunc(ctx, id)
+	}
+	return unexpected("ShopAPI.DeleteShop")
+}
+
+// IterateShops records the call and calls IterateShopsFunc, or returns an iterator failing with ErrUnexpectedCall if IterateShopsFunc is nil.
+func (m *ShopAPI) IterateShops(ctx context.Context, pageSize int, query ...*inspector.ShopQuery) inspector.ShopIterator {
+	m.record("IterateShops", ctx, pageSize, query)
+	if m.IterateShopsFunc != nil {
+		return m.IterateShopsFunc(ctx, pageSize, query...)
+	}
+	return unexpectedIterator[inspector.Shop]("ShopAPI.IterateShops")
+}
+
+// ListShops records the call and calls ListShopsFunc, or returns ErrUnexpectedCall if ListShopsFunc is nil.
+func (m *ShopAPI) ListShops(ctx context.Context, query *inspector.ShopQuery) ([]inspector.Shop, error) {
+	m.record("ListShops", ctx, query)
+	if m.ListShopsFunc != nil {
+		return m.ListShopsFunc(ctx, query)
+	}
+	return nil, unexpected("ShopAPI.ListShops")
+}
+
+// GetShopByExternalCode records the call and calls GetShopByExternalCodeFunc, or returns ErrUnexpectedCall if GetShopByExternalCodeFunc is nil.
+func (m *ShopAPI) GetShopByExternalCode(ctx context.Context, code string) (*inspector.Shop, error) {
+	m.record("GetShopByExternalCode", ctx, code)
+	if m.GetShopByExternalCodeFunc != nil {
+		return m.GetShopByExternalCodeFunc(ctx, code)
+	}
+	return nil, unexpected("ShopAPI.GetShopByExternalCode")
+}
+
+// BrandAPI is a mock of inspector.BrandAPI.
+type BrandAPI struct {
+	Recorder
+	GetBrandsFunc     func(ctx context.Context, offset, limit int) (*inspector.Pagination, error)
+	ToBrandsFunc      func(v any) ([]inspector.Brand, error)
+	IterateBrandsFunc func(ctx context.Context, pageSize int) inspector.BrandIterator
+	GetAllBrandsFunc  func(ctx context.Context, pageSize int) ([]inspector.Brand, error)
+	GetBrandFunc      func(ctx context.Context, id int) (*inspector.Brand, error)
+}
+
+var _ inspector.BrandAPI = (*BrandAPI)(nil)
+
+// GetBrands records the call and calls GetBrandsFunc, or returns ErrUnexpectedCall if GetBrandsFunc is nil.
+func (m *BrandAPI) GetBrands(ctx context.Context, offset, limit int) (*inspector.Pagination, error) {
+	m.record("GetBrands", ctx, offset, limit)
+	if m.GetBrandsFunc != nil {
+		return m.GetBrandsFunc(ctx, offset, limit)
+	}
+	return nil, unexpected("BrandAPI.GetBrands")
+}
+
+// ToBrands records the call and calls ToBrandsFunc, or the real BrandService.ToBrands if ToBrandsFunc is nil.
+func (m *BrandAPI) ToBrands(v any) ([]inspector.Brand, error) {
+	m.record("ToBrands", v)
+	if m.ToBrandsFunc != nil {
+		return m.ToBrandsFunc(v)
+	}
+	return (&inspector.BrandService{}).ToBrands(v)
+}
+
+// IterateBrands records the call and calls IterateBrandsFunc, or returns an iterator failing with ErrUnexpectedCall if IterateBrandsFunc is nil.
+func (m *BrandAPI) IterateBrands(ctx context.Context, pageSize int) inspector.BrandIterator {
+	m.record("IterateBrands", ctx, pageSize)
+	if m.IterateBrandsFunc != nil {
+		return m.IterateBrandsFunc(ctx, pageSize)
+	}
+	return unexpectedIterator[inspector.Brand]("BrandAPI.IterateBrands")
+}
+
+// GetAllBrands records the call and calls GetAllBrandsFunc, or returns ErrUnexpectedCall if GetAllBrandsFunc is nil.
+func (m *BrandAPI) GetAllBrands(ctx context.Context, pageSize int) ([]inspector.Brand, error) {
+	m.record("GetAllBrands", ctx, pageSize)
+	if m.GetAllBrandsFunc != nil {
+		return m.GetAllBrandsFunc(ctx, pageSize)
+	}
+	return nil, unexpected("BrandAPI.GetAllBrands")
+}
+
+// GetBrand records the call and calls GetBrandFunc, or returns ErrUnexpectedCall if GetBrandFunc is nil.
+func (m *BrandAPI) GetBrand(ctx context.Context, id int) (*inspector.Brand, error) {
+	m.record("GetBrand", ctx, id)
+	if m.GetBrandFunc != nil {
+		return m.GetBrandFunc(ctx, id)
+	}
+	return nil, unexpected("BrandAPI.GetBrand")
+}
+
+// CategoryAPI is a mock of inspector.CategoryAPI.
+type CategoryAPI struct {
+	Recorder
+	GetCategoriesFunc     func(ctx context.Context, offset, limit int) (*inspector.Pagination, error)
+	ToCategoriesFunc      func(v any) ([]inspector.Category, error)
+	IterateCategoriesFunc func(ctx context.Context, pageSize int) inspector.CategoryIterator
+	GetAllCategoriesFunc  func(ctx context.Context, pageSize int) ([]inspector.Category, error)
+	GetCategoryFunc       func(ctx context.Context, id int) (*inspector.Category, error)
+}
+
+var _ inspector.CategoryAPI = (*CategoryAPI)(nil)
+
+// GetCategories records the call and calls GetCategoriesFunc, or returns ErrUnexpectedCall if GetCategoriesFunc is nil.
+func (m *CategoryAPI) GetCategories(ctx context.Context, offset, limit int) (*inspector.Pagination, error) {
+	m.record("GetCategories", ctx, offset, limit)
+	if m.GetCategoriesFunc != nil {
+		return m.GetCategoriesFunc(ctx, offset, limit)
+	}
+	return nil, unexpected("CategoryAPI.GetCategories")
+}
+
+// ToCategories records the call and calls ToCategoriesFunc, or the real CategoryService.ToCategories if ToCategoriesFunc is nil.
+func (m *CategoryAPI) ToCategories(v any) ([]inspector.Category, error) {
+	m.record("ToCategories", v)
+	if m.ToCategoriesFunc != nil {
+		return m.ToCategoriesFunc(v)
+	}
+	return (&inspector.CategoryService{}).ToCategories(v)
+}
+
+// IterateCategories records the call and calls IterateCategoriesFunc, or returns an iterator failing with ErrUnexpectedCall if IterateCategoriesFunc is nil.
+func (m *CategoryAPI) IterateCategories(ctx context.Context, pageSize int) inspector.CategoryIterator {
+	m.record("IterateCategories", ctx, pageSize)
+	if m.IterateCategoriesFunc != nil {
+		return m.IterateCategoriesFunc(ctx, pageSize)
+	}
+	return unexpectedIterator[inspector.Category]("CategoryAPI.IterateCategories")
+}
+
+// GetAllCategories records the call and calls GetAllCategoriesFunc, or returns ErrUnexpectedCall if GetAllCategoriesFunc is nil.
+func (m *CategoryAPI) GetAllCategories(ctx context.Context, pageSize int) ([]inspector.Category, error) {
+	m.record("GetAllCategories", ctx, pageSize)
+	if m.GetAllCategoriesFunc != nil {
+		return m.GetAllCategoriesFunc(ctx, pageSize)
+	}
+	return nil, unexpected("CategoryAPI.GetAllCategories")
+}
+
+// GetCategory records the call and calls GetCategoryFunc, or returns ErrUnexpectedCall if GetCategoryFunc is nil.
+func (m *CategoryAPI) GetCategory(ctx context.Context, id int) (*inspector.Category, error) {
+	m.record("GetCategory", ctx, id)
+	if m.GetCategoryFunc != nil {
+		return m.GetCategoryFunc(ctx, id)
+	}
+	return nil, unexpected("CategoryAPI.GetCategory")
+}
+
+// ManufacturerAPI is a mock of inspector.ManufacturerAPI.
+type ManufacturerAPI struct {
+	Recorder
+	GetManufacturersFunc     func(ctx context.Context, offset, limit int) (*inspector.Pagination, error)
+	ToManufacturersFunc      func(v any) ([]inspector.Manufacturer, error)
+	IterateManufacturersFunc func(ctx context.Context, pageSize int) inspector.ManufacturerIterator
+	GetAllManufacturersFunc  func(ctx context.Context, pageSize int) ([]inspector.Manufacturer, error)
+	GetManufacturerFunc      func(ctx context.Context, id int) (*inspector.Manufacturer, error)
+}
+
+var _ inspector.ManufacturerAPI = (*ManufacturerAPI)(nil)
+
+// GetManufacturers records the call and calls GetManufacturersFunc, or returns ErrUnexpectedCall if GetManufacturersFunc is nil.
+func (m *ManufacturerAPI) GetManufacturers(ctx context.Context, offset, limit int) (*inspector.Pagination, error) {
+	m.record("GetManufacturers", ctx, offset, limit)
+	if m.GetManufacturersFunc != nil {
+		return m.GetManufacturersFunc(ctx, offset, limit)
+	}
+	return nil, unexpected("ManufacturerAPI.GetManufacturers")
+}
+
+// ToManufacturers records the call and calls ToManufacturersFunc, or the real ManufacturerService.ToManufacturers if ToManufacturersFunc is nil.
+func (m *ManufacturerAPI) ToManufacturers(v any) ([]inspector.Manufacturer, error) {
+	m.record("ToManufacturers", v)
+	if m.ToManufacturersFunc != nil {
+		return m.ToManufacturersFunc(v)
+	}
+	return (&inspector.ManufacturerService{}).ToManufacturers(v)
+}
+
+// IterateManufacturers records the call and calls IterateManufacturersFunc, or returns an iterator failing with ErrUnexpectedCall if IterateManufacturersFunc is nil.
+func (m *ManufacturerAPI) IterateManufacturers(ctx context.Context, pageSize int) inspector.ManufacturerIterator {
+	m.record("IterateManufacturers", ctx, pageSize)
+	if m.IterateManufacturersFunc != nil {
+		return m.IterateManufacturersFunc(ctx, pageSize)
+	}
+	return unexpectedIterator[inspector.Manufacturer]("ManufacturerAPI.IterateManufacturers")
+}
+
+// GetAllManufacturers records the call and calls GetAllManufacturersFunc, or returns ErrUnexpectedCall if GetAllManufacturersFunc is nil.
+func (m *ManufacturerAPI) GetAllManufacturers(ctx context.Context, pageSize int) ([]inspector.Manufacturer, error) {
+	m.record("GetAllManufacturers", ctx, pageSize)
+	if m.GetAllManufacturersFunc != nil {
+		return m.GetAllManufacturersFunc(ctx, pageSize)
+	}
+	return nil, unexpected("ManufacturerAPI.GetAllManufacturers")
+}
+
+// GetManufacturer records the call and calls GetManufacturerFunc, or returns ErrUnexpectedCall if GetManufacturerFunc is nil.
+func (m *ManufacturerAPI) GetManufacturer(ctx context.Context, id int) (*inspector.Manufacturer, error) {
+	m.record("GetManufacturer", ctx, id)
+	if m.GetManufacturerFunc != nil {
+		return m.GetManufacturerFunc(ctx, id)
+	}
+	return nil, unexpected("ManufacturerAPI.GetManufacturer")
+}
@@ -0,0 +1,82 @@
+package inspectortest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fault describes a failure injected into matching requests.
+// Latency is applied first; then Malformed or Status decide the response.
+// A fault with only Latency set delays the request and lets it through.
+type Fault struct {
+	Method     string        // request method to match, empty matches any
+	Path       string        // path prefix to match, e.g. PathRecognize; empty matches any
+	Times      int           // number of requests affected, 0 means every matching request
+	Latency    time.Duration // delay before responding
+	Status     int           // respond with this status code, e.g. 503 or 429
+	RetryAfter time.Duration // Retry-After header sent with Status
+	Malformed  bool          // respond 200 with a body that is not valid JSON
+
+	hits int
+}
+
+// Inject adds a fault. Faults are matched in the order they were added.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// matchFault returns the first active fault matching r and counts the hit. Callers hold s.mu.
+func (s *Server) matchFault(r *http.Request) *Fault {
+	for _, f := range s.faults {
+		if f.Times > 0 && f.hits >= f.Times {
+			continue
+		}
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if f.Path != "" && !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+		f.hits++
+		c := *f
+		return &c
+	}
+	return nil
+}
+
+// apply writes the faulty response and reports whether the request was handled.
+func (f *Fault) apply(w http.ResponseWriter, r *http.Request) bool {
+	if f.Latency > 0 {
+		select {
+		case <-time.After(f.Latency):
+		case <-r.Context().Done():
+			return true
+		}
+	}
+
+	switch {
+	case f.Malformed:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"id":`))
+		return true
+	case f.Status != 0:
+		if f.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(f.RetryAfter.Round(time.Second)/time.Second)))
+		}
+		writeError(w, f.Status, http.StatusText(f.Status))
+		return true
+	}
+	return false
+}
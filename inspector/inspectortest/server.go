@@ -0,0 +1,644 @@
+// Package inspectortest provides an in-memory fake Inspector Cloud server for tests.
+//
+// The server keeps state between requests: uploaded images can be recognized,
+// recognitions create reports that become READY after a configurable number of
+// polls, and visits and SKUs can be listed with the same pagination format as the
+// real API. Faults (latency, 5xx, 429 with Retry-After, malformed JSON) can be
+// injected per endpoint to exercise retries and error handling.
+package inspectortest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+)
+
+// APIKey is the key accepted by the fake server.
+const APIKey = "inspectortest"
+
+// Endpoint paths served by the fake server, relative to Server.URL
+const (
+	PathUploads          = "/uploads/"
+	PathUploadsByURL     = "/uploads/upload_by_url/"
+	PathRecognize        = "/recognize/"
+	PathRecognitionError = "/recognition_error/"
+	PathReports          = "/reports/"
+	PathSku              = "/sku/"
+	PathVisits           = "/visits/"
+)
+
+// RecordedRequest is a request received by the fake server.
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+}
+
+// Server is a stateful fake Inspector Cloud server.
+type Server struct {
+	*httptest.Server
+
+	mu                sync.Mutex
+	nextID            int
+	now               func() time.Time
+	images            map[int]inspector.Image
+	files             map[int][]byte
+	recognitions      []inspector.RecognizeResponse
+	recognitionVisits map[int]int
+	reports           map[int]*report
+	skus              []inspector.Sku
+	visits            map[int]inspector.Visit
+	recognitionErrors []inspector.RecognitionErrorRequest
+	readyAfter        int
+	reportData        map[string]any
+	reportErrors      map[string]string
+	faults            []*Fault
+	requests          []RecordedRequest
+}
+
+// report is the server-side state of a report.
+type report struct {
+	inspector.Report
+	polls  int
+	status string // forced status, see SetReportStatus
+}
+
+// NewServer starts a new fake server. Callers should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		nextID:            1,
+		now:               func() time.Time { return time.Now().UTC() },
+		images:            make(map[int]inspector.Image),
+		files:             make(map[int][]byte),
+		recognitionVisits: make(map[int]int),
+		reports:           make(map[int]*report),
+		visits:            make(map[int]inspector.Visit),
+		readyAfter:        1,
+		reportData:        make(map[string]any),
+		reportErrors:      make(map[string]string),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(PathUploads, s.handleUpload)
+	mux.HandleFunc(PathUploadsByURL, s.handleUploadByURL)
+	mux.HandleFunc(PathRecognize, s.handleRecognize)
+	mux.HandleFunc(PathRecognitionError, s.handleRecognitionError)
+	mux.HandleFunc(PathReports, s.handleReports)
+	mux.HandleFunc(PathSku, s.handleSku)
+	mux.HandleFunc(PathVisits, s.handleVisits)
+	s.Server = httptest.NewServer(s.middleware(mux))
+	return s
+}
+
+// Client makes an inspector.Client for the fake server.
+func (s *Server) Client() *inspector.Client {
+	c, err := inspector.NewClient(inspector.ClientConf{Instance: s.URL, APIKey: APIKey})
+	if err != nil {
+		panic(fmt.Sprintf("inspectortest: failed to create client: %v", err))
+	}
+	return c
+}
+
+// SetReadyAfter sets how many polls a report answers NOT_READY before it is READY (default: 1).
+func (s *Server) SetReadyAfter(polls int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readyAfter = polls
+}
+
+// SetReportData sets the json payload of READY reports of reportType.
+func (s *Server) SetReportData(reportType string, data any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reportData[reportType] = data
+}
+
+// SetReportError makes new and pending reports of reportType end with status ERROR.
+// An empty message removes the injected error.
+func (s *Server) SetReportError(reportType, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if message == "" {
+		delete(s.reportErrors, reportType)
+		return
+	}
+	s.reportErrors[reportType] = message
+}
+
+// SetReportStatus forces the status of the report, e.g. inspector.ReportStatusERROR.
+func (s *Server) SetReportStatus(id int, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r, ok := s.reports[id]; ok {
+		r.status = status
+	}
+}
+
+// AddSKU stores SKUs, assigning IDs to those without one.
+func (s *Server) AddSKU(skus ...inspector.Sku) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sku := range skus {
+		if sku.ID == 0 {
+			sku.ID = s.id()
+		}
+		s.skus = append(s.skus, sku)
+	}
+}
+
+// AddVisit stores a visit, assigning an ID when it has none.
+func (s *Server) AddVisit(v inspector.Visit) inspector.Visit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v.ID == 0 {
+		v.ID = s.id()
+	}
+	s.visits[v.ID] = v
+	return v
+}
+
+// Images returns the uploaded images ordered by ID.
+func (s *Server) Images() []inspector.Image {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	images := make([]inspector.Image, 0, len(s.images))
+	for _, img := range s.images {
+		images = append(images, img)
+	}
+	sort.Slice(images, func(i, j int) bool { return images[i].ID < images[j].ID })
+	return images
+}
+
+// File returns the bytes of an image uploaded via multipart form.
+func (s *Server) File(imageID int) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.files[imageID]
+	return b, ok
+}
+
+// Recognitions returns the recognition responses in creation order.
+func (s *Server) Recognitions() []inspector.RecognizeResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]inspector.RecognizeResponse(nil), s.recognitions...)
+}
+
+// Report returns the current state of a report without counting a poll.
+func (s *Server) Report(id int) (inspector.Report, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.reports[id]
+	if !ok {
+		return inspector.Report{}, false
+	}
+	return r.Report, true
+}
+
+// RecognitionErrors returns the received recognition error messages.
+func (s *Server) RecognitionErrors() []inspector.RecognitionErrorRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]inspector.RecognitionErrorRequest(nil), s.recognitionErrors...)
+}
+
+// Requests returns the requests received so far, including faulted ones.
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// id returns the next ID. Callers hold s.mu.
+func (s *Server) id() int {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+// middleware records requests, checks authorization and applies faults.
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, RecordedRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query()})
+		fault := s.matchFault(r)
+		s.mu.Unlock()
+
+		if r.Header.Get("Authorization") != "Token "+APIKey {
+			writeError(w, http.StatusUnauthorized, "Invalid token.")
+			return
+		}
+		if fault != nil && fault.apply(w, r) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
+	if rest := strings.Trim(strings.TrimPrefix(r.URL.Path, PathUploads), "/"); rest != "" && r.Method == http.MethodGet {
+		id, _ := strconv.Atoi(rest)
+		s.mu.Lock()
+		img, ok := s.images[id]
+		s.mu.Unlock()
+		if !ok {
+			writeError(w, http.StatusNotFound, "Not found.")
+			return
+		}
+		writeJSON(w, http.StatusOK, img)
+		return
+	}
+	if r.URL.Path != PathUploads {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	if r.Method == http.MethodGet {
+		images := s.Images()
+		writePage(w, r, len(images), func(offset, limit int) any { return images[offset:limit] })
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "No file was submitted.")
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	img := inspector.Image{ID: s.id(), URL: "https://storage.inspectortest/" + header.Filename, CreatedDate: s.now()}
+	s.images[img.ID] = img
+	s.files[img.ID] = data
+	s.mu.Unlock()
+	writeJSON(w, http.StatusCreated, img)
+}
+
+func (s *Server) handleUploadByURL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		return
+	}
+	var body inspector.UploadByUrlRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.URL == "" {
+		writeError(w, http.StatusBadRequest, "Enter a valid URL.")
+		return
+	}
+
+	s.mu.Lock()
+	img := inspector.Image{ID: s.id(), URL: body.URL, CreatedDate: s.now()}
+	s.images[img.ID] = img
+	s.mu.Unlock()
+	writeJSON(w, http.StatusCreated, img)
+}
+
+func (s *Server) handleRecognize(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		recs := s.Recognitions()
+		if v := r.URL.Query().Get("visit"); v != "" {
+			visit, _ := strconv.Atoi(v)
+			s.mu.Lock()
+			filtered := recs[:0]
+			for _, rec := range recs {
+				if s.recognitionVisits[rec.ID] == visit {
+					filtered = append(filtered, rec)
+				}
+			}
+			s.mu.Unlock()
+			recs = filtered
+		}
+		writePage(w, r, len(recs), func(offset, limit int) any { return recs[offset:limit] })
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		return
+	}
+
+	var rr inspector.RecognizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&rr); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(rr.Images) == 0 {
+		writeError(w, http.StatusBadRequest, "images: This field is required.")
+		return
+	}
+	if len(rr.ReportTypes) == 0 {
+		rr.ReportTypes = []string{inspector.ReportTypeFACING_COUNT}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range rr.Images {
+		if _, ok := s.images[id]; !ok {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("images: Invalid pk \"%d\" - object does not exist.", id))
+			return
+		}
+	}
+
+	rec := inspector.RecognizeResponse{
+		ID:      s.id(),
+		Images:  rr.Images,
+		Display: rr.Display,
+		Reports: make(map[string]int, len(rr.ReportTypes)),
+	}
+	rec.Scene = fmt.Sprintf("00000000-0000-4000-8000-%012d", rec.ID)
+	for _, reportType := range rr.ReportTypes {
+		id := s.id()
+		s.reports[id] = &report{Report: inspector.Report{
+			ID:          id,
+			Status:      inspector.ReportStatusNOT_READY,
+			ReportType:  reportType,
+			CreatedDate: s.now(),
+			UpdatedDate: s.now(),
+			Visit:       rr.Visit,
+		}}
+		rec.Reports[reportType] = id
+	}
+	s.recognitions = append(s.recognitions, rec)
+	s.recognitionVisits[rec.ID] = rr.Visit
+	writeJSON(w, http.StatusCreated, rec)
+}
+
+func (s *Server) handleRecognitionError(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		return
+	}
+	var rr inspector.RecognitionErrorRequest
+	if err := json.NewDecoder(r.Body).Decode(&rr); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	s.recognitionErrors = append(s.recognitionErrors, rr)
+	id := s.id()
+	s.mu.Unlock()
+	writeJSON(w, http.StatusCreated, inspector.RecognitionErrorResponse{RecognitionErrorID: id})
+}
+
+func (s *Server) handleReports(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		return
+	}
+
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, PathReports), "/")
+	if rest == "" {
+		visit, _ := strconv.Atoi(r.URL.Query().Get("visit"))
+		s.mu.Lock()
+		var reports []inspector.Report
+		for _, rep := range s.reports {
+			if visit == 0 || rep.Visit == visit {
+				reports = append(reports, rep.Report)
+			}
+		}
+		s.mu.Unlock()
+		sort.Slice(reports, func(i, j int) bool { return reports[i].ID < reports[j].ID })
+		writePage(w, r, len(reports), func(offset, limit int) any { return reports[offset:limit] })
+		return
+	}
+
+	id, err := strconv.Atoi(rest)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	s.mu.Lock()
+	rep, ok := s.reports[id]
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	rep.polls++
+	s.advance(rep)
+	body := struct {
+		inspector.Report
+		Error string `json:"error,omitempty"`
+	}{Report: rep.Report}
+	if rep.Status == inspector.ReportStatusERROR {
+		body.Error = s.reportErrors[rep.ReportType]
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, body)
+}
+
+// advance moves a NOT_READY report forward. Callers hold s.mu.
+func (s *Server) advance(rep *report) {
+	switch {
+	case rep.status != "":
+		rep.Status = rep.status
+	case rep.Status != inspector.ReportStatusNOT_READY:
+		return
+	case rep.polls <= s.readyAfter:
+		return
+	case s.reportErrors[rep.ReportType] != "":
+		rep.Status = inspector.ReportStatusERROR
+	default:
+		rep.Status = inspector.ReportStatusREADY
+		rep.Json = s.reportData[rep.ReportType]
+		if rep.Json == nil {
+			rep.Json = []any{}
+		}
+	}
+	rep.UpdatedDate = s.now()
+}
+
+func (s *Server) handleSku(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet || r.URL.Path != PathSku {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		return
+	}
+
+	q := r.URL.Query()
+	match := func(sku inspector.Sku) bool {
+		if v := q.Get("search"); v != "" && !strings.Contains(strings.ToLower(sku.Name), strings.ToLower(v)) && sku.CID != v {
+			return false
+		}
+		if v := q.Get("ean13"); v != "" && (sku.EAN13 == nil || *sku.EAN13 != v) {
+			return false
+		}
+		if v := q.Get("cid"); v != "" && sku.CID != v {
+			return false
+		}
+		return matchID(q.Get("brand"), sku.Brand) && matchID(q.Get("category"), sku.Category) && matchID(q.Get("manufacturer"), sku.Manufacturer)
+	}
+
+	s.mu.Lock()
+	var skus []inspector.Sku
+	for _, sku := range s.skus {
+		if match(sku) {
+			skus = append(skus, sku)
+		}
+	}
+	s.mu.Unlock()
+	writePage(w, r, len(skus), func(offset, limit int) any { return skus[offset:limit] })
+}
+
+func matchID(param string, id *int) bool {
+	if param == "" {
+		return true
+	}
+	return id != nil && strconv.Itoa(*id) == param
+}
+
+func (s *Server) handleVisits(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, PathVisits), "/")
+	if rest == "" {
+		switch r.Method {
+		case http.MethodGet:
+			s.listVisits(w, r)
+		case http.MethodPost:
+			s.createVisit(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		}
+		return
+	}
+
+	id, err := strconv.Atoi(rest)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	visit, ok := s.visits[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not found.")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, visit)
+	case http.MethodPatch:
+		var patch inspector.VisitUpdateRequest
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if patch.Shop != nil {
+			visit.Shop = *patch.Shop
+		}
+		if patch.Agent != nil {
+			visit.Agent = *patch.Agent
+		}
+		if patch.StartedDate != nil {
+			visit.StartedDate = *patch.StartedDate
+		}
+		if patch.Latitude != nil {
+			visit.Latitude = *patch.Latitude
+		}
+		if patch.Longitude != nil {
+			visit.Longitude = *patch.Longitude
+		}
+		s.visits[id] = visit
+		writeJSON(w, http.StatusOK, visit)
+	case http.MethodDelete:
+		delete(s.visits, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+	}
+}
+
+func (s *Server) createVisit(w http.ResponseWriter, r *http.Request) {
+	var req inspector.VisitCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	visit := inspector.Visit{ID: s.id(), Shop: req.Shop, Agent: req.Agent, StartedDate: s.now()}
+	if req.StartedDate != nil {
+		visit.StartedDate = *req.StartedDate
+	}
+	if req.Latitude != nil {
+		visit.Latitude = *req.Latitude
+	}
+	if req.Longitude != nil {
+		visit.Longitude = *req.Longitude
+	}
+	s.visits[visit.ID] = visit
+	s.mu.Unlock()
+	writeJSON(w, http.StatusCreated, visit)
+}
+
+func (s *Server) listVisits(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.mu.Lock()
+	var visits []inspector.Visit
+	for _, v := range s.visits {
+		if shop := q.Get("shop"); shop != "" && strconv.Itoa(v.Shop) != shop {
+			continue
+		}
+		if agent := q.Get("agent"); agent != "" && v.Agent != agent {
+			continue
+		}
+		visits = append(visits, v)
+	}
+	s.mu.Unlock()
+	sort.Slice(visits, func(i, j int) bool { return visits[i].ID < visits[j].ID })
+	writePage(w, r, len(visits), func(offset, limit int) any { return visits[offset:limit] })
+}
+
+// writePage writes one page of count items in the count/next/previous/results format.
+// slice returns the items between offset and limit.
+func writePage(w http.ResponseWriter, r *http.Request, count int, slice func(offset, limit int) any) {
+	q := r.URL.Query()
+	limit, err := strconv.Atoi(q.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = inspector.DefaultPageSize
+	}
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	offset = min(max(offset, 0), count)
+	end := min(offset+limit, count)
+
+	link := func(offset int) *string {
+		q.Set("offset", strconv.Itoa(offset))
+		q.Set("limit", strconv.Itoa(limit))
+		u := fmt.Sprintf("http://%s%s?%s", r.Host, r.URL.Path, q.Encode())
+		return &u
+	}
+	page := inspector.Pagination{Count: count, Results: slice(offset, end)}
+	if end < count {
+		page.Next = link(end)
+	}
+	if offset > 0 {
+		page.Previous = link(max(offset-limit, 0))
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, detail string) {
+	writeJSON(w, status, map[string]string{"detail": detail})
+}
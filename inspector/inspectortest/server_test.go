@@ -0,0 +1,221 @@
+package inspectortest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+	httpclient "github.com/germangorelkin/http-client"
+	"github.com/stretchr/testify/assert"
+)
+
+var fastWait = &inspector.ReportWaitOptions{Interval: time.Millisecond, Timeout: time.Second}
+
+func TestServer_RecognitionFlow(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.SetReadyAfter(2)
+	srv.SetReportData(inspector.ReportTypeFACING_COUNT, []map[string]any{{"sku_id": 7, "count": 3}})
+	client := srv.Client()
+	ctx := context.Background()
+
+	img, err := client.Image.Upload(ctx, strings.NewReader("jpeg"), "shelf.jpg")
+	assert.NoError(t, err)
+	data, ok := srv.File(img.ID)
+	assert.True(t, ok)
+	assert.Equal(t, "jpeg", string(data))
+
+	img2, err := client.Image.UploadByURL(ctx, "https://example.com/shelf.jpg")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/shelf.jpg", img2.URL)
+	assert.Len(t, srv.Images(), 2)
+
+	rec, err := client.Recognize.Recognize(ctx, inspector.RecognizeRequest{
+		Images:      []int{img.ID, img2.ID},
+		ReportTypes: []string{inspector.ReportTypeFACING_COUNT},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, srv.Recognitions(), 1)
+
+	reportID := rec.Reports[inspector.ReportTypeFACING_COUNT]
+	var statuses []string
+	report, err := client.Report.WaitForReport(ctx, reportID, &inspector.ReportWaitOptions{
+		Interval:   time.Millisecond,
+		Timeout:    time.Second,
+		OnProgress: func(r *inspector.Report) { statuses = append(statuses, r.Status) },
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"NOT_READY", "NOT_READY", "READY"}, statuses)
+
+	fc, err := client.Report.ToFacingCount(report.Json)
+	assert.NoError(t, err)
+	assert.Equal(t, []inspector.ReportFacingCountJson{{Count: 3, SkuId: 7}}, fc)
+
+	_, err = client.Recognize.Recognize(ctx, inspector.RecognizeRequest{Images: []int{999}})
+	assert.Error(t, err)
+}
+
+func TestServer_ReportErrors(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	img, err := client.Image.UploadByURL(ctx, "https://example.com/shelf.jpg")
+	assert.NoError(t, err)
+
+	srv.SetReportError(inspector.ReportTypePRICE_TAGS, "price tags model unavailable")
+	rec, err := client.Recognize.Recognize(ctx, inspector.RecognizeRequest{
+		Images:      []int{img.ID},
+		ReportTypes: []string{inspector.ReportTypeFACING_COUNT, inspector.ReportTypePRICE_TAGS},
+	})
+	assert.NoError(t, err)
+
+	_, err = client.Report.WaitForReport(ctx, rec.Reports[inspector.ReportTypePRICE_TAGS], fastWait)
+	assert.Error(t, err)
+	_, err = client.Report.WaitForReport(ctx, rec.Reports[inspector.ReportTypeFACING_COUNT], fastWait)
+	assert.NoError(t, err)
+
+	srv.SetReportStatus(rec.Reports[inspector.ReportTypeFACING_COUNT], inspector.ReportStatusERROR)
+	report, err := client.Report.GetReport(ctx, rec.Reports[inspector.ReportTypeFACING_COUNT])
+	assert.NoError(t, err)
+	assert.Equal(t, inspector.ReportStatusERROR, report.Status)
+}
+
+func TestServer_SkuPagination(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	brand := 5
+	for i := 0; i < 25; i++ {
+		sku := inspector.Sku{CID: "C" + string(rune('a'+i)), Name: "Cola"}
+		if i%5 == 0 {
+			sku.Brand = &brand
+		}
+		srv.AddSKU(sku)
+	}
+	client := srv.Client()
+	ctx := context.Background()
+
+	skus, err := client.Sku.GetAllSKU(ctx, 10)
+	assert.NoError(t, err)
+	assert.Len(t, skus, 25)
+
+	page, err := client.Sku.GetSKU(ctx, 20, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 25, page.Count)
+	assert.Nil(t, page.Next)
+	assert.NotNil(t, page.Previous)
+
+	branded, err := client.Sku.GetAllSKU(ctx, 2, &inspector.SkuQuery{Brand: &brand})
+	assert.NoError(t, err)
+	assert.Len(t, branded, 5)
+
+	found, err := client.Sku.FindByCID(ctx, "Cc")
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+}
+
+func TestServer_Visits(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	visit, err := client.Visit.CreateVisit(ctx, inspector.VisitCreateRequest{Shop: 42, Agent: "route-7"})
+	assert.NoError(t, err)
+	srv.AddVisit(inspector.Visit{Shop: 43, Agent: "route-8"})
+
+	agent := "route-9"
+	visit, err = client.Visit.UpdateVisit(ctx, visit.ID, inspector.VisitUpdateRequest{Agent: &agent})
+	assert.NoError(t, err)
+	assert.Equal(t, "route-9", visit.Agent)
+
+	shop := 42
+	visits, err := client.Visit.ListVisits(ctx, &inspector.VisitQuery{Shop: &shop})
+	assert.NoError(t, err)
+	assert.Len(t, visits, 1)
+
+	img, err := client.Image.UploadByURL(ctx, "https://example.com/shelf.jpg")
+	assert.NoError(t, err)
+	_, err = client.Recognize.Recognize(ctx, inspector.RecognizeRequest{Images: []int{img.ID}, Visit: visit.ID})
+	assert.NoError(t, err)
+
+	reports, err := client.Visit.GetVisitReports(ctx, visit.ID)
+	assert.NoError(t, err)
+	assert.Len(t, reports, 1)
+	recs, err := client.Visit.GetVisitRecognitions(ctx, visit.ID)
+	assert.NoError(t, err)
+	assert.Len(t, recs, 1)
+
+	assert.NoError(t, client.Visit.DeleteVisit(ctx, visit.ID))
+	_, err = client.Visit.GetVisit(ctx, visit.ID)
+	assert.Error(t, err)
+}
+
+func TestServer_RecognitionError(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	resp, err := client.Recognize.RecognitionError(context.Background(), &inspector.RecognitionErrorRequest{
+		Images: []int{1}, SkuId: 7, Scene: "scene", Message: "wrong sku",
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, resp.RecognitionErrorID)
+	assert.Equal(t, "wrong sku", srv.RecognitionErrors()[0].Message)
+}
+
+func TestServer_Faults(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	t.Run("5xx", func(t *testing.T) {
+		srv.Inject(Fault{Method: http.MethodPost, Path: PathUploadsByURL, Status: http.StatusServiceUnavailable, Times: 1})
+		_, err := client.Image.UploadByURL(ctx, "https://example.com/a.jpg")
+		var errResp *httpclient.ErrorResponse
+		assert.True(t, errors.As(err, &errResp))
+		assert.Equal(t, http.StatusServiceUnavailable, errResp.Response.StatusCode)
+
+		_, err = client.Image.UploadByURL(ctx, "https://example.com/a.jpg")
+		assert.NoError(t, err)
+	})
+
+	t.Run("429", func(t *testing.T) {
+		srv.Inject(Fault{Path: PathSku, Status: http.StatusTooManyRequests, RetryAfter: 3 * time.Second, Times: 1})
+		_, err := client.Sku.GetSKU(ctx, 0, 10)
+		var errResp *httpclient.ErrorResponse
+		assert.True(t, errors.As(err, &errResp))
+		assert.Equal(t, http.StatusTooManyRequests, errResp.Response.StatusCode)
+		assert.Equal(t, "3", errResp.Response.Header.Get("Retry-After"))
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		srv.Inject(Fault{Path: PathReports, Malformed: true, Times: 1})
+		_, err := client.Report.GetReport(ctx, 1)
+		assert.Error(t, err)
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		srv.Inject(Fault{Path: PathVisits, Latency: 200 * time.Millisecond})
+		defer srv.ClearFaults()
+		tctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		_, err := client.Visit.AddVisit(tctx)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		c, err := inspector.NewClient(inspector.ClientConf{Instance: srv.URL, APIKey: "wrong"})
+		assert.NoError(t, err)
+		_, err = c.Visit.AddVisit(ctx)
+		assert.Error(t, err)
+	})
+
+	assert.NotEmpty(t, srv.Requests())
+}
@@ -0,0 +1,224 @@
+package inspector
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Must-have list item statuses
+const (
+	MHLItemPRESENT    = "PRESENT"    // at least MinFacings facings
+	MHLItemUNDERFACED = "UNDERFACED" // on shelf with fewer than MinFacings facings
+	MHLItemMISSING    = "MISSING"    // no facings: out of stock or not listed
+)
+
+var (
+	// ErrInvalidMustHaveList is returned for must-have lists that fail validation.
+	ErrInvalidMustHaveList = errors.New("invalid must-have list")
+	// ErrMHLChainNotFound is returned when a must-have list has no entry for a retail chain.
+	ErrMHLChainNotFound = errors.New("retail chain not in must-have list")
+)
+
+// MustHaveList holds must-have SKU lists (MHL) per retail chain.
+type MustHaveList struct {
+	Name   string     `json:"name" yaml:"name"`
+	Chains []MHLChain `json:"chains" yaml:"chains"`
+}
+
+// MHLChain is the must-have list of a retail chain with per-store overrides.
+type MHLChain struct {
+	RetailChain string     `json:"retail_chain" yaml:"retail_chain"` // see Shop.RetailChain
+	Items       []MHLItem  `json:"items" yaml:"items"`
+	Stores      []MHLStore `json:"stores,omitempty" yaml:"stores,omitempty"`
+}
+
+// MHLStore overrides the chain list for one store.
+type MHLStore struct {
+	Store   string    `json:"store" yaml:"store"`                         // see Shop.ExternalCode
+	Items   []MHLItem `json:"items,omitempty" yaml:"items,omitempty"`     // added, or replacing chain items with the same SkuId
+	Exclude []int     `json:"exclude,omitempty" yaml:"exclude,omitempty"` // SKU IDs not required in this store
+}
+
+// MHLItem is a required SKU.
+type MHLItem struct {
+	SkuId      int `json:"sku_id" yaml:"sku_id"`
+	MinFacings int `json:"min_facings,omitempty" yaml:"min_facings,omitempty"` // default 1
+}
+
+// Validate checks that every chain is named once and every item has a SKU.
+func (m *MustHaveList) Validate() error {
+	seen := map[string]bool{}
+	for _, c := range m.Chains {
+		if seen[c.RetailChain] {
+			return fmt.Errorf("must-have list %q repeats retail chain %q:%w", m.Name, c.RetailChain, ErrInvalidMustHaveList)
+		}
+		seen[c.RetailChain] = true
+		items := append([]MHLItem(nil), c.Items...)
+		for _, s := range c.Stores {
+			items = append(items, s.Items...)
+		}
+		for _, it := range items {
+			if it.SkuId <= 0 || it.MinFacings < 0 {
+				return fmt.Errorf("must-have list %q chain %q has an item without sku_id or with negative min_facings:%w", m.Name, c.RetailChain, ErrInvalidMustHaveList)
+			}
+		}
+	}
+	return nil
+}
+
+// DecodeMustHaveList reads and validates a must-have list in PlanogramFormatJSON or PlanogramFormatYAML.
+func DecodeMustHaveList(r io.Reader, format string) (*MustHaveList, error) {
+	var m MustHaveList
+	if err := decodeDefinition(r, format, &m); err != nil {
+		return nil, fmt.Errorf("failed to decode must-have list:%w", err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// LoadMustHaveList reads a must-have list file; .yaml and .yml files are YAML, others JSON.
+func LoadMustHaveList(path string) (*MustHaveList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open must-have list %s:%w", path, err)
+	}
+	defer f.Close()
+	return DecodeMustHaveList(f, definitionFormat(path))
+}
+
+// Items returns the required SKUs of a store of retailChain, ordered by SKU ID.
+// Store overrides apply when store matches MHLStore.Store; an empty store uses the chain list.
+func (m *MustHaveList) Items(retailChain, store string) ([]MHLItem, error) {
+	for _, c := range m.Chains {
+		if c.RetailChain != retailChain {
+			continue
+		}
+		items := make(map[int]MHLItem, len(c.Items))
+		for _, it := range c.Items {
+			items[it.SkuId] = it
+		}
+		for _, s := range c.Stores {
+			if store == "" || s.Store != store {
+				continue
+			}
+			for _, it := range s.Items {
+				items[it.SkuId] = it
+			}
+			for _, id := range s.Exclude {
+				delete(items, id)
+			}
+		}
+
+		out := make([]MHLItem, 0, len(items))
+		for _, it := range items {
+			if it.MinFacings <= 0 {
+				it.MinFacings = 1
+			}
+			out = append(out, it)
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].SkuId < out[j].SkuId })
+		return out, nil
+	}
+	return nil, fmt.Errorf("%q:%w", retailChain, ErrMHLChainNotFound)
+}
+
+// MHLItemResult is the availability of one required SKU.
+type MHLItemResult struct {
+	SkuId      int    `json:"sku_id"`
+	Status     string `json:"status"`
+	Facings    int    `json:"facings"`
+	MinFacings int    `json:"min_facings"`
+}
+
+// MHLResult is the result of MustHaveList.Check.
+type MHLResult struct {
+	RetailChain string          `json:"retail_chain"`
+	Store       string          `json:"store,omitempty"`
+	Items       []MHLItemResult `json:"items"` // ordered by SKU ID
+	Present     int             `json:"present"`
+	UnderFaced  int             `json:"under_faced"`
+	Missing     int             `json:"missing"`
+	OSA         float64         `json:"osa"`        // on-shelf availability: percent of required SKUs with any facing
+	Compliance  float64         `json:"compliance"` // percent of required SKUs with at least MinFacings
+}
+
+// MissingSKUs returns the IDs of required SKUs without facings.
+func (r *MHLResult) MissingSKUs() []int {
+	var ids []int
+	for _, it := range r.Items {
+		if it.Status == MHLItemMISSING {
+			ids = append(ids, it.SkuId)
+		}
+	}
+	return ids
+}
+
+// Check compares the must-have list of a store with observed facings by SKU ID,
+// see FacingsFromCounts and FacingsFromRealograms.
+func (m *MustHaveList) Check(retailChain, store string, facings map[int]int) (*MHLResult, error) {
+	items, err := m.Items(retailChain, store)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &MHLResult{RetailChain: retailChain, Store: store}
+	for _, it := range items {
+		r := MHLItemResult{SkuId: it.SkuId, Facings: facings[it.SkuId], MinFacings: it.MinFacings}
+		switch {
+		case r.Facings >= r.MinFacings:
+			r.Status = MHLItemPRESENT
+			res.Present++
+		case r.Facings > 0:
+			r.Status = MHLItemUNDERFACED
+			res.UnderFaced++
+		default:
+			r.Status = MHLItemMISSING
+			res.Missing++
+		}
+		res.Items = append(res.Items, r)
+	}
+	if n := len(items); n > 0 {
+		res.OSA = 100 * float64(res.Present+res.UnderFaced) / float64(n)
+		res.Compliance = 100 * float64(res.Present) / float64(n)
+	}
+	return res, nil
+}
+
+// CheckShop runs Check for shop's RetailChain and ExternalCode.
+func (m *MustHaveList) CheckShop(shop Shop, facings map[int]int) (*MHLResult, error) {
+	return m.Check(shop.RetailChain, shop.ExternalCode, facings)
+}
+
+// FacingsFromCounts sums a FACING_COUNT report by SKU ID.
+func FacingsFromCounts(counts []ReportFacingCountJson) map[int]int {
+	facings := make(map[int]int, len(counts))
+	for _, c := range counts {
+		facings[c.SkuId] += c.Count
+	}
+	return facings
+}
+
+// FacingsFromRealograms sums the front-row shelf facings of realogram images by SKU ID.
+// Products not assigned to a shelf are counted too, duplicates only with IncludeDuplicates.
+func FacingsFromRealograms(realograms []ReportRealogramJson, opts *RealogramOptions) map[int]int {
+	includeDuplicates := opts != nil && opts.IncludeDuplicates
+	facings := map[int]int{}
+	for _, layout := range AnalyzeRealograms(realograms, opts) {
+		for _, s := range layout.Shelves {
+			for sku, n := range s.Facings {
+				facings[sku] += n
+			}
+		}
+		for _, p := range layout.Unassigned {
+			if includeDuplicates || !p.Duplicate {
+				facings[p.SkuId]++
+			}
+		}
+	}
+	return facings
+}
@@ -0,0 +1,112 @@
+package inspector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const mhlTestYAML = `
+name: q4
+chains:
+  - retail_chain: magnit
+    items:
+      - {sku_id: 1, min_facings: 2}
+      - sku_id: 2
+      - sku_id: 3
+    stores:
+      - store: MAG-012
+        items:
+          - {sku_id: 2, min_facings: 3}
+          - sku_id: 4
+        exclude: [3]
+  - retail_chain: x5
+    items:
+      - sku_id: 1
+`
+
+func TestMustHaveList_Check(t *testing.T) {
+	m, err := DecodeMustHaveList(strings.NewReader(mhlTestYAML), PlanogramFormatYAML)
+	assert.NoError(t, err)
+	facings := map[int]int{1: 1, 2: 2, 4: 1, 99: 5}
+
+	t.Run("chain list", func(t *testing.T) {
+		got, err := m.Check("magnit", "", facings)
+		assert.NoError(t, err)
+		assert.Equal(t, []MHLItemResult{
+			{SkuId: 1, Status: MHLItemUNDERFACED, Facings: 1, MinFacings: 2},
+			{SkuId: 2, Status: MHLItemPRESENT, Facings: 2, MinFacings: 1},
+			{SkuId: 3, Status: MHLItemMISSING, MinFacings: 1},
+		}, got.Items)
+		assert.Equal(t, 1, got.Present)
+		assert.Equal(t, 1, got.UnderFaced)
+		assert.Equal(t, 1, got.Missing)
+		assert.InDelta(t, 200.0/3, got.OSA, 1e-9)
+		assert.InDelta(t, 100.0/3, got.Compliance, 1e-9)
+		assert.Equal(t, []int{3}, got.MissingSKUs())
+	})
+
+	t.Run("store override", func(t *testing.T) {
+		got, err := m.CheckShop(Shop{RetailChain: "magnit", ExternalCode: "MAG-012"}, facings)
+		assert.NoError(t, err)
+		assert.Equal(t, "MAG-012", got.Store)
+		assert.Equal(t, []MHLItemResult{
+			{SkuId: 1, Status: MHLItemUNDERFACED, Facings: 1, MinFacings: 2},
+			{SkuId: 2, Status: MHLItemUNDERFACED, Facings: 2, MinFacings: 3},
+			{SkuId: 4, Status: MHLItemPRESENT, Facings: 1, MinFacings: 1},
+		}, got.Items)
+		assert.Equal(t, 100.0, got.OSA)
+	})
+
+	t.Run("other store uses the chain list", func(t *testing.T) {
+		got, err := m.Check("magnit", "MAG-999", facings)
+		assert.NoError(t, err)
+		assert.Len(t, got.Items, 3)
+	})
+
+	t.Run("unknown chain", func(t *testing.T) {
+		_, err := m.Check("lenta", "", facings)
+		assert.ErrorIs(t, err, ErrMHLChainNotFound)
+	})
+}
+
+func TestDecodeMustHaveList(t *testing.T) {
+	t.Run("json file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "mhl.json")
+		assert.NoError(t, os.WriteFile(path, []byte(`{"name":"q4","chains":[{"retail_chain":"x5","items":[{"sku_id":1}]}]}`), 0o600))
+		got, err := LoadMustHaveList(path)
+		assert.NoError(t, err)
+		assert.Equal(t, &MustHaveList{Name: "q4", Chains: []MHLChain{{RetailChain: "x5", Items: []MHLItem{{SkuId: 1}}}}}, got)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := DecodeMustHaveList(strings.NewReader(`{"chains":[{"retail_chain":"x5"},{"retail_chain":"x5"}]}`), PlanogramFormatJSON)
+		assert.ErrorIs(t, err, ErrInvalidMustHaveList)
+		_, err = DecodeMustHaveList(strings.NewReader(`{"chains":[{"retail_chain":"x5","stores":[{"store":"a","items":[{"min_facings":1}]}]}]}`), PlanogramFormatJSON)
+		assert.ErrorIs(t, err, ErrInvalidMustHaveList)
+		_, err = DecodeMustHaveList(strings.NewReader(`{"chain":[]}`), PlanogramFormatJSON)
+		assert.Error(t, err)
+	})
+}
+
+func TestFacingsFrom(t *testing.T) {
+	assert.Equal(t, map[int]int{1: 5, 2: 1}, FacingsFromCounts([]ReportFacingCountJson{{SkuId: 1, Count: 3}, {SkuId: 2, Count: 1}, {SkuId: 1, Count: 2}}))
+
+	facings := FacingsFromRealograms(loadRealogramReport(t), nil)
+	assert.Equal(t, 7, facings[53733])
+	total := 0
+	for _, n := range facings {
+		total += n
+	}
+	assert.Equal(t, 55, total)
+
+	r := ReportRealogramJson{Annotations: []ReportRealogramAnnotations{
+		{X: 50, Y: 50, W: 10, H: 10, SkuId: 1},
+		{X: 70, Y: 50, W: 10, H: 10, SkuId: 1, Duplicate: true},
+	}}
+	assert.Equal(t, map[int]int{1: 1}, FacingsFromRealograms([]ReportRealogramJson{r}, nil))
+	assert.Equal(t, map[int]int{1: 2}, FacingsFromRealograms([]ReportRealogramJson{r}, &RealogramOptions{IncludeDuplicates: true}))
+}
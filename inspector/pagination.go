@@ -0,0 +1,425 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+// Page represents a typed page of a list endpoint.
+type Page[T any] struct {
+	Count    int     `json:"count"`
+	Next     *string `json:"next,omitempty"`
+	Previous *string `json:"previous,omitempty"`
+	Results  []T     `json:"results"`
+}
+
+// Paginator provides iteration over any paginated list endpoint of the IC API.
+// It follows the query of the `next` URL returned by the server, so both
+// offset/limit and cursor pagination are supported, and decodes `results`
+// directly into []T. Requests always go to the configured instance and path.
+type Paginator[T any] struct {
+	client   *Client
+	ctx      context.Context
+	name     string // object name used in error messages
+	path     string
+	query    url.Values
+	hasMore  bool
+	seen     map[string]bool
+	maxPages int
+	count    int
+}
+
+// NewPaginator returns a Paginator for the list endpoint at path.
+// query is sent with the first request; pageSize controls the page limit (default: 100).
+func NewPaginator[T any](ctx context.Context, c *Client, path string, query url.Values, pageSize int) *Paginator[T] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set(queryParamLimit, strconv.Itoa(pageSize))
+	if q.Get(queryParamCursor) == "" {
+		q.Set(queryParamOffset, "0")
+	}
+	return &Paginator[T]{
+		client:   c,
+		ctx:      ctx,
+		name:     path,
+		path:     path,
+		query:    q,
+		hasMore:  true,
+		seen:     make(map[string]bool),
+		maxPages: MaxPaginationPages, // Safety limit to prevent infinite loops
+	}
+}
+
+// Count returns the total number of objects reported by the last fetched page.
+func (p *Paginator[T]) Count() int {
+	return p.count
+}
+
+// NextPage returns the next page.
+// Returns nil, nil when no more pages are available.
+func (p *Paginator[T]) NextPage() (*Page[T], error) {
+	if !p.hasMore {
+		return nil, nil
+	}
+
+	// Check infinite loop safeguard
+	key := p.query.Encode()
+	if p.seen[key] {
+		return nil, fmt.Errorf("detected pagination loop at %s?%s", p.path, key)
+	}
+	if len(p.seen) >= p.maxPages {
+		return nil, fmt.Errorf("exceeded maximum page limit of %d", p.maxPages)
+	}
+
+	// Mark this page as seen
+	p.seen[key] = true
+
+	page, err := getTypedPage[T](p.ctx, p.client, p.path, p.query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s page %s:%w", p.name, key, err)
+	}
+	p.count = page.Count
+
+	// Check if we have more pages
+	p.hasMore = false
+	if page.Next != nil {
+		next, err := url.Parse(*page.Next)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse next page URL %q:%w", *page.Next, err)
+		}
+		p.query = next.Query()
+		p.hasMore = true
+	}
+
+	return page, nil
+}
+
+// Next returns the items of the next page.
+// Returns nil, nil when no more pages are available.
+func (p *Paginator[T]) Next() ([]T, error) {
+	page, err := p.NextPage()
+	if err != nil || page == nil {
+		return nil, err
+	}
+	if page.Results == nil {
+		return []T{}, nil
+	}
+	return page.Results, nil
+}
+
+// All returns a range-over-func iterator over all remaining items.
+// Iteration stops after the first error is yielded.
+func (p *Paginator[T]) All() iter.Seq2[T, error] {
+	return allItems(p.Next)
+}
+
+// Collect fetches all remaining items.
+func (p *Paginator[T]) Collect() ([]T, error) {
+	return collectItems(p.Next)
+}
+
+// Iterator is the page-by-page iteration returned by the Iterate methods of the
+// service APIs. Paginator implements it for the IC API; IteratorFunc lets fakes
+// supply pages without a server.
+type Iterator[T any] interface {
+	// Next returns the items of the next page, or nil, nil when no more pages are available.
+	Next() ([]T, error)
+	// All returns a range-over-func iterator over all remaining items.
+	All() iter.Seq2[T, error]
+	// Collect fetches all remaining items.
+	Collect() ([]T, error)
+}
+
+// IteratorFunc adapts a next page function to Iterator.
+// The function returns nil, nil when no more pages are available.
+type IteratorFunc[T any] func() ([]T, error)
+
+// Next calls f.
+func (f IteratorFunc[T]) Next() ([]T, error) {
+	return f()
+}
+
+// All returns a range-over-func iterator over all remaining items.
+func (f IteratorFunc[T]) All() iter.Seq2[T, error] {
+	return allItems(f.Next)
+}
+
+// Collect fetches all remaining items.
+func (f IteratorFunc[T]) Collect() ([]T, error) {
+	return collectItems(f.Next)
+}
+
+// SliceIterator returns an Iterator over the given pages.
+func SliceIterator[T any](pages ...[]T) Iterator[T] {
+	return IteratorFunc[T](func() ([]T, error) {
+		if len(pages) == 0 {
+			return nil, nil
+		}
+		page := pages[0]
+		pages = pages[1:]
+		if page == nil {
+			page = []T{}
+		}
+		return page, nil
+	})
+}
+
+// allItems ranges over the items of the pages returned by next.
+func allItems[T any](next func() ([]T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			items, err := next()
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if items == nil {
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// collectItems concatenates the pages returned by next.
+func collectItems[T any](next func() ([]T, error)) ([]T, error) {
+	var items []T
+	for {
+		page, err := next()
+		if err != nil {
+			return nil, err
+		}
+		if page == nil {
+			break
+		}
+		items = append(items, page...)
+	}
+	return items, nil
+}
+
+// getTypedPage requests one page of the list endpoint at path with the exact query.
+func getTypedPage[T any](ctx context.Context, c *Client, path string, query url.Values) (*Page[T], error) {
+	req, err := c.httpClient.NewRequest(methodGET, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to NewRequest(%s, %s):%w", methodGET, path, err)
+	}
+	req.URL.RawQuery = query.Encode()
+
+	var page Page[T]
+	if _, err = c.httpClient.Do(ctx, req, &page); err != nil {
+		return nil, fmt.Errorf("failed to Do with Request(%s, %s):%w", methodGET, req.URL.RawQuery, err)
+	}
+	return &page, nil
+}
+
+// getPage requests one page of the list endpoint at path through a Paginator.
+// Results hold the decoded JSON items, see the To* converters of the services.
+func (c *Client) getPage(ctx context.Context, path string, query url.Values, offset, limit int) (*Pagination, error) {
+	p := NewPaginator[any](ctx, c, path, query, limit)
+	p.query.Set(queryParamOffset, strconv.Itoa(offset))
+	page, err := p.NextPage()
+	if err != nil {
+		return nil, err
+	}
+	results := page.Results
+	if results == nil {
+		results = []any{}
+	}
+	return &Pagination{Count: page.Count, Next: page.Next, Previous: page.Previous, Results: results}, nil
+}
+
+// getObject requests a single object at path and decodes it into v.
+func (c *Client) getObject(ctx context.Context, path string, v any) error {
+	req, err := c.httpClient.NewRequest(methodGET, path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to NewRequest(%s, %s):%w", methodGET, path, err)
+	}
+
+	if _, err = c.httpClient.Do(ctx, req, v); err != nil {
+		return fmt.Errorf("failed to Do with Request(%s, %s):%w", methodGET, path, err)
+	}
+
+	return nil
+}
+
+// ParallelFetchOptions configures parallel fetching of offset-paginated lists.
+type ParallelFetchOptions struct {
+	PageSize    int                           // items per page (default: DefaultPageSize)
+	Concurrency int                           // maximum concurrent page requests (default: DefaultFetchConcurrency)
+	MaxRetries  int                           // full re-pulls when the list changed during the pull
+	Strict      bool                          // return an error instead of a warning if the list is still inconsistent
+	OnWarning   func(warn ConsistencyWarning) // optional callback for consistency warnings
+}
+
+// ConsistencyWarning describes changes of a list detected during a parallel pull.
+type ConsistencyWarning struct {
+	Attempt       int   // 1-based pull attempt
+	ExpectedCount int   // count reported by the first page
+	CountDrift    []int // differing counts reported by later pages
+	Fetched       int   // number of items fetched including duplicates
+	DuplicateIDs  []int // IDs returned on more than one page
+}
+
+// String returns a human readable description of the warning.
+func (w ConsistencyWarning) String() string {
+	return fmt.Sprintf("list changed during pull (attempt %d): expected %d items, fetched %d, count drift %v, duplicate ids %v",
+		w.Attempt, w.ExpectedCount, w.Fetched, w.CountDrift, w.DuplicateIDs)
+}
+
+func (w ConsistencyWarning) consistent() bool {
+	return len(w.CountDrift) == 0 && len(w.DuplicateIDs) == 0 && w.Fetched == w.ExpectedCount
+}
+
+// fetchAllParallel fetches the first page, computes the remaining offsets from its count
+// and size and fetches them with bounded concurrency. Items are returned in page order with
+// duplicates (by id) removed.
+func fetchAllParallel[T any](ctx context.Context, c *Client, path string, query url.Values, opts *ParallelFetchOptions, id func(T) int) ([]T, error) {
+	var options ParallelFetchOptions
+	if opts != nil {
+		options = *opts
+	}
+	if options.PageSize <= 0 {
+		options.PageSize = DefaultPageSize
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = DefaultFetchConcurrency
+	}
+
+	for attempt := 1; ; attempt++ {
+		pages, warn, err := fetchPagesParallel[T](ctx, c, path, query, options)
+		if err != nil {
+			return nil, err
+		}
+		warn.Attempt = attempt
+
+		items := make([]T, 0, warn.ExpectedCount)
+		seen := make(map[int]bool, warn.ExpectedCount)
+		for _, page := range pages {
+			for _, item := range page {
+				warn.Fetched++
+				key := id(item)
+				if seen[key] {
+					warn.DuplicateIDs = append(warn.DuplicateIDs, key)
+					continue
+				}
+				seen[key] = true
+				items = append(items, item)
+			}
+		}
+
+		if warn.consistent() {
+			return items, nil
+		}
+		if attempt <= options.MaxRetries {
+			continue
+		}
+		if options.Strict {
+			return nil, fmt.Errorf("failed to fetch %s consistently: %s", path, warn)
+		}
+		if options.OnWarning != nil {
+			options.OnWarning(warn)
+		}
+		return items, nil
+	}
+}
+
+func fetchPagesParallel[T any](ctx context.Context, c *Client, path string, query url.Values, options ParallelFetchOptions) ([][]T, ConsistencyWarning, error) {
+	var warn ConsistencyWarning
+
+	pageQuery := func(offset int) url.Values {
+		q := url.Values{}
+		for k, v := range query {
+			q[k] = v
+		}
+		q.Set(queryParamLimit, strconv.Itoa(options.PageSize))
+		q.Set(queryParamOffset, strconv.Itoa(offset))
+		return q
+	}
+
+	first, err := getTypedPage[T](ctx, c, path, pageQuery(0))
+	if err != nil {
+		return nil, warn, fmt.Errorf("failed to fetch %s page at offset 0:%w", path, err)
+	}
+	warn.ExpectedCount = first.Count
+	if first.Next == nil {
+		warn.ExpectedCount = len(first.Results)
+		return [][]T{first.Results}, warn, nil
+	}
+
+	// the server may cap limit below PageSize, so the first page gives the stride
+	stride := len(first.Results)
+	if stride == 0 {
+		return nil, warn, fmt.Errorf("failed to fetch %s: empty first page with more pages", path)
+	}
+	numPages := max((first.Count+stride-1)/stride, 1)
+	if numPages > MaxPaginationPages {
+		return nil, warn, fmt.Errorf("exceeded maximum page limit of %d", MaxPaginationPages)
+	}
+	pages := make([][]T, numPages)
+	counts := make([]int, numPages)
+	pages[0] = first.Results
+	counts[0] = first.Count
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		sem      = make(chan struct{}, options.Concurrency)
+	)
+	for i := 1; i < numPages; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			offset := i * stride
+			page, err := getTypedPage[T](ctx, c, path, pageQuery(offset))
+			if err != nil {
+				once.Do(func() {
+					firstErr = fmt.Errorf("failed to fetch %s page at offset %d:%w", path, offset, err)
+					cancel()
+				})
+				return
+			}
+			pages[i] = page.Results
+			counts[i] = page.Count
+		}(i)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, warn, firstErr
+	}
+	// workers skip their pages once ctx is done, so the result is incomplete
+	if err := ctx.Err(); err != nil {
+		return nil, warn, fmt.Errorf("failed to fetch %s:%w", path, err)
+	}
+
+	for _, count := range counts[1:] {
+		if count != first.Count {
+			warn.CountDrift = append(warn.CountDrift, count)
+		}
+	}
+	return pages, warn, nil
+}
@@ -0,0 +1,151 @@
+package inspector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writePage(w http.ResponseWriter, count int, next *string, results string) {
+	w.Header().Set(headerContentType, contentTypeJSON)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"count":    count,
+		"next":     next,
+		"previous": nil,
+		"results":  json.RawMessage(results),
+	})
+}
+
+func TestPaginator_Cursor(t *testing.T) {
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/visits/", r.URL.Path)
+		requests = append(requests, r.URL.RawQuery)
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			next := "https://other-host.example/api/v1.5/visits/?cursor=cD0y&limit=2"
+			writePage(w, 3, &next, `[{"id": 1, "shop": 10}, {"id": 2, "shop": 10}]`)
+		case "cD0y":
+			writePage(w, 3, nil, `[{"id": 3, "shop": 11}]`)
+		default:
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("cursor"))
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+	assert.NoError(t, err)
+
+	visits, err := client.Visit.IterateVisits(context.Background(), 2).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(visits))
+	assert.Equal(t, 11, visits[2].Shop)
+	assert.Equal(t, []string{"limit=2&offset=0", "cursor=cD0y&limit=2"}, requests)
+}
+
+func TestPaginator_All(t *testing.T) {
+	var serverURL string
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Get("offset") == "0" {
+			next := fmt.Sprintf("%s/uploads/?limit=2&offset=2", serverURL)
+			writePage(w, 3, &next, `[{"id": 1, "width": 640}, {"id": 2, "width": 800}]`)
+			return
+		}
+		writePage(w, 3, nil, `[{"id": 3, "width": 1024}]`)
+	}))
+	defer ts.Close()
+	serverURL = ts.URL
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+	assert.NoError(t, err)
+
+	t.Run("range over all items", func(t *testing.T) {
+		calls = 0
+		var ids []int
+		for img, err := range client.Image.IterateImages(context.Background(), 2).All() {
+			assert.NoError(t, err)
+			ids = append(ids, img.ID)
+		}
+		assert.Equal(t, []int{1, 2, 3}, ids)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("break stops fetching", func(t *testing.T) {
+		calls = 0
+		for img := range client.Image.IterateImages(context.Background(), 2).All() {
+			assert.Equal(t, 1, img.ID)
+			break
+		}
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("count", func(t *testing.T) {
+		it := NewPaginator[Image](context.Background(), client, endpointUploads, nil, 2)
+		assert.Equal(t, 0, it.Count())
+		_, err := it.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, 3, it.Count())
+	})
+}
+
+func TestPaginator_Errors(t *testing.T) {
+	t.Run("pagination loop", func(t *testing.T) {
+		var serverURL string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next := fmt.Sprintf("%s/sku/?limit=1&offset=0", serverURL)
+			writePage(w, 10, &next, `[{"id": 1}]`)
+		}))
+		defer ts.Close()
+		serverURL = ts.URL
+
+		client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+		assert.NoError(t, err)
+
+		var lastErr error
+		for _, err := range client.Sku.IterateSKU(context.Background(), 1).All() {
+			lastErr = err
+		}
+		assert.Error(t, lastErr)
+		assert.Contains(t, lastErr.Error(), "detected pagination loop")
+	})
+
+	t.Run("max pages", func(t *testing.T) {
+		var serverURL string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next := fmt.Sprintf("%s/sku/?limit=1&offset=%s1", serverURL, r.URL.Query().Get("offset"))
+			writePage(w, 10, &next, `[{"id": 1}]`)
+		}))
+		defer ts.Close()
+		serverURL = ts.URL
+
+		client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+		assert.NoError(t, err)
+
+		it := client.Sku.IterateSKU(context.Background(), 1).(*Paginator[Sku])
+		it.maxPages = 3
+		_, err = it.Collect()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeded maximum page limit of 3")
+	})
+
+	t.Run("malformed results", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writePage(w, 1, nil, `{"bad": "data"}`)
+		}))
+		defer ts.Close()
+
+		client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+		assert.NoError(t, err)
+
+		_, err = client.Sku.IterateSKU(context.Background(), 1).Next()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to fetch SKU page")
+	})
+}
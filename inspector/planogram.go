@@ -0,0 +1,339 @@
+package inspector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Definition formats of planograms and must-have lists
+const (
+	PlanogramFormatJSON = "json"
+	PlanogramFormatYAML = "yaml"
+)
+
+// Planogram slot statuses
+const (
+	PlanogramSlotCORRECT   = "CORRECT"   // on the planned shelf, in planned order
+	PlanogramSlotMISPLACED = "MISPLACED" // present, but on another shelf or out of order
+	PlanogramSlotMISSING   = "MISSING"   // not found on the realogram
+	PlanogramSlotEXTRA     = "EXTRA"     // found on the realogram, not planned
+)
+
+// Planogram diff operations
+const (
+	PlanogramDiffADD     = "add"     // place a missing SKU
+	PlanogramDiffMOVE    = "move"    // move a misplaced SKU to its slot
+	PlanogramDiffREMOVE  = "remove"  // remove an unplanned SKU
+	PlanogramDiffFACINGS = "facings" // add facings to reach MinFacings
+)
+
+// ErrInvalidPlanogram is returned for planogram definitions that fail validation.
+var ErrInvalidPlanogram = errors.New("invalid planogram")
+
+// Planogram is a planned shelf layout of one bay.
+type Planogram struct {
+	Name    string           `json:"name" yaml:"name"`
+	Shelves []PlanogramShelf `json:"shelves" yaml:"shelves"` // top to bottom, like RealogramLayout.Shelves
+}
+
+// PlanogramShelf holds the slots of one shelf.
+type PlanogramShelf struct {
+	Slots []PlanogramSlot `json:"slots" yaml:"slots"` // left to right
+}
+
+// PlanogramSlot is a planned SKU position.
+type PlanogramSlot struct {
+	SkuId      int `json:"sku_id" yaml:"sku_id"`
+	MinFacings int `json:"min_facings,omitempty" yaml:"min_facings,omitempty"` // default 1
+}
+
+// minFacings returns MinFacings with the default applied.
+func (s PlanogramSlot) minFacings() int {
+	if s.MinFacings <= 0 {
+		return 1
+	}
+	return s.MinFacings
+}
+
+// Validate checks that the planogram has shelves and every slot has a SKU.
+func (p *Planogram) Validate() error {
+	if len(p.Shelves) == 0 {
+		return fmt.Errorf("planogram %q has no shelves:%w", p.Name, ErrInvalidPlanogram)
+	}
+	for i, shelf := range p.Shelves {
+		for j, slot := range shelf.Slots {
+			if slot.SkuId <= 0 {
+				return fmt.Errorf("planogram %q shelf %d slot %d has no sku_id:%w", p.Name, i, j, ErrInvalidPlanogram)
+			}
+			if slot.MinFacings < 0 {
+				return fmt.Errorf("planogram %q shelf %d slot %d has negative min_facings:%w", p.Name, i, j, ErrInvalidPlanogram)
+			}
+		}
+	}
+	return nil
+}
+
+// DecodePlanogram reads and validates a planogram in PlanogramFormatJSON or PlanogramFormatYAML.
+func DecodePlanogram(r io.Reader, format string) (*Planogram, error) {
+	var p Planogram
+	if err := decodeDefinition(r, format, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode planogram:%w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// LoadPlanogram reads a planogram file; .yaml and .yml files are YAML, others JSON.
+func LoadPlanogram(path string) (*Planogram, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open planogram %s:%w", path, err)
+	}
+	defer f.Close()
+	return DecodePlanogram(f, definitionFormat(path))
+}
+
+// decodeDefinition decodes a JSON or YAML definition file into v, rejecting unknown fields.
+func decodeDefinition(r io.Reader, format string, v any) error {
+	switch format {
+	case PlanogramFormatJSON:
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		return dec.Decode(v)
+	case PlanogramFormatYAML:
+		dec := yaml.NewDecoder(r)
+		dec.KnownFields(true)
+		return dec.Decode(v)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// definitionFormat picks the definition format by file extension.
+func definitionFormat(path string) string {
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		return PlanogramFormatYAML
+	}
+	return PlanogramFormatJSON
+}
+
+// PlanogramLocation is a position on the shelves: a slot index for planned SKUs,
+// the index of a run of equal adjacent facings for products found on the realogram.
+type PlanogramLocation struct {
+	Shelf    int `json:"shelf"`
+	Position int `json:"position"`
+}
+
+// PlanogramSlotResult is the compliance of one planned slot, or of an EXTRA product.
+type PlanogramSlotResult struct {
+	PlanogramLocation
+	SkuId      int                `json:"sku_id"`
+	Status     string             `json:"status"`
+	Facings    int                `json:"facings"`
+	MinFacings int                `json:"min_facings,omitempty"`
+	Found      *PlanogramLocation `json:"found,omitempty"` // where a MISPLACED SKU was found
+}
+
+// PlanogramDiff is one change that brings the shelf closer to the planogram.
+type PlanogramDiff struct {
+	Op      string             `json:"op"`
+	SkuId   int                `json:"sku_id"`
+	At      PlanogramLocation  `json:"at"`             // planned slot; for remove, the found location
+	From    *PlanogramLocation `json:"from,omitempty"` // move: where the SKU is now
+	Facings int                `json:"facings"`        // facings to add, move or remove
+}
+
+// PlanogramCompliance is the result of CheckPlanogram.
+type PlanogramCompliance struct {
+	Planogram string                `json:"planogram"`
+	Image     int                   `json:"image"`
+	Score     float64               `json:"score"` // 0..1
+	Slots     []PlanogramSlotResult `json:"slots"` // planned slots in planogram order
+	Extra     []PlanogramSlotResult `json:"extra,omitempty"`
+	Diff      []PlanogramDiff       `json:"diff,omitempty"`
+}
+
+// Counts returns the number of slots per status, EXTRA included.
+func (c *PlanogramCompliance) Counts() map[string]int {
+	counts := map[string]int{}
+	for _, s := range c.Slots {
+		counts[s.Status]++
+	}
+	counts[PlanogramSlotEXTRA] += len(c.Extra)
+	return counts
+}
+
+// PlanogramOptions configures CheckPlanogram.
+type PlanogramOptions struct {
+	Realogram *RealogramOptions // shelf assignment and duplicate handling
+}
+
+// facingRun is a run of adjacent front-row facings of one SKU.
+type facingRun struct {
+	sku     int
+	facings int
+	used    bool
+}
+
+// CheckPlanogram compares a planogram with the shelf-assigned products of a realogram.
+//
+// Shelves are matched by index, top to bottom. On each shelf adjacent front-row
+// facings of a SKU form a run; runs are aligned with the planned slots in order
+// (longest common subsequence) and aligned slots are CORRECT. Other slots are
+// MISPLACED when a run of their SKU is left anywhere (same shelf first, then the
+// nearest shelf), otherwise MISSING. Remaining runs are EXTRA.
+//
+// Each slot scores min(Facings/MinFacings, 1), halved for MISPLACED; Score is the
+// mean over all slots. EXTRA products do not lower the score but appear in Diff.
+func CheckPlanogram(p *Planogram, r ReportRealogramJson, opts *PlanogramOptions) *PlanogramCompliance {
+	var o PlanogramOptions
+	if opts != nil {
+		o = *opts
+	}
+	layout := AnalyzeRealogram(r, o.Realogram)
+	includeDuplicates := o.Realogram != nil && o.Realogram.IncludeDuplicates
+
+	runs := make([][]*facingRun, len(layout.Shelves))
+	for i, s := range layout.Shelves {
+		for _, prod := range s.Products {
+			if prod.Stacked || (prod.Duplicate && !includeDuplicates) {
+				continue
+			}
+			if n := len(runs[i]); n > 0 && runs[i][n-1].sku == prod.SkuId {
+				runs[i][n-1].facings++
+				continue
+			}
+			runs[i] = append(runs[i], &facingRun{sku: prod.SkuId, facings: 1})
+		}
+	}
+
+	res := &PlanogramCompliance{Planogram: p.Name, Image: r.Image}
+	slots := make([][]PlanogramSlotResult, len(p.Shelves))
+	for i, shelf := range p.Shelves {
+		for j, slot := range shelf.Slots {
+			slots[i] = append(slots[i], PlanogramSlotResult{
+				PlanogramLocation: PlanogramLocation{Shelf: i, Position: j},
+				SkuId:             slot.SkuId,
+				Status:            PlanogramSlotMISSING,
+				MinFacings:        slot.minFacings(),
+			})
+		}
+		if i < len(runs) {
+			alignSlots(slots[i], runs[i])
+		}
+	}
+
+	for i := range slots {
+		for j := range slots[i] {
+			s := &slots[i][j]
+			if s.Status != PlanogramSlotMISSING {
+				continue
+			}
+			if loc, run := findRun(runs, s.SkuId, i); run != nil {
+				run.used = true
+				s.Status = PlanogramSlotMISPLACED
+				s.Facings = run.facings
+				s.Found = loc
+			}
+		}
+	}
+
+	var earned float64
+	total := 0
+	for _, shelf := range slots {
+		for _, s := range shelf {
+			total++
+			credit := min(float64(s.Facings)/float64(s.MinFacings), 1)
+			switch s.Status {
+			case PlanogramSlotCORRECT:
+				earned += credit
+			case PlanogramSlotMISPLACED:
+				earned += credit / 2
+				res.Diff = append(res.Diff, PlanogramDiff{Op: PlanogramDiffMOVE, SkuId: s.SkuId, At: s.PlanogramLocation, From: s.Found, Facings: s.Facings})
+			case PlanogramSlotMISSING:
+				res.Diff = append(res.Diff, PlanogramDiff{Op: PlanogramDiffADD, SkuId: s.SkuId, At: s.PlanogramLocation, Facings: s.MinFacings})
+			}
+			if s.Status != PlanogramSlotMISSING && s.Facings < s.MinFacings {
+				res.Diff = append(res.Diff, PlanogramDiff{Op: PlanogramDiffFACINGS, SkuId: s.SkuId, At: s.PlanogramLocation, Facings: s.MinFacings - s.Facings})
+			}
+			res.Slots = append(res.Slots, s)
+		}
+	}
+	if total > 0 {
+		res.Score = earned / float64(total)
+	}
+
+	for i, shelf := range runs {
+		for j, run := range shelf {
+			if run.used {
+				continue
+			}
+			loc := PlanogramLocation{Shelf: i, Position: j}
+			res.Extra = append(res.Extra, PlanogramSlotResult{PlanogramLocation: loc, SkuId: run.sku, Status: PlanogramSlotEXTRA, Facings: run.facings})
+			res.Diff = append(res.Diff, PlanogramDiff{Op: PlanogramDiffREMOVE, SkuId: run.sku, At: loc, Facings: run.facings})
+		}
+	}
+	return res
+}
+
+// alignSlots marks the longest in-order match between slots and runs as CORRECT.
+func alignSlots(slots []PlanogramSlotResult, runs []*facingRun) {
+	n, m := len(slots), len(runs)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if slots[i].SkuId == runs[j].sku {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case slots[i].SkuId == runs[j].sku:
+			slots[i].Status = PlanogramSlotCORRECT
+			slots[i].Facings = runs[j].facings
+			runs[j].used = true
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+}
+
+// findRun returns the first unused run of sku, searching shelf first and then
+// the shelves at increasing distance from it.
+func findRun(runs [][]*facingRun, sku, shelf int) (*PlanogramLocation, *facingRun) {
+	for d := 0; shelf-d >= 0 || shelf+d < len(runs); d++ {
+		candidates := []int{shelf - d, shelf + d}
+		if d == 0 {
+			candidates = candidates[:1]
+		}
+		for _, i := range candidates {
+			if i < 0 || i >= len(runs) {
+				continue
+			}
+			for j, run := range runs[i] {
+				if !run.used && run.sku == sku {
+					return &PlanogramLocation{Shelf: i, Position: j}, run
+				}
+			}
+		}
+	}
+	return nil, nil
+}
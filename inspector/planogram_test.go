@@ -0,0 +1,149 @@
+package inspector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func planogramTestRealogram() ReportRealogramJson {
+	product := func(i, shelf, sku int) ReportRealogramAnnotations {
+		return ReportRealogramAnnotations{X: 50 + 100*i, Y: 150 + 200*shelf, W: 100, H: 100, SkuId: sku}
+	}
+	return ReportRealogramJson{
+		Image: 7,
+		Annotations: []ReportRealogramAnnotations{
+			product(0, 0, 1), product(1, 0, 1), product(2, 0, 2), product(3, 0, 3),
+			product(0, 1, 4), product(1, 1, 5), product(2, 1, 7),
+		},
+		ShelfAnnotations: []ReportRealogramShelfAnnotations{
+			{X1: 0, Y1: 200, X2: 1000, Y2: 200},
+			{X1: 0, Y1: 400, X2: 1000, Y2: 400},
+		},
+	}
+}
+
+func TestCheckPlanogram(t *testing.T) {
+	p := &Planogram{Name: "bay-1", Shelves: []PlanogramShelf{
+		{Slots: []PlanogramSlot{{SkuId: 1, MinFacings: 2}, {SkuId: 3}, {SkuId: 2}}},
+		{Slots: []PlanogramSlot{{SkuId: 4}, {SkuId: 6}, {SkuId: 5, MinFacings: 2}}},
+	}}
+
+	got := CheckPlanogram(p, planogramTestRealogram(), nil)
+
+	assert.Equal(t, "bay-1", got.Planogram)
+	assert.Equal(t, 7, got.Image)
+	assert.Equal(t, []PlanogramSlotResult{
+		{PlanogramLocation: PlanogramLocation{0, 0}, SkuId: 1, Status: PlanogramSlotCORRECT, Facings: 2, MinFacings: 2},
+		{PlanogramLocation: PlanogramLocation{0, 1}, SkuId: 3, Status: PlanogramSlotMISPLACED, Facings: 1, MinFacings: 1, Found: &PlanogramLocation{0, 2}},
+		{PlanogramLocation: PlanogramLocation{0, 2}, SkuId: 2, Status: PlanogramSlotCORRECT, Facings: 1, MinFacings: 1},
+		{PlanogramLocation: PlanogramLocation{1, 0}, SkuId: 4, Status: PlanogramSlotCORRECT, Facings: 1, MinFacings: 1},
+		{PlanogramLocation: PlanogramLocation{1, 1}, SkuId: 6, Status: PlanogramSlotMISSING, MinFacings: 1},
+		{PlanogramLocation: PlanogramLocation{1, 2}, SkuId: 5, Status: PlanogramSlotCORRECT, Facings: 1, MinFacings: 2},
+	}, got.Slots)
+	assert.Equal(t, []PlanogramSlotResult{
+		{PlanogramLocation: PlanogramLocation{1, 2}, SkuId: 7, Status: PlanogramSlotEXTRA, Facings: 1},
+	}, got.Extra)
+	assert.InDelta(t, 4.0/6, got.Score, 1e-9)
+	assert.Equal(t, map[string]int{PlanogramSlotCORRECT: 4, PlanogramSlotMISPLACED: 1, PlanogramSlotMISSING: 1, PlanogramSlotEXTRA: 1}, got.Counts())
+
+	assert.Equal(t, []PlanogramDiff{
+		{Op: PlanogramDiffMOVE, SkuId: 3, At: PlanogramLocation{0, 1}, From: &PlanogramLocation{0, 2}, Facings: 1},
+		{Op: PlanogramDiffADD, SkuId: 6, At: PlanogramLocation{1, 1}, Facings: 1},
+		{Op: PlanogramDiffFACINGS, SkuId: 5, At: PlanogramLocation{1, 2}, Facings: 1},
+		{Op: PlanogramDiffREMOVE, SkuId: 7, At: PlanogramLocation{1, 2}, Facings: 1},
+	}, got.Diff)
+}
+
+func TestCheckPlanogram_OtherShelf(t *testing.T) {
+	p := &Planogram{Shelves: []PlanogramShelf{
+		{Slots: []PlanogramSlot{{SkuId: 1}, {SkuId: 2}, {SkuId: 3}, {SkuId: 7}}},
+		{Slots: []PlanogramSlot{{SkuId: 4}, {SkuId: 5}}},
+		{Slots: []PlanogramSlot{{SkuId: 8}}}, // shelf missing on the photo
+	}}
+
+	got := CheckPlanogram(p, planogramTestRealogram(), nil)
+
+	assert.Equal(t, PlanogramSlotMISPLACED, got.Slots[3].Status)
+	assert.Equal(t, &PlanogramLocation{1, 2}, got.Slots[3].Found)
+	assert.Equal(t, PlanogramSlotMISSING, got.Slots[6].Status)
+	assert.Empty(t, got.Extra)
+	assert.InDelta(t, 5.5/7, got.Score, 1e-9)
+}
+
+func TestCheckPlanogram_Testdata(t *testing.T) {
+	realogram := loadRealogramReport(t)[0]
+
+	// the photo as it is must be fully compliant with a planogram made from it
+	p := &Planogram{Name: "as-is"}
+	for _, s := range AnalyzeRealogram(realogram, nil).Shelves {
+		var shelf PlanogramShelf
+		for _, prod := range s.Products {
+			if prod.Stacked || prod.Duplicate {
+				continue
+			}
+			if n := len(shelf.Slots); n > 0 && shelf.Slots[n-1].SkuId == prod.SkuId {
+				shelf.Slots[n-1].MinFacings++
+				continue
+			}
+			shelf.Slots = append(shelf.Slots, PlanogramSlot{SkuId: prod.SkuId, MinFacings: 1})
+		}
+		p.Shelves = append(p.Shelves, shelf)
+	}
+
+	got := CheckPlanogram(p, realogram, nil)
+	assert.Equal(t, 1.0, got.Score)
+	assert.Empty(t, got.Diff)
+	assert.Equal(t, len(got.Slots), got.Counts()[PlanogramSlotCORRECT])
+}
+
+func TestDecodePlanogram(t *testing.T) {
+	want := &Planogram{Name: "bay-1", Shelves: []PlanogramShelf{
+		{Slots: []PlanogramSlot{{SkuId: 1, MinFacings: 2}, {SkuId: 3}}},
+	}}
+
+	t.Run("json", func(t *testing.T) {
+		got, err := DecodePlanogram(strings.NewReader(`{"name":"bay-1","shelves":[{"slots":[{"sku_id":1,"min_facings":2},{"sku_id":3}]}]}`), PlanogramFormatJSON)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		got, err := DecodePlanogram(strings.NewReader(`
+name: bay-1
+shelves:
+  - slots:
+      - {sku_id: 1, min_facings: 2}
+      - sku_id: 3
+`), PlanogramFormatYAML)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := DecodePlanogram(strings.NewReader("shelves:\n  - slots:\n      - sku: 1\n"), PlanogramFormatYAML)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := DecodePlanogram(strings.NewReader(`{"name":"x","shelves":[{"slots":[{"min_facings":2}]}]}`), PlanogramFormatJSON)
+		assert.ErrorIs(t, err, ErrInvalidPlanogram)
+		_, err = DecodePlanogram(strings.NewReader(`{"name":"x"}`), PlanogramFormatJSON)
+		assert.ErrorIs(t, err, ErrInvalidPlanogram)
+	})
+
+	t.Run("load by extension", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "bay.yml")
+		assert.NoError(t, os.WriteFile(path, []byte("name: bay-1\nshelves:\n  - slots:\n      - {sku_id: 1, min_facings: 2}\n      - {sku_id: 3}\n"), 0o600))
+		got, err := LoadPlanogram(path)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+
+		_, err = LoadPlanogram(filepath.Join(dir, "missing.json"))
+		assert.Error(t, err)
+	})
+}
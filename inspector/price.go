@@ -0,0 +1,248 @@
+package inspector
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Price audit finding kinds
+const (
+	PriceFindingDEVIATION      = "DEVIATION"      // tag price outside the tolerance of the expected price
+	PriceFindingMISSING_TAG    = "MISSING_TAG"    // SKU on shelf without a price tag
+	PriceFindingPROMO_MISMATCH = "PROMO_MISMATCH" // promo tag without a running promo, or regular tag during a promo
+)
+
+// Price list CSV columns
+const (
+	priceColumnSkuID       = "sku_id"
+	priceColumnRetailChain = "retail_chain"
+	priceColumnPrice       = "price"
+	priceColumnPromoPrice  = "promo_price"
+)
+
+// ErrInvalidPriceList is returned for price lists that cannot be parsed.
+var ErrInvalidPriceList = errors.New("invalid price list")
+
+// IsPromo reports whether the price tag was recognized as a promo tag.
+func (p ReportPriceTagsJson) IsPromo() bool {
+	v, err := strconv.ParseBool(p.Promo)
+	return err == nil && v
+}
+
+// ExpectedPrice is the recommended retail price of a SKU.
+type ExpectedPrice struct {
+	SkuId       int      `json:"sku_id"`
+	RetailChain string   `json:"retail_chain,omitempty"` // empty: every chain without its own entry
+	Price       float64  `json:"price"`                  // regular price
+	PromoPrice  *float64 `json:"promo_price,omitempty"`  // set while a promo is running
+}
+
+// PriceList holds expected prices.
+type PriceList []ExpectedPrice
+
+// Lookup returns the expected price of sku for retailChain, falling back to the entry without chain.
+func (l PriceList) Lookup(sku int, retailChain string) (ExpectedPrice, bool) {
+	var fallback *ExpectedPrice
+	for i, p := range l {
+		if p.SkuId != sku {
+			continue
+		}
+		if p.RetailChain == retailChain {
+			return p, true
+		}
+		if p.RetailChain == "" && fallback == nil {
+			fallback = &l[i]
+		}
+	}
+	if fallback != nil {
+		return *fallback, true
+	}
+	return ExpectedPrice{}, false
+}
+
+// ReadPriceListCSV reads a price list with a header row. Columns are matched by name:
+// sku_id and price are required, retail_chain and promo_price are optional.
+func ReadPriceListCSV(r io.Reader) (PriceList, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price list header:%w", err)
+	}
+	cols := map[string]int{}
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{priceColumnSkuID, priceColumnPrice} {
+		if _, ok := cols[name]; !ok {
+			return nil, fmt.Errorf("price list has no %s column:%w", name, ErrInvalidPriceList)
+		}
+	}
+	cell := func(rec []string, name string) string {
+		if i, ok := cols[name]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+
+	var list PriceList
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read price list line %d:%w", line, err)
+		}
+		p := ExpectedPrice{RetailChain: cell(rec, priceColumnRetailChain)}
+		if p.SkuId, err = strconv.Atoi(cell(rec, priceColumnSkuID)); err != nil {
+			return nil, fmt.Errorf("price list line %d: sku_id %w:%w", line, err, ErrInvalidPriceList)
+		}
+		if p.Price, err = strconv.ParseFloat(cell(rec, priceColumnPrice), 64); err != nil {
+			return nil, fmt.Errorf("price list line %d: price %w:%w", line, err, ErrInvalidPriceList)
+		}
+		if v := cell(rec, priceColumnPromoPrice); v != "" {
+			promo, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("price list line %d: promo_price %w:%w", line, err, ErrInvalidPriceList)
+			}
+			p.PromoPrice = &promo
+		}
+		list = append(list, p)
+	}
+	return list, nil
+}
+
+// ReadPriceListJSON reads a JSON array of ExpectedPrice.
+func ReadPriceListJSON(r io.Reader) (PriceList, error) {
+	var list PriceList
+	if err := json.NewDecoder(r).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode price list:%w", err)
+	}
+	return list, nil
+}
+
+// LoadPriceList reads a price list file; .csv files are CSV, others JSON.
+func LoadPriceList(path string) (PriceList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open price list %s:%w", path, err)
+	}
+	defer f.Close()
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return ReadPriceListCSV(f)
+	}
+	return ReadPriceListJSON(f)
+}
+
+// PriceAuditOptions configures AuditPrices.
+// A tag price is compliant when it differs from the expected price by at most
+// the larger of AbsTolerance and PctTolerance percent of the expected price.
+type PriceAuditOptions struct {
+	RetailChain  string
+	AbsTolerance float64     // allowed absolute deviation, in currency units
+	PctTolerance float64     // allowed deviation, in percent of the expected price
+	Faced        map[int]int // facings by SKU ID; faced SKUs without a tag are reported as MISSING_TAG
+}
+
+// PriceFinding is a price compliance issue.
+type PriceFinding struct {
+	SkuId        int     `json:"sku_id"`
+	Name         string  `json:"name,omitempty"`
+	Kind         string  `json:"kind"`
+	Promo        bool    `json:"promo"`    // the tag is a promo tag
+	Expected     float64 `json:"expected"` // expected price for the tag kind; 0 if the SKU is not in the list
+	Actual       float64 `json:"actual"`   // tag price; 0 for MISSING_TAG
+	Deviation    float64 `json:"deviation"`
+	DeviationPct float64 `json:"deviation_pct"`
+}
+
+// PriceAudit is the result of AuditPrices.
+type PriceAudit struct {
+	RetailChain string         `json:"retail_chain,omitempty"`
+	Checked     int            `json:"checked"`   // tags of listed SKUs
+	Compliant   int            `json:"compliant"` // checked tags without findings
+	Findings    []PriceFinding `json:"findings"`  // ordered by SKU ID
+	Unlisted    []int          `json:"unlisted,omitempty"`
+}
+
+// Compliance returns the percent of checked tags without findings.
+func (a *PriceAudit) Compliance() float64 {
+	if a.Checked == 0 {
+		return 0
+	}
+	return 100 * float64(a.Compliant) / float64(a.Checked)
+}
+
+// AuditPrices compares the tags of a PRICE_TAGS report with expected prices.
+//
+// A promo tag is compared with PromoPrice; when no promo is running it is a
+// PROMO_MISMATCH. A regular tag during a promo is a PROMO_MISMATCH too. Other
+// tags are compared with Price. Every tag is checked on its own. Tags of SKUs
+// missing from the list are listed in Unlisted.
+func AuditPrices(tags []ReportPriceTagsJson, list PriceList, opts *PriceAuditOptions) *PriceAudit {
+	var o PriceAuditOptions
+	if opts != nil {
+		o = *opts
+	}
+	audit := &PriceAudit{RetailChain: o.RetailChain}
+	tagged := map[int]bool{}
+	unlisted := map[int]bool{}
+
+	for _, tag := range tags {
+		tagged[tag.SkuId] = true
+		exp, ok := list.Lookup(tag.SkuId, o.RetailChain)
+		if !ok {
+			unlisted[tag.SkuId] = true
+			continue
+		}
+		audit.Checked++
+
+		f := PriceFinding{SkuId: tag.SkuId, Name: tag.Name, Promo: tag.IsPromo(), Actual: tag.Price, Expected: exp.Price}
+		switch {
+		case f.Promo && exp.PromoPrice == nil, !f.Promo && exp.PromoPrice != nil:
+			f.Kind = PriceFindingPROMO_MISMATCH
+		case f.Promo:
+			f.Expected = *exp.PromoPrice
+		}
+		f.Deviation = f.Actual - f.Expected
+		if f.Expected != 0 {
+			f.DeviationPct = 100 * f.Deviation / f.Expected
+		}
+		if f.Kind == "" && math.Abs(f.Deviation) > max(o.AbsTolerance, o.PctTolerance*math.Abs(f.Expected)/100) {
+			f.Kind = PriceFindingDEVIATION
+		}
+		if f.Kind == "" {
+			audit.Compliant++
+			continue
+		}
+		audit.Findings = append(audit.Findings, f)
+	}
+
+	for sku, n := range o.Faced {
+		if n <= 0 || tagged[sku] {
+			continue
+		}
+		f := PriceFinding{SkuId: sku, Kind: PriceFindingMISSING_TAG}
+		if exp, ok := list.Lookup(sku, o.RetailChain); ok {
+			f.Expected = exp.Price
+		}
+		audit.Findings = append(audit.Findings, f)
+	}
+
+	sort.SliceStable(audit.Findings, func(i, j int) bool { return audit.Findings[i].SkuId < audit.Findings[j].SkuId })
+	for sku := range unlisted {
+		audit.Unlisted = append(audit.Unlisted, sku)
+	}
+	sort.Ints(audit.Unlisted)
+	return audit
+}
@@ -0,0 +1,135 @@
+package inspector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const priceListTestCSV = `sku_id,retail_chain,price,promo_price
+1,,100,
+1,magnit,110,
+2,,200,150
+3,,50,
+`
+
+func TestReadPriceList(t *testing.T) {
+	promo := 150.0
+	want := PriceList{
+		{SkuId: 1, Price: 100},
+		{SkuId: 1, RetailChain: "magnit", Price: 110},
+		{SkuId: 2, Price: 200, PromoPrice: &promo},
+		{SkuId: 3, Price: 50},
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		got, err := ReadPriceListCSV(strings.NewReader(priceListTestCSV))
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("csv columns in any order", func(t *testing.T) {
+		got, err := ReadPriceListCSV(strings.NewReader("price,sku_id\n9.5,7\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, PriceList{{SkuId: 7, Price: 9.5}}, got)
+	})
+
+	t.Run("csv errors", func(t *testing.T) {
+		_, err := ReadPriceListCSV(strings.NewReader("sku_id\n1\n"))
+		assert.ErrorIs(t, err, ErrInvalidPriceList)
+		_, err = ReadPriceListCSV(strings.NewReader("sku_id,price\n1,abc\n"))
+		assert.ErrorIs(t, err, ErrInvalidPriceList)
+	})
+
+	t.Run("files", func(t *testing.T) {
+		dir := t.TempDir()
+		csvPath := filepath.Join(dir, "prices.csv")
+		jsonPath := filepath.Join(dir, "prices.json")
+		assert.NoError(t, os.WriteFile(csvPath, []byte(priceListTestCSV), 0o600))
+		assert.NoError(t, os.WriteFile(jsonPath, []byte(`[{"sku_id":1,"price":100},{"sku_id":1,"retail_chain":"magnit","price":110},{"sku_id":2,"price":200,"promo_price":150},{"sku_id":3,"price":50}]`), 0o600))
+
+		got, err := LoadPriceList(csvPath)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+		got, err = LoadPriceList(jsonPath)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+}
+
+func TestPriceList_Lookup(t *testing.T) {
+	list, err := ReadPriceListCSV(strings.NewReader(priceListTestCSV))
+	assert.NoError(t, err)
+
+	p, ok := list.Lookup(1, "magnit")
+	assert.True(t, ok)
+	assert.Equal(t, 110.0, p.Price)
+	p, ok = list.Lookup(1, "x5")
+	assert.True(t, ok)
+	assert.Equal(t, 100.0, p.Price)
+	_, ok = list.Lookup(9, "")
+	assert.False(t, ok)
+}
+
+func TestAuditPrices(t *testing.T) {
+	list, err := ReadPriceListCSV(strings.NewReader(priceListTestCSV))
+	assert.NoError(t, err)
+	tags := []ReportPriceTagsJson{
+		{SkuId: 1, Name: "A", Price: 112},               // magnit 110, within 2%
+		{SkuId: 1, Name: "A", Price: 120},               // 10 over 110
+		{SkuId: 2, Name: "B", Price: 151, Promo: "1"},   // promo 150, within 1
+		{SkuId: 2, Name: "B", Price: 200},               // regular tag during promo
+		{SkuId: 3, Name: "C", Price: 45, Promo: "true"}, // promo tag, no promo running
+		{SkuId: 8, Name: "X", Price: 10},                // not in the list
+	}
+
+	audit := AuditPrices(tags, list, &PriceAuditOptions{
+		RetailChain:  "magnit",
+		AbsTolerance: 1,
+		PctTolerance: 2,
+		Faced:        map[int]int{1: 3, 4: 2, 5: 0},
+	})
+
+	assert.Equal(t, "magnit", audit.RetailChain)
+	assert.Equal(t, 5, audit.Checked)
+	assert.Equal(t, 2, audit.Compliant)
+	assert.InDelta(t, 40, audit.Compliance(), 1e-9)
+	assert.Equal(t, []int{8}, audit.Unlisted)
+
+	assert.Len(t, audit.Findings, 4)
+	assert.Equal(t, PriceFinding{SkuId: 1, Name: "A", Kind: PriceFindingDEVIATION, Expected: 110, Actual: 120, Deviation: 10, DeviationPct: 100.0 / 11}, audit.Findings[0])
+	assert.Equal(t, PriceFinding{SkuId: 2, Name: "B", Kind: PriceFindingPROMO_MISMATCH, Expected: 200, Actual: 200}, audit.Findings[1])
+	assert.Equal(t, PriceFinding{SkuId: 3, Name: "C", Kind: PriceFindingPROMO_MISMATCH, Promo: true, Expected: 50, Actual: 45, Deviation: -5, DeviationPct: -10}, audit.Findings[2])
+	assert.Equal(t, PriceFinding{SkuId: 4, Kind: PriceFindingMISSING_TAG}, audit.Findings[3])
+}
+
+func TestAuditPrices_Report(t *testing.T) {
+	var srv ReportService
+	tags, err := srv.ToPriceTags([]map[string]any{{"sku_id": 9859, "price": 360.0, "promo": true, "name": "Bref"}})
+	assert.NoError(t, err)
+	assert.True(t, tags[0].IsPromo())
+
+	promo := 359.0
+	audit := AuditPrices(tags, PriceList{{SkuId: 9859, Price: 400, PromoPrice: &promo}}, &PriceAuditOptions{AbsTolerance: 1})
+	assert.Empty(t, audit.Findings)
+	assert.Equal(t, 100.0, audit.Compliance())
+}
+
+func TestAuditPrices_Webhook(t *testing.T) {
+	tags := loadWebhookReports(t).Reports.PriceTags
+	promo9859, promo9869 := 360.0, 320.0
+	list := PriceList{
+		{SkuId: 9859, Price: 400, PromoPrice: &promo9859},
+		{SkuId: 9869, Price: 350, PromoPrice: &promo9869},
+		{SkuId: 12106, Price: 149},
+	}
+
+	audit := AuditPrices(tags, list, nil)
+	assert.Equal(t, 3, audit.Checked)
+	assert.Equal(t, 3, audit.Compliant)
+	assert.Empty(t, audit.Findings)
+	assert.Len(t, audit.Unlisted, 9)
+}
@@ -0,0 +1,602 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	httpclient "github.com/germangorelkin/http-client"
+)
+
+// Job statuses
+const (
+	JobStatusPENDING = "PENDING" // waiting for a worker or for the next retry
+	JobStatusRUNNING = "RUNNING" // being processed by a worker
+	JobStatusDONE    = "DONE"    // images uploaded and recognition started, see Job.Response
+	JobStatusFAILED  = "FAILED"  // permanent error or retries exhausted, see Job.LastError
+)
+
+var (
+	// ErrJobNotFound is returned when the queue has no job with the given ID.
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobFailed is returned by Wait for jobs that ended in JobStatusFAILED.
+	ErrJobFailed = errors.New("job failed")
+)
+
+// JobImage is an image of a queued job, kept either as raw bytes or as a URL.
+type JobImage struct {
+	Filename string `json:"filename,omitempty"`
+	Data     []byte `json:"data,omitempty"` // image bytes, dropped once the job is done
+	URL      string `json:"url,omitempty"`  // uploaded with UploadByURL when set
+}
+
+// NewJobImage reads the whole image from r so the job can be replayed after a restart.
+func NewJobImage(r io.Reader, filename string) (JobImage, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return JobImage{}, fmt.Errorf("failed to read image %s:%w", filename, err)
+	}
+	return JobImage{Filename: filename, Data: data}, nil
+}
+
+// Job represents an upload + recognize job of a JobQueue.
+type Job struct {
+	ID          int                `json:"id"`
+	Status      string             `json:"status"`
+	Images      []JobImage         `json:"images"`
+	Request     RecognizeRequest   `json:"request"`             // Images is filled with ImageIDs by the queue
+	ImageIDs    []int              `json:"image_ids,omitempty"` // images uploaded so far, not re-uploaded on retry
+	Attempts    int                `json:"attempts"`
+	NextAttempt time.Time          `json:"next_attempt,omitempty"`
+	LastError   string             `json:"last_error,omitempty"`
+	Response    *RecognizeResponse `json:"response,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// Finished reports whether the job reached a terminal status.
+func (j *Job) Finished() bool {
+	return j.Status == JobStatusDONE || j.Status == JobStatusFAILED
+}
+
+// JobQueueOptions configures a JobQueue.
+type JobQueueOptions struct {
+	Workers      int           // concurrent workers (default: 1)
+	MaxAttempts  int           // attempts before a retryable job fails (default: DefaultJobMaxAttempts)
+	BaseBackoff  time.Duration // delay after the first failure, doubled on each retry (default: DefaultJobBaseBackoff)
+	MaxBackoff   time.Duration // upper bound of the retry delay (default: DefaultJobMaxBackoff)
+	PollInterval time.Duration // how often idle workers re-check the queue (default: DefaultJobPollInterval)
+	CompactEvery int           // records appended before the log is compacted (default: DefaultJobCompactEvery)
+}
+
+func applyJobQueueDefaults(opts *JobQueueOptions) JobQueueOptions {
+	var o JobQueueOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Workers <= 0 {
+		o.Workers = 1
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = DefaultJobMaxAttempts
+	}
+	if o.BaseBackoff <= 0 {
+		o.BaseBackoff = DefaultJobBaseBackoff
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = DefaultJobMaxBackoff
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = DefaultJobPollInterval
+	}
+	if o.CompactEvery <= 0 {
+		o.CompactEvery = DefaultJobCompactEvery
+	}
+	return o
+}
+
+// JobQueue is a durable local queue of upload + recognize jobs.
+//
+// Every state change is appended as a JSON line to a write-ahead log file and
+// synced to disk, so jobs survive process restarts: OpenJobQueue replays the
+// log, compacts it and puts interrupted jobs back to pending. Image bytes are
+// written once, by Enqueue and by compaction; the other records only carry the
+// state change. The log is also compacted every CompactEvery records. Jobs are
+// processed at least once; a crash between the recognize call and the log
+// write repeats the recognition on restart.
+type JobQueue struct {
+	client API
+	path   string
+	opts   JobQueueOptions
+
+	mu     sync.Mutex
+	f      *os.File
+	jobs   map[int]*Job
+	done   map[int]chan struct{}
+	lastID int
+	wake   chan struct{}
+
+	appended int   // records appended since the last compaction
+	err      error // first log write or compaction failure, stops Run
+}
+
+// OpenJobQueue opens or creates the queue log at path. Jobs use the image and recognize services of c.
+func OpenJobQueue(c API, path string, opts *JobQueueOptions) (*JobQueue, error) {
+	o := applyJobQueueDefaults(opts)
+	q := &JobQueue{
+		client: c,
+		path:   path,
+		opts:   o,
+		jobs:   make(map[int]*Job),
+		done:   make(map[int]chan struct{}),
+		wake:   make(chan struct{}, o.Workers),
+	}
+	if err := q.load(); err != nil {
+		return nil, err
+	}
+	if err := q.compact(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// load replays the log. A torn last line left by a crash is ignored.
+func (q *JobQueue) load() error {
+	f, err := os.Open(q.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open queue %s:%w", q.path, err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for line := 1; ; line++ {
+		b, readErr := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(b)) > 0 {
+			var job Job
+			if err := json.Unmarshal(b, &job); err != nil {
+				if readErr == io.EOF {
+					break
+				}
+				return fmt.Errorf("failed to decode queue %s line %d:%w", q.path, line, err)
+			}
+			if job.Status == JobStatusRUNNING {
+				job.Status = JobStatusPENDING
+			}
+			if prev, ok := q.jobs[job.ID]; ok && job.Status != JobStatusDONE {
+				restoreImageData(job.Images, prev.Images)
+			}
+			q.jobs[job.ID] = &job
+			q.lastID = max(q.lastID, job.ID)
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return fmt.Errorf("failed to read queue %s:%w", q.path, readErr)
+		}
+	}
+
+	for id, job := range q.jobs {
+		ch := make(chan struct{})
+		if job.Finished() {
+			close(ch)
+		}
+		q.done[id] = ch
+	}
+	return nil
+}
+
+// Compact rewrites the log with only the latest state of every job.
+func (q *JobQueue) Compact() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.compact()
+}
+
+func (q *JobQueue) compact() error {
+	tmp, err := os.CreateTemp(filepath.Dir(q.path), filepath.Base(q.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to compact queue %s:%w", q.path, err)
+	}
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, id := range q.sortedIDs() {
+		if err = enc.Encode(q.jobs[id]); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), q.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to compact queue %s:%w", q.path, err)
+	}
+
+	if q.f != nil {
+		q.f.Close()
+	}
+	q.appended = 0
+	q.f, err = os.OpenFile(q.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open queue %s:%w", q.path, err)
+	}
+	return nil
+}
+
+// persist appends the state change of the job to the log, without image bytes.
+// Callers hold q.mu.
+func (q *JobQueue) persist(job *Job) error {
+	return q.append(job, false)
+}
+
+// append writes the job as a log record; image bytes only when withData is set.
+// Callers hold q.mu.
+func (q *JobQueue) append(job *Job, withData bool) error {
+	job.UpdatedAt = time.Now().UTC()
+	rec := *job
+	if !withData {
+		rec.Images = make([]JobImage, len(job.Images))
+		for i, img := range job.Images {
+			rec.Images[i] = JobImage{Filename: img.Filename, URL: img.URL}
+		}
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode job %d:%w", job.ID, err)
+	}
+	if _, err := q.f.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("failed to write job %d:%w", job.ID, err)
+	}
+	if err := q.f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync job %d:%w", job.ID, err)
+	}
+
+	q.appended++
+	if q.appended >= q.opts.CompactEvery {
+		// The record is already durable; a failed compaction is reported by Run.
+		if err := q.compact(); err != nil {
+			q.setErr(err)
+		}
+	}
+	return nil
+}
+
+// setErr records the first persistence failure. Callers hold q.mu.
+func (q *JobQueue) setErr(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+// restoreImageData copies image bytes from the previous record of a job,
+// since only the enqueue record and compacted records carry them.
+func restoreImageData(images, prev []JobImage) {
+	for i := range images {
+		if i < len(prev) && images[i].Data == nil {
+			images[i].Data = prev[i].Data
+		}
+	}
+}
+
+// Close closes the queue log. Workers must be stopped first.
+func (q *JobQueue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.f == nil {
+		return nil
+	}
+	err := q.f.Close()
+	q.f = nil
+	return err
+}
+
+// Enqueue durably stores a job that uploads images and recognizes them with rr.
+// rr.Images is ignored and replaced with the uploaded image IDs.
+func (q *JobQueue) Enqueue(rr RecognizeRequest, images ...JobImage) (int, error) {
+	if len(images) == 0 {
+		return 0, errors.New("failed to enqueue job: no images")
+	}
+	rr.Images = nil
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.f == nil {
+		return 0, fmt.Errorf("failed to enqueue job: queue %s is closed", q.path)
+	}
+
+	now := time.Now().UTC()
+	job := &Job{
+		ID:        q.lastID + 1,
+		Status:    JobStatusPENDING,
+		Images:    images,
+		Request:   rr,
+		CreatedAt: now,
+	}
+	if err := q.append(job, true); err != nil {
+		return 0, err
+	}
+	q.lastID = job.ID
+	q.jobs[job.ID] = job
+	q.done[job.ID] = make(chan struct{})
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return job.ID, nil
+}
+
+// Status returns a snapshot of the job.
+func (q *JobQueue) Status(id int) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("failed to get job %d:%w", id, ErrJobNotFound)
+	}
+	return *job, nil
+}
+
+// Jobs returns snapshots of all jobs ordered by ID.
+func (q *JobQueue) Jobs() []Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	jobs := make([]Job, 0, len(q.jobs))
+	for _, id := range q.sortedIDs() {
+		jobs = append(jobs, *q.jobs[id])
+	}
+	return jobs
+}
+
+// Wait blocks until the job is finished and returns its recognition response.
+// Failed jobs return an error wrapping ErrJobFailed.
+func (q *JobQueue) Wait(ctx context.Context, id int) (*RecognizeResponse, error) {
+	q.mu.Lock()
+	ch, ok := q.done[id]
+	q.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("failed to wait job %d:%w", id, ErrJobNotFound)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("failed to wait job %d:%w", id, ctx.Err())
+	case <-ch:
+	}
+
+	job, err := q.Status(id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status == JobStatusFAILED {
+		return nil, fmt.Errorf("job %d: %s:%w", id, job.LastError, ErrJobFailed)
+	}
+	return job.Response, nil
+}
+
+// Run processes jobs with the configured number of workers until ctx is done,
+// and returns ctx.Err(). It stops early with the error when the log cannot be
+// written or compacted, since further progress would not be durable.
+// Jobs interrupted by cancellation go back to pending without using an attempt.
+func (q *JobQueue) Run(ctx context.Context) error {
+	q.mu.Lock()
+	q.err = nil
+	q.mu.Unlock()
+
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errs := make(chan error, q.opts.Workers)
+	var wg sync.WaitGroup
+	for i := 0; i < q.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := q.work(workCtx); err != nil {
+				errs <- err
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("failed to run queue %s:%w", q.path, err)
+	default:
+	}
+	return fmt.Errorf("failed to run queue %s:%w", q.path, ctx.Err())
+}
+
+// work claims and processes jobs until ctx is done or the log fails.
+func (q *JobQueue) work(ctx context.Context) error {
+	for ctx.Err() == nil {
+		job, wait, err := q.claim()
+		if err != nil {
+			return err
+		}
+		if job == nil {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+			case <-q.wake:
+			case <-timer.C:
+			}
+			timer.Stop()
+			continue
+		}
+		q.process(ctx, job)
+	}
+	return nil
+}
+
+// claim marks the next due job as running and returns a copy of it,
+// or how long to sleep when nothing is due.
+func (q *JobQueue) claim() (*Job, time.Duration, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.err != nil {
+		return nil, 0, q.err
+	}
+
+	now := time.Now()
+	wait := q.opts.PollInterval
+	for _, id := range q.sortedIDs() {
+		job := q.jobs[id]
+		if job.Status != JobStatusPENDING {
+			continue
+		}
+		if d := job.NextAttempt.Sub(now); d > 0 {
+			wait = min(wait, d)
+			continue
+		}
+		if q.f == nil {
+			return nil, wait, fmt.Errorf("queue %s is closed", q.path)
+		}
+		job.Status = JobStatusRUNNING
+		if err := q.persist(job); err != nil {
+			job.Status = JobStatusPENDING
+			q.setErr(err)
+			return nil, wait, err
+		}
+		c := *job
+		c.ImageIDs = append([]int(nil), job.ImageIDs...)
+		return &c, 0, nil
+	}
+	return nil, wait, nil
+}
+
+func (q *JobQueue) process(ctx context.Context, job *Job) {
+	for i := len(job.ImageIDs); i < len(job.Images); i++ {
+		var (
+			img Image
+			err error
+		)
+		if u := job.Images[i].URL; u != "" {
+			img, err = q.client.Images().UploadByURL(ctx, u)
+		} else {
+			img, err = q.client.Images().Upload(ctx, bytes.NewReader(job.Images[i].Data), job.Images[i].Filename)
+		}
+		if err != nil {
+			q.fail(ctx, job.ID, err)
+			return
+		}
+		job.ImageIDs = append(job.ImageIDs, img.ID)
+		q.update(job.ID, func(j *Job) { j.ImageIDs = append([]int(nil), job.ImageIDs...) })
+	}
+
+	rr := job.Request
+	rr.Images = job.ImageIDs
+	rec, err := q.client.Recognizer().Recognize(ctx, rr)
+	if err != nil {
+		q.fail(ctx, job.ID, err)
+		return
+	}
+
+	q.update(job.ID, func(j *Job) {
+		j.Status = JobStatusDONE
+		j.Attempts++
+		j.LastError = ""
+		j.Response = rec
+		images := make([]JobImage, len(j.Images))
+		for i, img := range j.Images {
+			images[i] = JobImage{Filename: img.Filename, URL: img.URL}
+		}
+		j.Images = images
+	})
+}
+
+// fail records a failed attempt and schedules a retry when the error is transient.
+func (q *JobQueue) fail(ctx context.Context, id int, err error) {
+	if ctx.Err() != nil {
+		q.update(id, func(j *Job) { j.Status = JobStatusPENDING })
+		return
+	}
+	q.update(id, func(j *Job) {
+		j.Attempts++
+		j.LastError = err.Error()
+		delay, retry := retryDelay(err, j.Attempts, q.opts)
+		if !retry || j.Attempts >= q.opts.MaxAttempts {
+			j.Status = JobStatusFAILED
+			return
+		}
+		j.Status = JobStatusPENDING
+		j.NextAttempt = time.Now().UTC().Add(delay)
+	})
+}
+
+// update applies fn to the job, persists it and releases waiters of finished jobs.
+func (q *JobQueue) update(id int, fn func(*Job)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job := q.jobs[id]
+	fn(job)
+	if q.f != nil {
+		// A failed write loses progress: the job is replayed from its last persisted state after a restart.
+		if err := q.persist(job); err != nil {
+			q.setErr(err)
+		}
+	}
+	if job.Finished() {
+		select {
+		case <-q.done[id]:
+		default:
+			close(q.done[id])
+		}
+	}
+}
+
+func (q *JobQueue) sortedIDs() []int {
+	ids := make([]int, 0, len(q.jobs))
+	for id := range q.jobs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// retryDelay classifies err. Network errors, 5xx and 429 are retried with
+// exponential backoff (or Retry-After when longer); other API errors are permanent.
+func retryDelay(err error, attempt int, opts JobQueueOptions) (time.Duration, bool) {
+	delay := opts.BaseBackoff
+	for i := 1; i < attempt && delay < opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, opts.MaxBackoff)
+
+	var errResp *httpclient.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Response == nil {
+		return delay, true
+	}
+	status := errResp.Response.StatusCode
+	if status == http.StatusTooManyRequests {
+		if s, err := strconv.Atoi(errResp.Response.Header.Get("Retry-After")); err == nil {
+			delay = max(delay, time.Duration(s)*time.Second)
+		}
+		return delay, true
+	}
+	return delay, status >= http.StatusInternalServerError
+}
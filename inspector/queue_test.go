@@ -0,0 +1,322 @@
+package inspector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpclient "github.com/germangorelkin/http-client"
+	"github.com/stretchr/testify/assert"
+)
+
+var testJobQueueOptions = &JobQueueOptions{
+	Workers:      2,
+	BaseBackoff:  time.Millisecond,
+	MaxBackoff:   5 * time.Millisecond,
+	PollInterval: 5 * time.Millisecond,
+}
+
+func newQueueServer(t *testing.T, recognize http.HandlerFunc) (*httptest.Server, *int32) {
+	var uploads int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+endpointUploads, func(w http.ResponseWriter, r *http.Request) {
+		id := atomic.AddInt32(&uploads, 1)
+		fmt.Fprintf(w, `{"id":%d}`, id)
+	})
+	mux.HandleFunc("/"+endpointUploadsByURL, func(w http.ResponseWriter, r *http.Request) {
+		id := atomic.AddInt32(&uploads, 1)
+		fmt.Fprintf(w, `{"id":%d}`, id)
+	})
+	mux.HandleFunc("/"+endpointRecognize, recognize)
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+	return ts, &uploads
+}
+
+func TestJobQueue_Retry(t *testing.T) {
+	var calls int32
+	ts, uploads := newQueueServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var rr RecognizeRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&rr))
+		assert.Equal(t, []int{1, 2}, rr.Images)
+		assert.Equal(t, "RU", rr.CountryCode)
+		fmt.Fprint(w, `{"id":77,"images":[1,2],"reports":{"FACING_COUNT":5}}`)
+	})
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: ""})
+	assert.NoError(t, err)
+
+	q, err := OpenJobQueue(client, filepath.Join(t.TempDir(), "queue.log"), testJobQueueOptions)
+	assert.NoError(t, err)
+	defer q.Close()
+
+	img, err := NewJobImage(strings.NewReader("jpeg"), "a.jpg")
+	assert.NoError(t, err)
+	id, err := q.Enqueue(RecognizeRequest{CountryCode: "RU"}, img, JobImage{URL: "https://example.com/b.jpg"})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx)
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer waitCancel()
+	rec, err := q.Wait(waitCtx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, 77, rec.ID)
+	assert.Equal(t, int32(2), atomic.LoadInt32(uploads)) // uploads are not repeated on retry
+
+	job, err := q.Status(id)
+	assert.NoError(t, err)
+	assert.Equal(t, JobStatusDONE, job.Status)
+	assert.Equal(t, 2, job.Attempts)
+	assert.Nil(t, job.Images[0].Data)
+}
+
+func TestJobQueue_PermanentError(t *testing.T) {
+	ts, _ := newQueueServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"detail":"invalid report type"}`)
+	})
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: ""})
+	assert.NoError(t, err)
+
+	q, err := OpenJobQueue(client, filepath.Join(t.TempDir(), "queue.log"), testJobQueueOptions)
+	assert.NoError(t, err)
+	defer q.Close()
+
+	id, err := q.Enqueue(RecognizeRequest{}, JobImage{URL: "https://example.com/a.jpg"})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx)
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer waitCancel()
+	_, err = q.Wait(waitCtx, id)
+	assert.True(t, errors.Is(err, ErrJobFailed))
+
+	job, _ := q.Status(id)
+	assert.Equal(t, JobStatusFAILED, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+	assert.Contains(t, job.LastError, "invalid report type")
+
+	_, err = q.Status(999)
+	assert.True(t, errors.Is(err, ErrJobNotFound))
+}
+
+func TestJobQueue_Restart(t *testing.T) {
+	ts, _ := newQueueServer(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":1,"reports":{"FACING_COUNT":5}}`)
+	})
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: ""})
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "queue.log")
+	q, err := OpenJobQueue(client, path, testJobQueueOptions)
+	assert.NoError(t, err)
+	first, err := q.Enqueue(RecognizeRequest{}, JobImage{Filename: "a.jpg", Data: []byte("jpeg")})
+	assert.NoError(t, err)
+	second, err := q.Enqueue(RecognizeRequest{}, JobImage{URL: "https://example.com/b.jpg"})
+	assert.NoError(t, err)
+	assert.NoError(t, q.Close())
+
+	// simulate a crash while writing the next record
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	assert.NoError(t, err)
+	_, err = f.WriteString(`{"id":3,"status":"PEN`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	q, err = OpenJobQueue(client, path, testJobQueueOptions)
+	assert.NoError(t, err)
+	defer q.Close()
+
+	jobs := q.Jobs()
+	assert.Len(t, jobs, 2)
+	assert.Equal(t, JobStatusPENDING, jobs[0].Status)
+	assert.Equal(t, []byte("jpeg"), jobs[0].Images[0].Data)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx)
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer waitCancel()
+	for _, id := range []int{first, second} {
+		_, err := q.Wait(waitCtx, id)
+		assert.NoError(t, err)
+	}
+
+	next, err := q.Enqueue(RecognizeRequest{}, JobImage{URL: "https://example.com/c.jpg"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, next)
+}
+
+func TestJobQueue_RunErrors(t *testing.T) {
+	ts, _ := newQueueServer(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":77}`)
+	})
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: ""})
+	assert.NoError(t, err)
+
+	t.Run("context done", func(t *testing.T) {
+		q, err := OpenJobQueue(client, filepath.Join(t.TempDir(), "queue.log"), testJobQueueOptions)
+		assert.NoError(t, err)
+		defer q.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, q.Run(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("log write fails", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queue.log")
+		q, err := OpenJobQueue(client, path, testJobQueueOptions)
+		assert.NoError(t, err)
+		defer q.Close()
+		id, err := q.Enqueue(RecognizeRequest{}, JobImage{URL: "https://example.com/a.jpg"})
+		assert.NoError(t, err)
+
+		ro, err := os.Open(path)
+		assert.NoError(t, err)
+		q.mu.Lock()
+		q.f.Close()
+		q.f = ro
+		q.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err = q.Run(ctx)
+		if assert.Error(t, err) {
+			assert.NotErrorIs(t, err, context.DeadlineExceeded)
+			assert.Contains(t, err.Error(), fmt.Sprintf("failed to write job %d", id))
+		}
+		job, err := q.Status(id)
+		assert.NoError(t, err)
+		assert.Equal(t, JobStatusPENDING, job.Status)
+	})
+
+	t.Run("compaction fails", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queue.log")
+		opts := *testJobQueueOptions
+		opts.CompactEvery = 2
+		q, err := OpenJobQueue(client, path, &opts)
+		assert.NoError(t, err)
+		defer q.Close()
+		_, err = q.Enqueue(RecognizeRequest{}, JobImage{URL: "https://example.com/a.jpg"})
+		assert.NoError(t, err)
+
+		// the open log keeps accepting records, but the compacted file cannot replace it
+		assert.NoError(t, os.Remove(path))
+		assert.NoError(t, os.MkdirAll(filepath.Join(path, "dir"), 0o700))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err = q.Run(ctx)
+		if assert.Error(t, err) {
+			assert.NotErrorIs(t, err, context.DeadlineExceeded)
+			assert.Contains(t, err.Error(), "failed to compact queue")
+		}
+	})
+}
+
+func TestJobQueue_Log(t *testing.T) {
+	client, err := NewClient(ClintConf{Instance: "http://localhost", APIKey: ""})
+	assert.NoError(t, err)
+
+	readLog := func(path string) []string {
+		b, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		return strings.Split(strings.TrimSpace(string(b)), "\n")
+	}
+	data := []byte(strings.Repeat("jpeg", 1024))
+
+	t.Run("image bytes are written once", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queue.log")
+		q, err := OpenJobQueue(client, path, testJobQueueOptions)
+		assert.NoError(t, err)
+		id, err := q.Enqueue(RecognizeRequest{}, JobImage{Filename: "a.jpg", Data: data})
+		assert.NoError(t, err)
+		for i := 1; i <= 3; i++ {
+			q.update(id, func(j *Job) { j.Attempts = i; j.ImageIDs = []int{i} })
+		}
+		assert.NoError(t, q.Close())
+
+		lines := readLog(path)
+		if assert.Len(t, lines, 4) {
+			assert.Greater(t, len(lines[0]), len(data))
+			for _, line := range lines[1:] {
+				assert.Less(t, len(line), len(data))
+			}
+		}
+
+		q, err = OpenJobQueue(client, path, testJobQueueOptions)
+		assert.NoError(t, err)
+		defer q.Close()
+		job, err := q.Status(id)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, job.Attempts)
+		assert.Equal(t, data, job.Images[0].Data)
+	})
+
+	t.Run("compacts while open", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "queue.log")
+		opts := *testJobQueueOptions
+		opts.CompactEvery = 4
+		q, err := OpenJobQueue(client, path, &opts)
+		assert.NoError(t, err)
+		defer q.Close()
+		id, err := q.Enqueue(RecognizeRequest{}, JobImage{Filename: "a.jpg", Data: data})
+		assert.NoError(t, err)
+		for i := 1; i <= 10; i++ {
+			q.update(id, func(j *Job) { j.Attempts = i })
+		}
+
+		assert.Len(t, readLog(path), 4) // last compacted at the 7th update, then 3 more records
+		job, err := q.Status(id)
+		assert.NoError(t, err)
+		assert.Equal(t, data, job.Images[0].Data)
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	opts := JobQueueOptions{BaseBackoff: time.Second, MaxBackoff: 10 * time.Second}
+	apiErr := func(status int, header http.Header) error {
+		return fmt.Errorf("wrapped:%w", &httpclient.ErrorResponse{Response: &http.Response{StatusCode: status, Header: header}})
+	}
+
+	delay, retry := retryDelay(errors.New("connection refused"), 1, opts)
+	assert.True(t, retry)
+	assert.Equal(t, time.Second, delay)
+
+	delay, retry = retryDelay(apiErr(http.StatusBadGateway, nil), 3, opts)
+	assert.True(t, retry)
+	assert.Equal(t, 4*time.Second, delay)
+
+	delay, _ = retryDelay(apiErr(http.StatusBadGateway, nil), 10, opts)
+	assert.Equal(t, 10*time.Second, delay)
+
+	delay, retry = retryDelay(apiErr(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"30"}}), 1, opts)
+	assert.True(t, retry)
+	assert.Equal(t, 30*time.Second, delay)
+
+	_, retry = retryDelay(apiErr(http.StatusNotFound, nil), 1, opts)
+	assert.False(t, retry)
+}
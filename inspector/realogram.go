@@ -0,0 +1,209 @@
+package inspector
+
+import (
+	"sort"
+)
+
+// Bounds returns the box edges of the annotation in pixels.
+// Annotation X and Y are the center of the box: with this convention product
+// bottoms rest on the shelf lines of REALOGRAM reports.
+func (a ReportRealogramAnnotations) Bounds() (left, top, right, bottom int) {
+	left = a.X - a.W/2
+	top = a.Y - a.H/2
+	return left, top, left + a.W, top + a.H
+}
+
+// YAt returns the y coordinate of the shelf line at x, interpolating sloped lines.
+func (s ReportRealogramShelfAnnotations) YAt(x int) float64 {
+	if s.X2 == s.X1 {
+		return float64(s.Y1+s.Y2) / 2
+	}
+	return float64(s.Y1) + float64(s.Y2-s.Y1)*float64(x-s.X1)/float64(s.X2-s.X1)
+}
+
+// RealogramOptions configures AnalyzeRealogram.
+type RealogramOptions struct {
+	StraddleTolerance float64 // default: DefaultStraddleTolerance
+	IncludeDuplicates bool    // count duplicate annotations in facings and occupancy
+}
+
+// ShelfProduct is an annotation placed on a shelf.
+type ShelfProduct struct {
+	ReportRealogramAnnotations
+	Left, Top, Right, Bottom int  // box edges, see ReportRealogramAnnotations.Bounds
+	Straddling               bool // a shelf line crosses the box well inside its top and bottom
+	Stacked                  bool // rests on another product of the same shelf, not a facing
+}
+
+// Shelf is one shelf of a realogram with its products ordered left to right.
+type Shelf struct {
+	Index      int                             // 0 is the top shelf
+	Line       ReportRealogramShelfAnnotations // the shelf line products stand on
+	Products   []ShelfProduct                  // left to right, including duplicates
+	Facings    map[int]int                     // facings by SkuId (front row, without stacked products)
+	OccupiedPx int                             // width covered by products, overlaps counted once
+	LengthPx   int                             // width of the shelf line
+	Duplicates int                             // duplicate annotations on the shelf
+}
+
+// Occupancy returns the share of the shelf length covered by products.
+func (s *Shelf) Occupancy() float64 {
+	if s.LengthPx <= 0 {
+		return 0
+	}
+	return float64(s.OccupiedPx) / float64(s.LengthPx)
+}
+
+// FacingCount returns the total facings of the shelf.
+func (s *Shelf) FacingCount() int {
+	n := 0
+	for _, c := range s.Facings {
+		n += c
+	}
+	return n
+}
+
+// RealogramLayout is the shelf layout of one realogram image.
+type RealogramLayout struct {
+	Image      int
+	Shelves    []Shelf        // top to bottom
+	Unassigned []ShelfProduct // products below the lowest shelf line or on an image without shelf lines
+}
+
+// AnalyzeRealograms runs AnalyzeRealogram for every image of a report.
+func AnalyzeRealograms(realograms []ReportRealogramJson, opts *RealogramOptions) []RealogramLayout {
+	layouts := make([]RealogramLayout, 0, len(realograms))
+	for _, r := range realograms {
+		layouts = append(layouts, AnalyzeRealogram(r, opts))
+	}
+	return layouts
+}
+
+// AnalyzeRealogram assigns every annotation to a shelf line and computes shelf analytics.
+//
+// A product belongs to the first shelf line below its center, so products stacked
+// on top of others belong to the same shelf as the products they rest on. A product
+// whose box is crossed by a shelf line with more than StraddleTolerance of its
+// height on both sides is flagged Straddling and still assigned by its center.
+// Duplicate annotations are kept in Products but are not counted unless IncludeDuplicates is set.
+func AnalyzeRealogram(r ReportRealogramJson, opts *RealogramOptions) RealogramLayout {
+	o := RealogramOptions{StraddleTolerance: DefaultStraddleTolerance}
+	if opts != nil {
+		o = *opts
+		if o.StraddleTolerance <= 0 {
+			o.StraddleTolerance = DefaultStraddleTolerance
+		}
+	}
+
+	lines := append([]ReportRealogramShelfAnnotations(nil), r.ShelfAnnotations...)
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Y1+lines[i].Y2 < lines[j].Y1+lines[j].Y2
+	})
+
+	layout := RealogramLayout{Image: r.Image, Shelves: make([]Shelf, len(lines))}
+	for i, line := range lines {
+		length := line.X2 - line.X1
+		if length < 0 {
+			length = -length
+		}
+		layout.Shelves[i] = Shelf{Index: i, Line: line, Facings: make(map[int]int), LengthPx: length}
+	}
+
+	for _, a := range r.Annotations {
+		p := ShelfProduct{ReportRealogramAnnotations: a}
+		p.Left, p.Top, p.Right, p.Bottom = a.Bounds()
+
+		shelf := -1
+		for i, line := range lines {
+			y := line.YAt(a.X)
+			margin := o.StraddleTolerance * float64(a.H)
+			if float64(p.Top)+margin < y && y < float64(p.Bottom)-margin {
+				p.Straddling = true
+			}
+			if shelf < 0 && y >= float64(a.Y) {
+				shelf = i
+			}
+		}
+		if shelf < 0 {
+			layout.Unassigned = append(layout.Unassigned, p)
+			continue
+		}
+		layout.Shelves[shelf].Products = append(layout.Shelves[shelf].Products, p)
+	}
+
+	for i := range layout.Shelves {
+		analyzeShelf(&layout.Shelves[i], o)
+	}
+	sortShelfProducts(layout.Unassigned)
+	return layout
+}
+
+func analyzeShelf(s *Shelf, o RealogramOptions) {
+	sortShelfProducts(s.Products)
+
+	counted := func(p ShelfProduct) bool { return o.IncludeDuplicates || !p.Duplicate }
+	for i := range s.Products {
+		p := &s.Products[i]
+		if p.Duplicate {
+			s.Duplicates++
+		}
+		if !counted(*p) {
+			continue
+		}
+		for _, q := range s.Products {
+			if q.Top > p.Top && counted(q) && restsOn(*p, q, o.StraddleTolerance) {
+				p.Stacked = true
+				break
+			}
+		}
+	}
+
+	var intervals [][2]int
+	for _, p := range s.Products {
+		if !counted(p) {
+			continue
+		}
+		intervals = append(intervals, [2]int{p.Left, p.Right})
+		if !p.Stacked {
+			s.Facings[p.SkuId]++
+		}
+	}
+	s.OccupiedPx = unionLength(intervals)
+}
+
+// restsOn reports whether p sits on top of q: they overlap horizontally by at least
+// half of the narrower box and p's bottom is near q's top.
+func restsOn(p, q ShelfProduct, tolerance float64) bool {
+	overlap := min(p.Right, q.Right) - max(p.Left, q.Left)
+	if overlap*2 < min(p.W, q.W) {
+		return false
+	}
+	return float64(p.Bottom) <= float64(q.Top)+tolerance*float64(q.H)
+}
+
+func sortShelfProducts(products []ShelfProduct) {
+	sort.SliceStable(products, func(i, j int) bool {
+		if products[i].Left != products[j].Left {
+			return products[i].Left < products[j].Left
+		}
+		return products[i].Top < products[j].Top
+	})
+}
+
+// unionLength returns the total length covered by the intervals.
+func unionLength(intervals [][2]int) int {
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i][0] < intervals[j][0] })
+	total, end := 0, 0
+	for i, iv := range intervals {
+		if i == 0 || iv[0] > end {
+			total += iv[1] - iv[0]
+			end = iv[1]
+			continue
+		}
+		if iv[1] > end {
+			total += iv[1] - end
+			end = iv[1]
+		}
+	}
+	return total
+}
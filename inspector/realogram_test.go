@@ -0,0 +1,156 @@
+package inspector
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func loadRealogramReport(t *testing.T) []ReportRealogramJson {
+	t.Helper()
+	b, err := os.ReadFile("testdata/REALOGRAM_1_5.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(b, &report); err != nil {
+		t.Fatal(err)
+	}
+	var srv ReportService
+	realograms, err := srv.ToRealogram(report.Json)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return realograms
+}
+
+func TestAnalyzeRealogram(t *testing.T) {
+	layouts := AnalyzeRealograms(loadRealogramReport(t), nil)
+	assert.Len(t, layouts, 2)
+
+	t.Run("assigns every product to a shelf", func(t *testing.T) {
+		l := layouts[0]
+		assert.Equal(t, 55801587, l.Image)
+		assert.Len(t, l.Shelves, 3)
+		assert.Empty(t, l.Unassigned)
+
+		var counts []int
+		for i, s := range l.Shelves {
+			assert.Equal(t, i, s.Index)
+			counts = append(counts, len(s.Products))
+			for _, p := range s.Products {
+				assert.False(t, p.Straddling)
+				if p.Stacked {
+					continue
+				}
+				assert.InDelta(t, s.Line.YAt(p.X), float64(p.Bottom), 50, "product %s rests on its shelf", p.Name)
+			}
+		}
+		assert.Equal(t, []int{15, 8, 6}, counts)
+		assert.True(t, l.Shelves[0].Line.Y1 < l.Shelves[1].Line.Y1)
+	})
+
+	t.Run("orders products left to right", func(t *testing.T) {
+		for _, l := range layouts {
+			for _, s := range l.Shelves {
+				for i := 1; i < len(s.Products); i++ {
+					assert.LessOrEqual(t, s.Products[i-1].Left, s.Products[i].Left)
+				}
+			}
+		}
+		got := []int{}
+		for _, p := range layouts[0].Shelves[2].Products {
+			got = append(got, p.SkuId)
+		}
+		assert.Equal(t, []int{48809, 65853, 26536, 53733, 53733, 53733}, got)
+	})
+
+	t.Run("counts front row facings", func(t *testing.T) {
+		top := layouts[0].Shelves[0]
+		assert.Equal(t, map[int]int{53736: 3, 61108: 2, 61109: 2}, top.Facings)
+		assert.Equal(t, 7, top.FacingCount())
+		stacked := 0
+		for _, p := range top.Products {
+			if p.Stacked {
+				stacked++
+			}
+		}
+		assert.Equal(t, 8, stacked)
+
+		assert.Equal(t, map[int]int{2176: 10, 47328: 2}, layouts[1].Shelves[0].Facings)
+	})
+
+	t.Run("linear occupancy", func(t *testing.T) {
+		s := layouts[0].Shelves[2]
+		assert.Equal(t, 1132, s.LengthPx)
+		assert.Equal(t, 883, s.OccupiedPx)
+		assert.InDelta(t, 0.78, s.Occupancy(), 0.001)
+	})
+}
+
+func TestAnalyzeRealogram_Duplicates(t *testing.T) {
+	r := ReportRealogramJson{
+		Image: 1,
+		Annotations: []ReportRealogramAnnotations{
+			{X: 150, Y: 50, W: 100, H: 100, SkuId: 1},
+			{X: 250, Y: 50, W: 100, H: 100, SkuId: 2},
+			{X: 300, Y: 50, W: 100, H: 100, SkuId: 2, Duplicate: true},
+		},
+		ShelfAnnotations: []ReportRealogramShelfAnnotations{{X1: 0, Y1: 100, X2: 1000, Y2: 100}},
+	}
+
+	l := AnalyzeRealogram(r, nil)
+	s := l.Shelves[0]
+	assert.Len(t, s.Products, 3)
+	assert.Equal(t, 1, s.Duplicates)
+	assert.Equal(t, map[int]int{1: 1, 2: 1}, s.Facings)
+	assert.Equal(t, 200, s.OccupiedPx)
+
+	l = AnalyzeRealogram(r, &RealogramOptions{IncludeDuplicates: true})
+	s = l.Shelves[0]
+	assert.Equal(t, map[int]int{1: 1, 2: 2}, s.Facings)
+	assert.Equal(t, 250, s.OccupiedPx)
+}
+
+func TestAnalyzeRealogram_Straddling(t *testing.T) {
+	r := ReportRealogramJson{
+		Annotations: []ReportRealogramAnnotations{
+			{X: 100, Y: 150, W: 100, H: 100, SkuId: 1}, // spans 100..200 across the line at 180
+			{X: 300, Y: 230, W: 100, H: 100, SkuId: 2}, // rests on the line below
+			{X: 500, Y: 450, W: 100, H: 100, SkuId: 3}, // below the lowest line
+		},
+		ShelfAnnotations: []ReportRealogramShelfAnnotations{
+			{X1: 0, Y1: 280, X2: 1000, Y2: 280},
+			{X1: 0, Y1: 170, X2: 1000, Y2: 190},
+		},
+	}
+
+	l := AnalyzeRealogram(r, nil)
+	assert.Len(t, l.Shelves, 2)
+	assert.Equal(t, 170, l.Shelves[0].Line.Y1, "shelves are ordered top to bottom")
+
+	top := l.Shelves[0]
+	assert.Len(t, top.Products, 1)
+	assert.True(t, top.Products[0].Straddling)
+
+	bottom := l.Shelves[1]
+	assert.Len(t, bottom.Products, 1)
+	assert.False(t, bottom.Products[0].Straddling)
+
+	assert.Len(t, l.Unassigned, 1)
+	assert.Equal(t, 3, l.Unassigned[0].SkuId)
+
+	l = AnalyzeRealogram(r, &RealogramOptions{StraddleTolerance: 0.4})
+	assert.False(t, l.Shelves[0].Products[0].Straddling)
+}
+
+func TestReportRealogramShelfAnnotations_YAt(t *testing.T) {
+	line := ReportRealogramShelfAnnotations{X1: 0, Y1: 100, X2: 100, Y2: 200}
+	assert.Equal(t, 150.0, line.YAt(50))
+	assert.Equal(t, 100.0, line.YAt(0))
+
+	vertical := ReportRealogramShelfAnnotations{X1: 10, Y1: 100, X2: 10, Y2: 200}
+	assert.Equal(t, 150.0, vertical.YAt(10))
+}
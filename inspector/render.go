@@ -0,0 +1,306 @@
+package inspector
+
+import (
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"sort"
+	"strings"
+)
+
+// RenderOptions configures RenderRealogramPNG and RenderRealogramSVG.
+type RenderOptions struct {
+	ColorKey     func(a ReportRealogramAnnotations) string // products with the same key share a color, e.g. the brand of a.SkuId; default: SKU ID
+	Label        func(a ReportRealogramAnnotations) string // label text; default: a.Name
+	Highlight    []int                                     // SKU IDs drawn emphasized; other products are grayed out
+	HideLabels   bool
+	HideShelves  bool
+	NoBackground bool // SVG: do not reference Image.URL as background
+	LineWidth    int  // default: DefaultRenderLineWidth
+	FontScale    int  // PNG label font pixel size; default: DefaultRenderFontScale
+}
+
+// renderPalette holds distinguishable box colors picked by ColorKey hash.
+var renderPalette = []color.RGBA{
+	{230, 25, 75, 255}, {60, 180, 75, 255}, {255, 225, 25, 255}, {0, 130, 200, 255},
+	{245, 130, 48, 255}, {145, 30, 180, 255}, {70, 240, 240, 255}, {240, 50, 230, 255},
+	{210, 245, 60, 255}, {0, 128, 128, 255}, {170, 110, 40, 255}, {128, 0, 0, 255},
+}
+
+var (
+	renderDimmed = color.RGBA{160, 160, 160, 255}
+	renderShelf  = color.RGBA{255, 255, 255, 255}
+)
+
+// renderStyle is the look of one product box.
+type renderStyle struct {
+	color     color.RGBA
+	width     int
+	dashed    bool // duplicate annotation
+	highlight bool
+}
+
+type renderer struct {
+	RenderOptions
+	highlight map[int]bool
+}
+
+func newRenderer(opts *RenderOptions) *renderer {
+	r := &renderer{}
+	if opts != nil {
+		r.RenderOptions = *opts
+	}
+	if r.ColorKey == nil {
+		r.ColorKey = func(a ReportRealogramAnnotations) string { return fmt.Sprintf("sku:%d", a.SkuId) }
+	}
+	if r.Label == nil {
+		r.Label = func(a ReportRealogramAnnotations) string { return a.Name }
+	}
+	if r.LineWidth <= 0 {
+		r.LineWidth = DefaultRenderLineWidth
+	}
+	if r.FontScale <= 0 {
+		r.FontScale = DefaultRenderFontScale
+	}
+	if len(r.Highlight) > 0 {
+		r.highlight = make(map[int]bool, len(r.Highlight))
+		for _, id := range r.Highlight {
+			r.highlight[id] = true
+		}
+	}
+	return r
+}
+
+func (r *renderer) style(a ReportRealogramAnnotations) renderStyle {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(r.ColorKey(a)))
+	s := renderStyle{color: renderPalette[h.Sum32()%uint32(len(renderPalette))], width: r.LineWidth, dashed: a.Duplicate}
+	if r.highlight != nil {
+		if r.highlight[a.SkuId] {
+			s.highlight = true
+			s.width *= 2
+		} else {
+			s.color = renderDimmed
+		}
+	}
+	return s
+}
+
+// ordered returns the annotations with highlighted products last, so they are drawn on top.
+func (r *renderer) ordered(annotations []ReportRealogramAnnotations) []ReportRealogramAnnotations {
+	out := append([]ReportRealogramAnnotations(nil), annotations...)
+	sort.SliceStable(out, func(i, j int) bool {
+		return !r.highlight[out[i].SkuId] && r.highlight[out[j].SkuId]
+	})
+	return out
+}
+
+// RenderRealogramPNG draws the realogram over base and writes the result as PNG:
+// shelf lines, product boxes colored by ColorKey, and labels. Duplicate annotations
+// are drawn dashed. Labels use a small built-in font covering ASCII letters, digits
+// and common punctuation; other characters are drawn as '?', see RenderRealogramSVG
+// for full text.
+func RenderRealogramPNG(w io.Writer, base image.Image, realogram ReportRealogramJson, opts *RenderOptions) error {
+	r := newRenderer(opts)
+	dst := image.NewRGBA(base.Bounds())
+	draw.Draw(dst, dst.Bounds(), base, base.Bounds().Min, draw.Src)
+	origin := base.Bounds().Min
+
+	if !r.HideShelves {
+		for _, s := range realogram.ShelfAnnotations {
+			drawLine(dst, origin.Add(image.Pt(s.X1, s.Y1)), origin.Add(image.Pt(s.X2, s.Y2)), r.LineWidth, renderShelf)
+		}
+	}
+
+	for _, a := range r.ordered(realogram.Annotations) {
+		st := r.style(a)
+		left, top, right, bottom := a.Bounds()
+		box := image.Rect(left, top, right, bottom).Add(origin)
+		strokeRect(dst, box, st.width, st.color, st.dashed)
+
+		if r.HideLabels {
+			continue
+		}
+		label := fitText(r.Label(a), box.Dx()-2*st.width, r.FontScale)
+		if label == "" {
+			continue
+		}
+		pad := r.FontScale
+		bg := image.Rect(box.Min.X, box.Min.Y, box.Min.X+textWidth(label, r.FontScale)+pad, box.Min.Y+glyphHeight*r.FontScale+pad)
+		fillRect(dst, bg, st.color)
+		drawText(dst, bg.Min.X+pad, bg.Min.Y+pad, label, r.FontScale, textColor(st.color))
+	}
+
+	if err := png.Encode(w, dst); err != nil {
+		return fmt.Errorf("failed to encode png:%w", err)
+	}
+	return nil
+}
+
+// RenderRealogramSVG writes an SVG overlay of the realogram sized to img. Unless
+// NoBackground is set, img.URL is referenced as the background so the file can be
+// viewed on its own. Each product is a <g> with data-sku-id, a <title> and its label.
+func RenderRealogramSVG(w io.Writer, img Image, realogram ReportRealogramJson, opts *RenderOptions) error {
+	r := newRenderer(opts)
+	width, height := img.Width, img.Height
+	if width <= 0 || height <= 0 {
+		width, height = realogramExtent(realogram)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	if img.URL != "" && !r.NoBackground {
+		fmt.Fprintf(&b, `<image href="%s" x="0" y="0" width="%d" height="%d"/>`+"\n", svgEscape(img.URL), width, height)
+	}
+
+	if !r.HideShelves {
+		b.WriteString(`<g class="shelves">` + "\n")
+		for _, s := range realogram.ShelfAnnotations {
+			fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="%d"/>`+"\n",
+				s.X1, s.Y1, s.X2, s.Y2, svgColor(renderShelf), r.LineWidth)
+		}
+		b.WriteString("</g>\n")
+	}
+
+	b.WriteString(`<g class="products" font-family="sans-serif">` + "\n")
+	for _, a := range r.ordered(realogram.Annotations) {
+		st := r.style(a)
+		left, top, _, _ := a.Bounds()
+		class := "product"
+		if st.highlight {
+			class += " highlight"
+		}
+		if a.Duplicate {
+			class += " duplicate"
+		}
+		fmt.Fprintf(&b, `<g class="%s" data-sku-id="%d">`, class, a.SkuId)
+		fmt.Fprintf(&b, `<title>%s (SKU %d)</title>`, svgEscape(a.Name), a.SkuId)
+		dash := ""
+		if st.dashed {
+			dash = fmt.Sprintf(` stroke-dasharray="%d %d"`, 4*st.width, 2*st.width)
+		}
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="%s" stroke-width="%d"%s/>`,
+			left, top, a.W, a.H, svgColor(st.color), st.width, dash)
+		if label := r.Label(a); !r.HideLabels && label != "" {
+			size := 4 * r.FontScale
+			fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="%d" fill="%s" stroke="%s" stroke-width="%d" paint-order="stroke">%s</text>`,
+				left+st.width, top+st.width+size, size, svgColor(textColor(st.color)), svgColor(st.color), r.FontScale, svgEscape(label))
+		}
+		b.WriteString("</g>\n")
+	}
+	b.WriteString("</g>\n</svg>\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write svg:%w", err)
+	}
+	return nil
+}
+
+// realogramExtent returns the smallest canvas containing every annotation and shelf line.
+func realogramExtent(r ReportRealogramJson) (width, height int) {
+	for _, a := range r.Annotations {
+		_, _, right, bottom := a.Bounds()
+		width, height = max(width, right), max(height, bottom)
+	}
+	for _, s := range r.ShelfAnnotations {
+		width, height = max(width, s.X1, s.X2), max(height, s.Y1, s.Y2)
+	}
+	return width, height
+}
+
+// fitText shortens s to fit width pixels of PNG labels.
+func fitText(s string, width, scale int) string {
+	runes := []rune(strings.TrimSpace(s))
+	n := max(width/(glyphAdvance*scale), 0)
+	if len(runes) > n {
+		runes = runes[:n]
+	}
+	return string(runes)
+}
+
+// textColor returns black or white, whichever reads better on bg.
+func textColor(bg color.RGBA) color.RGBA {
+	if 299*int(bg.R)+587*int(bg.G)+114*int(bg.B) > 150*1000 {
+		return color.RGBA{0, 0, 0, 255}
+	}
+	return color.RGBA{255, 255, 255, 255}
+}
+
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+var svgReplacer = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&#39;")
+
+func svgEscape(s string) string {
+	return svgReplacer.Replace(s)
+}
+
+func fillRect(dst *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(dst, r.Intersect(dst.Bounds()), image.NewUniform(c), image.Point{}, draw.Over)
+}
+
+// strokeRect draws the outline of r inside its bounds, dashed if requested.
+func strokeRect(dst *image.RGBA, r image.Rectangle, width int, c color.Color, dashed bool) {
+	edges := []image.Rectangle{
+		image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+width),
+		image.Rect(r.Min.X, r.Max.Y-width, r.Max.X, r.Max.Y),
+		image.Rect(r.Min.X, r.Min.Y, r.Min.X+width, r.Max.Y),
+		image.Rect(r.Max.X-width, r.Min.Y, r.Max.X, r.Max.Y),
+	}
+	for i, e := range edges {
+		if !dashed {
+			fillRect(dst, e, c)
+			continue
+		}
+		dash, gap := 4*width, 2*width
+		if i < 2 {
+			for x := e.Min.X; x < e.Max.X; x += dash + gap {
+				fillRect(dst, image.Rect(x, e.Min.Y, min(x+dash, e.Max.X), e.Max.Y), c)
+			}
+			continue
+		}
+		for y := e.Min.Y; y < e.Max.Y; y += dash + gap {
+			fillRect(dst, image.Rect(e.Min.X, y, e.Max.X, min(y+dash, e.Max.Y)), c)
+		}
+	}
+}
+
+// drawLine draws a line of the given width from p to q.
+func drawLine(dst *image.RGBA, p, q image.Point, width int, c color.Color) {
+	dx, dy := abs(q.X-p.X), -abs(q.Y-p.Y)
+	sx, sy := 1, 1
+	if p.X > q.X {
+		sx = -1
+	}
+	if p.Y > q.Y {
+		sy = -1
+	}
+	half := width / 2
+	for e := dx + dy; ; {
+		fillRect(dst, image.Rect(p.X-half, p.Y-half, p.X-half+width, p.Y-half+width), c)
+		if p == q {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			p.X += sx
+		}
+		if e2 <= dx {
+			e += dx
+			p.Y += sy
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,144 @@
+package inspector
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func renderTestRealogram() ReportRealogramJson {
+	return ReportRealogramJson{
+		Image: 1,
+		Annotations: []ReportRealogramAnnotations{
+			{X: 50, Y: 50, W: 60, H: 60, SkuId: 1, Name: "Losk <Gel>"},
+			{X: 150, Y: 50, W: 60, H: 60, SkuId: 2, Name: "Persil"},
+			{X: 150, Y: 150, W: 60, H: 60, SkuId: 2, Name: "Persil", Duplicate: true},
+		},
+		ShelfAnnotations: []ReportRealogramShelfAnnotations{{X1: 0, Y1: 190, X2: 200, Y2: 190}},
+	}
+}
+
+func decodePNG(t *testing.T, b []byte) image.Image {
+	t.Helper()
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func TestRenderRealogramPNG(t *testing.T) {
+	base := image.NewRGBA(image.Rect(0, 0, 200, 200))
+	black := color.RGBA{0, 0, 0, 255}
+	fillRect(base, base.Bounds(), black)
+	r := newRenderer(nil)
+
+	t.Run("boxes, shelves and labels", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, RenderRealogramPNG(&buf, base, renderTestRealogram(), nil))
+		out := decodePNG(t, buf.Bytes())
+
+		assert.Equal(t, base.Bounds(), out.Bounds())
+		a := renderTestRealogram().Annotations[1]
+		assert.Equal(t, r.style(a).color, color.RGBAModel.Convert(out.At(178, 60)), "right edge of the box")
+		assert.Equal(t, black, color.RGBAModel.Convert(out.At(150, 60)), "inside of the box")
+		assert.Equal(t, renderShelf, color.RGBAModel.Convert(out.At(100, 190)), "shelf line")
+		assert.Equal(t, r.style(a).color, color.RGBAModel.Convert(out.At(121, 21)), "label background")
+		assert.Equal(t, base.At(0, 0), black, "base is not modified")
+	})
+
+	t.Run("duplicates are dashed", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, RenderRealogramPNG(&buf, base, renderTestRealogram(), &RenderOptions{HideLabels: true}))
+		out := decodePNG(t, buf.Bytes())
+		// top edge of the duplicate runs from x=120: dash of 12px, gap of 6px
+		assert.NotEqual(t, black, color.RGBAModel.Convert(out.At(125, 121)))
+		assert.Equal(t, black, color.RGBAModel.Convert(out.At(135, 121)))
+	})
+
+	t.Run("highlight grays out other SKUs", func(t *testing.T) {
+		var buf bytes.Buffer
+		opts := &RenderOptions{Highlight: []int{1}, HideLabels: true, HideShelves: true}
+		assert.NoError(t, RenderRealogramPNG(&buf, base, renderTestRealogram(), opts))
+		out := decodePNG(t, buf.Bytes())
+
+		assert.Equal(t, renderDimmed, color.RGBAModel.Convert(out.At(178, 60)))
+		assert.Equal(t, r.style(renderTestRealogram().Annotations[0]).color, color.RGBAModel.Convert(out.At(25, 50)), "highlighted box is twice as wide")
+		assert.Equal(t, black, color.RGBAModel.Convert(out.At(100, 190)), "shelves hidden")
+	})
+
+	t.Run("color key groups products", func(t *testing.T) {
+		opts := &RenderOptions{ColorKey: func(ReportRealogramAnnotations) string { return "brand" }}
+		rr := newRenderer(opts)
+		ann := renderTestRealogram().Annotations
+		assert.Equal(t, rr.style(ann[0]).color, rr.style(ann[1]).color)
+	})
+}
+
+func TestRenderRealogramSVG(t *testing.T) {
+	t.Run("overlay", func(t *testing.T) {
+		var buf bytes.Buffer
+		img := Image{ID: 1, URL: "https://example.com/a.jpg?x=1&y=2", Width: 200, Height: 200}
+		assert.NoError(t, RenderRealogramSVG(&buf, img, renderTestRealogram(), &RenderOptions{Highlight: []int{2}}))
+		svg := buf.String()
+
+		assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="200" height="200"`))
+		assert.Contains(t, svg, `<image href="https://example.com/a.jpg?x=1&amp;y=2"`)
+		assert.Contains(t, svg, `<line x1="0" y1="190" x2="200" y2="190"`)
+		assert.Equal(t, 3, strings.Count(svg, "<rect "))
+		assert.Contains(t, svg, `<title>Losk &lt;Gel&gt; (SKU 1)</title>`)
+		assert.Contains(t, svg, `<rect x="20" y="20" width="60" height="60" fill="none" stroke="#a0a0a0"`)
+		assert.Contains(t, svg, `class="product highlight duplicate" data-sku-id="2"`)
+		assert.Contains(t, svg, `stroke-dasharray=`)
+		assert.True(t, strings.Index(svg, `data-sku-id="1"`) < strings.Index(svg, `data-sku-id="2"`), "highlighted products are drawn last")
+	})
+
+	t.Run("size from annotations without background", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, RenderRealogramSVG(&buf, Image{URL: "https://example.com/a.jpg"}, renderTestRealogram(), &RenderOptions{NoBackground: true, HideLabels: true}))
+		svg := buf.String()
+		assert.Contains(t, svg, `width="200" height="190"`)
+		assert.NotContains(t, svg, "<image")
+		assert.NotContains(t, svg, "<text")
+	})
+
+	t.Run("report testdata", func(t *testing.T) {
+		realograms := loadRealogramReport(t)
+		var buf bytes.Buffer
+		assert.NoError(t, RenderRealogramSVG(&buf, Image{ID: realograms[0].Image}, realograms[0], nil))
+		assert.Equal(t, len(realograms[0].Annotations), strings.Count(buf.String(), "<rect "))
+	})
+}
+
+func TestLoadImageFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shelf.png")
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 3, 2))))
+	assert.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+
+	img, err := LoadImageFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 3, 2), img.Bounds())
+
+	_, err = LoadImageFile(filepath.Join(t.TempDir(), "missing.png"))
+	assert.Error(t, err)
+}
+
+func TestDrawText(t *testing.T) {
+	dst := image.NewRGBA(image.Rect(0, 0, 20, 10))
+	white := color.RGBA{255, 255, 255, 255}
+	x := drawText(dst, 0, 0, "1я", 1, white)
+	assert.Equal(t, 2*glyphAdvance, x)
+	assert.Equal(t, white, dst.At(1, 0), "top of '1'")
+	assert.Equal(t, color.RGBA{}, dst.At(0, 0))
+	assert.Equal(t, white, dst.At(4, 0), "unknown runes draw '?'")
+	assert.Equal(t, 3*glyphAdvance*2, textWidth("abc", 2))
+	assert.Equal(t, "ab", fitText(" abc ", 8, 1))
+}
@@ -3,6 +3,7 @@ package inspector
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -28,6 +29,9 @@ const (
 	ReportWaitDefaultTimeout  = DefaultPollingTimeout
 )
 
+// ErrReportFailed is returned by WaitForReport for reports that end with status ERROR.
+var ErrReportFailed = errors.New("report failed")
+
 // ReportService provides access to the Reports functions in the IC API.
 type ReportService struct {
 	client *Client
@@ -82,6 +86,32 @@ type ReportPriceTagsJson struct {
 	SkuId        int     `json:"sku_id" mapstructure:"sku_id"`
 }
 
+// UnmarshalJSON decodes a price tag. The API sends promo as a bool or a string;
+// a bool is stored as "1" or "0", the same as ToPriceTags does.
+func (p *ReportPriceTagsJson) UnmarshalJSON(b []byte) error {
+	type priceTag ReportPriceTagsJson
+	aux := struct {
+		*priceTag
+		Promo any `json:"promo"`
+	}{priceTag: (*priceTag)(p)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	switch v := aux.Promo.(type) {
+	case nil:
+	case bool:
+		p.Promo = "0"
+		if v {
+			p.Promo = "1"
+		}
+	case string:
+		p.Promo = v
+	default:
+		return fmt.Errorf("failed to Unmarshal promo %v of price tag %d: unsupported type %T", v, p.SkuId, v)
+	}
+	return nil
+}
+
 // ReportFacingCountJson represents a unit of data of FACING_COUNT report
 type ReportFacingCountJson struct {
 	Count int `json:"count"`
@@ -188,7 +218,7 @@ func (srv *ReportService) WaitForReport(ctx context.Context, id int, opts *Repor
 		case ReportStatusREADY:
 			return report, nil
 		case ReportStatusERROR:
-			return nil, fmt.Errorf("failed to WaitForReport(%d) with status ERROR", id)
+			return nil, fmt.Errorf("failed to WaitForReport(%d) with status ERROR:%w", id, ErrReportFailed)
 		}
 
 		timer := time.NewTimer(interval)
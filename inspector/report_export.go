@@ -0,0 +1,295 @@
+package inspector
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReportExportOptions configures report exports.
+type ReportExportOptions struct {
+	SKUs  SkuLookup // adds sku_name and ean13 columns from the catalog; optional
+	Comma rune      // CSV field delimiter (default: ','); ';' suits Excel in many locales
+	BOM   bool      // start CSV output with a UTF-8 byte order mark so Excel detects the encoding
+}
+
+// ReportTable is a report flattened to rows, one table per report type.
+// Cells are int, float64, string, bool or nil.
+type ReportTable struct {
+	Name   string // report type, used as XLSX sheet name
+	Header []string
+	Rows   [][]any
+}
+
+// ReportTables flattens a report into tables. v is the output of
+// ReportService.DecodeReport ([]ReportFacingCountJson, []ReportPriceTagsJson
+// or []ReportRealogramJson) or a *WebhookReports, which gives a table per
+// non-empty section.
+func ReportTables(v any, opts *ReportExportOptions) ([]*ReportTable, error) {
+	var skus SkuLookup
+	if opts != nil {
+		skus = opts.SKUs
+	}
+	switch v := v.(type) {
+	case []ReportFacingCountJson:
+		return []*ReportTable{facingCountTable(v, skus)}, nil
+	case []ReportPriceTagsJson:
+		return []*ReportTable{priceTagsTable(v, skus)}, nil
+	case []ReportRealogramJson:
+		return []*ReportTable{realogramTable(v, skus)}, nil
+	case WebhookReports:
+		return ReportTables(&v, opts)
+	case *WebhookReports:
+		var tables []*ReportTable
+		if len(v.Reports.FacingCount) > 0 {
+			tables = append(tables, facingCountTable(v.Reports.FacingCount, skus))
+		}
+		if len(v.Reports.PriceTags) > 0 {
+			tables = append(tables, priceTagsTable(v.Reports.PriceTags, skus))
+		}
+		if len(v.Reports.Realogram) > 0 {
+			tables = append(tables, realogramTable(v.Reports.Realogram, skus))
+		}
+		return tables, nil
+	}
+	return nil, fmt.Errorf("failed to export report: unsupported type %T", v)
+}
+
+// catalogColumns returns the catalog columns of sku, nil without a lookup.
+func catalogColumns(skus SkuLookup, sku int) []any {
+	if skus == nil {
+		return nil
+	}
+	s, ok := skus.LookupSKU(sku)
+	if !ok {
+		return []any{nil, nil}
+	}
+	return []any{s.Name, derefOrNil(s.EAN13)}
+}
+
+func withCatalogHeader(skus SkuLookup, header ...string) []string {
+	if skus == nil {
+		return header
+	}
+	return append(header, "sku_name", "ean13")
+}
+
+func facingCountTable(counts []ReportFacingCountJson, skus SkuLookup) *ReportTable {
+	t := &ReportTable{Name: ReportTypeFACING_COUNT, Header: withCatalogHeader(skus, "sku_id", "count")}
+	for _, c := range counts {
+		t.Rows = append(t.Rows, append([]any{c.SkuId, c.Count}, catalogColumns(skus, c.SkuId)...))
+	}
+	return t
+}
+
+func priceTagsTable(tags []ReportPriceTagsJson, skus SkuLookup) *ReportTable {
+	t := &ReportTable{Name: ReportTypePRICE_TAGS, Header: withCatalogHeader(skus,
+		"sku_id", "name", "brand", "manufacturer", "category", "price", "promo", "sku_image_url")}
+	for _, p := range tags {
+		t.Rows = append(t.Rows, append([]any{p.SkuId, p.Name, p.Brand, p.Manufacturer, p.Category, p.Price, p.IsPromo(), p.SkuImageUrl},
+			catalogColumns(skus, p.SkuId)...))
+	}
+	return t
+}
+
+func realogramTable(realograms []ReportRealogramJson, skus SkuLookup) *ReportTable {
+	t := &ReportTable{Name: ReportTypeREALOGRAM, Header: withCatalogHeader(skus,
+		"image", "sku_id", "name", "x", "y", "w", "h", "duplicate")}
+	for _, r := range realograms {
+		for _, a := range r.Annotations {
+			t.Rows = append(t.Rows, append([]any{r.Image, a.SkuId, a.Name, a.X, a.Y, a.W, a.H, a.Duplicate},
+				catalogColumns(skus, a.SkuId)...))
+		}
+	}
+	return t
+}
+
+// ExportReportCSV writes a report as CSV with a header row, see ReportTables.
+// Several tables of a WebhookReports are written one after another, each
+// preceded by a row with its name and separated by an empty line.
+func ExportReportCSV(w io.Writer, v any, opts *ReportExportOptions) error {
+	tables, err := ReportTables(v, opts)
+	if err != nil {
+		return err
+	}
+	if opts != nil && opts.BOM {
+		if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+			return fmt.Errorf("failed to write CSV:%w", err)
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if opts != nil && opts.Comma != 0 {
+		cw.Comma = opts.Comma
+	}
+	for i, t := range tables {
+		if len(tables) > 1 {
+			if i > 0 {
+				cw.Write(nil)
+			}
+			cw.Write([]string{t.Name})
+		}
+		cw.Write(t.Header)
+		record := make([]string, len(t.Header))
+		for _, row := range t.Rows {
+			for j, cell := range row {
+				record[j] = formatReportCell(cell)
+			}
+			cw.Write(record)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV:%w", err)
+	}
+	return nil
+}
+
+func formatReportCell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// ExportReportXLSX writes a report as an Excel workbook with one sheet per
+// report type, see ReportTables. The header row of every sheet is frozen.
+func ExportReportXLSX(w io.Writer, v any, opts *ReportExportOptions) error {
+	tables, err := ReportTables(v, opts)
+	if err != nil {
+		return err
+	}
+	if len(tables) == 0 {
+		return fmt.Errorf("failed to export report: no data")
+	}
+
+	zw := zip.NewWriter(w)
+	add := func(name, content string) error {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(f, content)
+		return err
+	}
+
+	var contentTypes, workbook, rels strings.Builder
+	contentTypes.WriteString(xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	workbook.WriteString(xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	rels.WriteString(xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i, t := range tables {
+		n := i + 1
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(xlsxSheetName(t.Name)), n, n)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+	}
+	contentTypes.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	rels.WriteString(`</Relationships>`)
+
+	files := []struct{ name, content string }{
+		{"[Content_Types].xml", contentTypes.String()},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+	}
+	for i, t := range tables {
+		files = append(files, struct{ name, content string }{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), xlsxSheet(t)})
+	}
+	for _, f := range files {
+		if err := add(f.name, f.content); err != nil {
+			return fmt.Errorf("failed to write XLSX %s:%w", f.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write XLSX:%w", err)
+	}
+	return nil
+}
+
+// xlsxSheet renders a table as worksheet XML with inline strings.
+func xlsxSheet(t *ReportTable) string {
+	var b strings.Builder
+	b.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>` +
+		`<sheetData>`)
+	row := func(r int, cells []any) {
+		fmt.Fprintf(&b, `<row r="%d">`, r)
+		for c, v := range cells {
+			ref := xlsxColumn(c) + strconv.Itoa(r)
+			switch v := v.(type) {
+			case nil:
+			case int, float64:
+				fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, formatReportCell(v))
+			case bool:
+				n := 0
+				if v {
+					n = 1
+				}
+				fmt.Fprintf(&b, `<c r="%s" t="b"><v>%d</v></c>`, ref, n)
+			default:
+				if s := fmt.Sprint(v); s != "" {
+					fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, xmlEscape(s))
+				}
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	header := make([]any, len(t.Header))
+	for i, h := range t.Header {
+		header[i] = h
+	}
+	row(1, header)
+	for i, cells := range t.Rows {
+		row(i+2, cells)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// xlsxColumn returns the column letters of a zero-based column index: A, B, ..., Z, AA, ...
+func xlsxColumn(i int) string {
+	var s []byte
+	for i++; i > 0; i = (i - 1) / 26 {
+		s = append([]byte{byte('A' + (i-1)%26)}, s...)
+	}
+	return string(s)
+}
+
+// xlsxSheetName strips characters Excel forbids in sheet names and truncates to 31 characters.
+func xlsxSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if r := []rune(name); len(r) > 31 {
+		name = string(r[:31])
+	}
+	return name
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,140 @@
+package inspector
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// loadWebhookReports parses testdata/webhook_reports.json.
+func loadWebhookReports(t *testing.T) *WebhookReports {
+	t.Helper()
+	b, err := os.ReadFile("testdata/webhook_reports.json")
+	assert.NoError(t, err)
+	var srv ReportService
+	wh, err := srv.ParseWebhookReports(b)
+	assert.NoError(t, err)
+	return wh
+}
+
+// exportTestWebhook returns the first two facing counts and the first price tag (a promo tag) of the webhook testdata.
+func exportTestWebhook(t *testing.T) *WebhookReports {
+	wh := loadWebhookReports(t)
+	wh.Reports.FacingCount = wh.Reports.FacingCount[:2]
+	wh.Reports.PriceTags = wh.Reports.PriceTags[:1]
+	wh.Reports.Realogram = nil
+	return wh
+}
+
+func TestExportReportCSV(t *testing.T) {
+	ean := "4600000000017"
+	skus := SkuIndex{9857: {ID: 9857, Name: "Bref Power", EAN13: &ean}}
+
+	t.Run("single report", func(t *testing.T) {
+		var b bytes.Buffer
+		err := ExportReportCSV(&b, exportTestWebhook(t).Reports.FacingCount, &ReportExportOptions{SKUs: skus, Comma: ';'})
+		assert.NoError(t, err)
+		assert.Equal(t, "sku_id;count;sku_name;ean13\n9857;4;Bref Power;4600000000017\n2;101;;\n", b.String())
+	})
+
+	t.Run("webhook sections", func(t *testing.T) {
+		var b bytes.Buffer
+		assert.NoError(t, ExportReportCSV(&b, exportTestWebhook(t), &ReportExportOptions{BOM: true}))
+		assert.Equal(t, "\uFEFF"+
+			"FACING_COUNT\nsku_id,count\n9857,4\n2,101\n"+
+			"\n"+
+			"PRICE_TAGS\nsku_id,name,brand,manufacturer,category,price,promo,sku_image_url\n"+
+			"9859,Bref 2*Оригинал Сила Актив Лаванда,Bref,Henkel,HOME & HYGIENE,360,true,http://henkel.inspector-cloud.ru/media/2019/08/28/0ffbe108-2ab2-4b1d-a296-dca361ce1cd4.jpg\n", b.String())
+	})
+
+	t.Run("webhook promo tags", func(t *testing.T) {
+		var b bytes.Buffer
+		assert.NoError(t, ExportReportCSV(&b, loadWebhookReports(t).Reports.PriceTags, nil))
+		rows, err := csv.NewReader(&b).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, rows, 13)
+		var promo []string
+		for _, row := range rows[1:] {
+			if row[6] == "true" {
+				promo = append(promo, row[0])
+			}
+		}
+		assert.Equal(t, []string{"9859", "9869"}, promo)
+	})
+
+	t.Run("realogram", func(t *testing.T) {
+		var b bytes.Buffer
+		r := []ReportRealogramJson{{Image: 5, Annotations: []ReportRealogramAnnotations{{X: 10, Y: 20, W: 30, H: 40, SkuId: 1, Name: "Cola", Duplicate: true}}}}
+		assert.NoError(t, ExportReportCSV(&b, r, nil))
+		assert.Equal(t, "image,sku_id,name,x,y,w,h,duplicate\n5,1,Cola,10,20,30,40,true\n", b.String())
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		assert.Error(t, ExportReportCSV(io.Discard, "report", nil))
+	})
+}
+
+func TestExportReportXLSX(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, ExportReportXLSX(&b, exportTestWebhook(t), nil))
+
+	zr, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	assert.NoError(t, err)
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		assert.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		assert.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+	assert.Len(t, files, 6)
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="FACING_COUNT" sheetId="1" r:id="rId1"/><sheet name="PRICE_TAGS" sheetId="2" r:id="rId2"/>`)
+	assert.Contains(t, files["[Content_Types].xml"], `/xl/worksheets/sheet2.xml`)
+
+	var sheet struct {
+		Rows []struct {
+			R     int `xml:"r,attr"`
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	for name, content := range files {
+		if strings.HasSuffix(name, ".xml") || strings.HasSuffix(name, ".rels") {
+			assert.NoError(t, xml.Unmarshal([]byte(content), new(any)), name)
+		}
+	}
+	assert.NoError(t, xml.Unmarshal([]byte(files["xl/worksheets/sheet2.xml"]), &sheet))
+	assert.Len(t, sheet.Rows, 2)
+	row := sheet.Rows[1]
+	assert.Equal(t, 2, row.R)
+	assert.Equal(t, "A2", row.Cells[0].Ref)
+	assert.Equal(t, "9859", row.Cells[0].Value)
+	assert.Equal(t, "inlineStr", row.Cells[1].Type)
+	assert.Equal(t, "Bref 2*Оригинал Сила Актив Лаванда", row.Cells[1].Inline)
+	assert.Equal(t, "F2", row.Cells[5].Ref)
+	assert.Equal(t, "360", row.Cells[5].Value)
+	assert.Equal(t, "G2", row.Cells[6].Ref)
+	assert.Equal(t, "b", row.Cells[6].Type)
+	assert.Equal(t, "1", row.Cells[6].Value)
+
+	assert.Error(t, ExportReportXLSX(io.Discard, &WebhookReports{}, nil))
+}
+
+func TestXlsxColumn(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"} {
+		assert.Equal(t, want, xlsxColumn(i))
+	}
+}
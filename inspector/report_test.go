@@ -312,7 +312,7 @@ func TestReportService_ParseWebhookReports(t *testing.T) {
 					Name:         "Bref",
 					Category:     "HOME & HYGIENE",
 					SkuImageUrl:  "http://henkel.inspector-cloud.ru/media/2019/08/28/0ffbe108-2ab2-4b1d-a296-dca361ce1cd4.jpg",
-					Promo:        "",
+					Promo:        "1",
 					SkuId:        9859,
 				},
 			},
@@ -349,6 +349,9 @@ func TestReportService_ParseWebhookReports_Error(t *testing.T) {
 	var srv ReportService
 	_, err := srv.ParseWebhookReports([]byte(`{`))
 	assert.Error(t, err)
+
+	_, err = srv.ParseWebhookReports([]byte(`{"reports":{"PRICE_TAGS":[{"sku_id":1,"promo":1}]}}`))
+	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to Unmarshal")
 }
 
@@ -404,6 +407,7 @@ func TestReportService_WaitForReport(t *testing.T) {
 		})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "status ERROR")
+		assert.ErrorIs(t, err, ErrReportFailed)
 	})
 
 	t.Run("times out", func(t *testing.T) {
@@ -0,0 +1,269 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// VisitSessionOptions configures a VisitSession.
+type VisitSessionOptions struct {
+	Visit       VisitCreateRequest // payload used to open the visit
+	VisitID     int                // existing visit to attach to instead of creating a new one
+	ReportTypes []string           // reports generated for every display (default: FACING_COUNT)
+	Datetime    *time.Time         // shared date and time of the recognitions
+	CountryCode string             // shared country code of the recognitions
+	RetailChain string             // shared retail chain of the recognitions
+	Concurrency int                // displays processed concurrently (default: DefaultSessionConcurrency)
+	Wait        *ReportWaitOptions // report polling options, see WaitForReport
+}
+
+// DisplayResult represents the outcome of one display of a visit.
+type DisplayResult struct {
+	Display      int                `json:"display"`
+	Images       []Image            `json:"images"`
+	Recognition  *RecognizeResponse `json:"recognition,omitempty"`
+	Reports      map[string]*Report `json:"reports,omitempty"` // ready reports by report type
+	ReportErrors map[string]error   `json:"-"`                 // failed reports by report type
+	Err          error              `json:"-"`                 // upload or recognition error
+}
+
+// Failed reports whether the display has any error.
+func (r *DisplayResult) Failed() bool {
+	return r.Err != nil || len(r.ReportErrors) > 0
+}
+
+// VisitSummary represents the outcome of a VisitSession.
+type VisitSummary struct {
+	Visit    *Visit          `json:"visit"`
+	Displays []DisplayResult `json:"displays"` // in the order displays were added
+}
+
+// Failed returns the number of displays with errors.
+func (s *VisitSummary) Failed() int {
+	n := 0
+	for i := range s.Displays {
+		if s.Displays[i].Failed() {
+			n++
+		}
+	}
+	return n
+}
+
+// Err joins all display and report errors, nil if every display succeeded.
+func (s *VisitSummary) Err() error {
+	var errs []error
+	for _, d := range s.Displays {
+		if d.Err != nil {
+			errs = append(errs, fmt.Errorf("display %d: %w", d.Display, d.Err))
+		}
+		for reportType, err := range d.ReportErrors {
+			errs = append(errs, fmt.Errorf("display %d report %s: %w", d.Display, reportType, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ErrSessionUsed is returned by VisitSession.Run when the session has already run.
+var ErrSessionUsed = errors.New("visit session already run")
+
+// sessionPhoto is a photo queued for upload either from a reader or by URL.
+type sessionPhoto struct {
+	reader   io.Reader
+	filename string
+	url      string
+}
+
+// VisitSession orchestrates a store audit: one visit covering several displays,
+// each with several photos. Photos of a display are uploaded and recognized
+// together, then all reports are awaited.
+type VisitSession struct {
+	client API
+	opts   VisitSessionOptions
+
+	openMu sync.Mutex // serializes Open, so the visit API call does not hold mu
+	visit  *Visit
+
+	mu       sync.Mutex
+	ran      bool // photo readers are consumed by the first Run
+	displays []int
+	photos   map[int][]sessionPhoto
+}
+
+// NewVisitSession makes a new VisitSession using the visit, image, recognize and report services of c.
+func NewVisitSession(c API, opts VisitSessionOptions) *VisitSession {
+	if len(opts.ReportTypes) == 0 {
+		opts.ReportTypes = []string{ReportTypeFACING_COUNT}
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultSessionConcurrency
+	}
+	return &VisitSession{
+		client: c,
+		opts:   opts,
+		photos: make(map[int][]sessionPhoto),
+	}
+}
+
+// Open creates the visit, or fetches it when VisitID is set. Calling Open again returns the same visit.
+// Photos can be added while the visit is being opened.
+func (s *VisitSession) Open(ctx context.Context) (*Visit, error) {
+	s.openMu.Lock()
+	defer s.openMu.Unlock()
+	if s.visit != nil {
+		return s.visit, nil
+	}
+
+	var (
+		visit *Visit
+		err   error
+	)
+	if s.opts.VisitID != 0 {
+		visit, err = s.client.Visits().GetVisit(ctx, s.opts.VisitID)
+	} else {
+		visit, err = s.client.Visits().CreateVisit(ctx, s.opts.Visit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open visit session:%w", err)
+	}
+	s.visit = visit
+	return visit, nil
+}
+
+// AddPhoto queues a photo of the display read from r.
+func (s *VisitSession) AddPhoto(display int, r io.Reader, filename string) {
+	s.addPhoto(display, sessionPhoto{reader: r, filename: filename})
+}
+
+// AddPhotoURL queues a photo of the display uploaded by URL.
+func (s *VisitSession) AddPhotoURL(display int, url string) {
+	s.addPhoto(display, sessionPhoto{url: url})
+}
+
+func (s *VisitSession) addPhoto(display int, p sessionPhoto) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.photos[display]; !ok {
+		s.displays = append(s.displays, display)
+	}
+	s.photos[display] = append(s.photos[display], p)
+}
+
+// Run opens the visit if needed, processes every display and waits for all reports.
+// Display failures are recorded in the summary; an error is returned only
+// when the visit cannot be opened or ctx is done.
+// A session runs once: photo readers are consumed by the upload, so a second
+// Run returns ErrSessionUsed.
+func (s *VisitSession) Run(ctx context.Context) (*VisitSummary, error) {
+	visit, err := s.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.ran {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("failed to run visit session:%w", ErrSessionUsed)
+	}
+	s.ran = true
+	displays := append([]int(nil), s.displays...)
+	photos := make(map[int][]sessionPhoto, len(s.photos))
+	for d, p := range s.photos {
+		photos[d] = p
+	}
+	s.mu.Unlock()
+
+	summary := &VisitSummary{Visit: visit, Displays: make([]DisplayResult, len(displays))}
+	sem := make(chan struct{}, s.opts.Concurrency)
+	var wg sync.WaitGroup
+	for i, display := range displays {
+		wg.Add(1)
+		go func(i, display int) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				summary.Displays[i] = DisplayResult{Display: display, Err: ctx.Err()}
+				return
+			}
+			summary.Displays[i] = s.runDisplay(ctx, visit.ID, display, photos[display])
+		}(i, display)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return summary, fmt.Errorf("failed to run visit session:%w", err)
+	}
+	return summary, nil
+}
+
+func (s *VisitSession) runDisplay(ctx context.Context, visitID, display int, photos []sessionPhoto) DisplayResult {
+	result := DisplayResult{Display: display}
+
+	for _, p := range photos {
+		var (
+			img Image
+			err error
+		)
+		if p.url != "" {
+			img, err = s.client.Images().UploadByURL(ctx, p.url)
+		} else {
+			img, err = s.client.Images().Upload(ctx, p.reader, p.filename)
+		}
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		result.Images = append(result.Images, img)
+	}
+
+	ids := make([]int, len(result.Images))
+	for i, img := range result.Images {
+		ids[i] = img.ID
+	}
+	rec, err := s.client.Recognizer().Recognize(ctx, RecognizeRequest{
+		Images:      ids,
+		ReportTypes: s.opts.ReportTypes,
+		Display:     display,
+		Visit:       visitID,
+		Datetime:    s.opts.Datetime,
+		CountryCode: s.opts.CountryCode,
+		RetailChain: s.opts.RetailChain,
+	})
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Recognition = rec
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for reportType, reportID := range rec.Reports {
+		wg.Add(1)
+		go func(reportType string, reportID int) {
+			defer wg.Done()
+			report, err := s.client.Reports().WaitForReport(ctx, reportID, s.opts.Wait)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if result.ReportErrors == nil {
+					result.ReportErrors = make(map[string]error)
+				}
+				result.ReportErrors[reportType] = err
+				return
+			}
+			if result.Reports == nil {
+				result.Reports = make(map[string]*Report)
+			}
+			result.Reports[reportType] = report
+		}(reportType, reportID)
+	}
+	wg.Wait()
+
+	return result
+}
@@ -0,0 +1,78 @@
+package inspector_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/germangorelkin/go-inspector/inspector"
+	"github.com/germangorelkin/go-inspector/inspector/inspectormock"
+	"github.com/stretchr/testify/assert"
+)
+
+// The tests in this file drive VisitSession through inspectormock, which imports
+// package inspector and therefore needs an external test package.
+
+func TestVisitSession_Open(t *testing.T) {
+	api := inspectormock.NewClient()
+	api.Visit.GetVisitFunc = func(ctx context.Context, id int) (*inspector.Visit, error) {
+		return &inspector.Visit{ID: id}, nil
+	}
+
+	session := inspector.NewVisitSession(api, inspector.VisitSessionOptions{VisitID: 55})
+	visit, err := session.Open(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 55, visit.ID)
+
+	summary, err := session.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 55, summary.Visit.ID)
+	assert.Empty(t, summary.Displays)
+	assert.NoError(t, summary.Err())
+
+	calls := api.Visit.CallsTo("GetVisit")
+	assert.Len(t, calls, 1)
+	assert.Equal(t, 55, calls[0].Args[1])
+	assert.Empty(t, api.Visit.CallsTo("CreateVisit"))
+}
+
+func TestVisitSession_RunWithMocks(t *testing.T) {
+	api := inspectormock.NewClient()
+	api.Visit.CreateVisitFunc = func(ctx context.Context, vr inspector.VisitCreateRequest) (*inspector.Visit, error) {
+		return &inspector.Visit{ID: 7, Shop: vr.Shop}, nil
+	}
+	api.Image.UploadByURLFunc = func(ctx context.Context, url string) (inspector.Image, error) {
+		return inspector.Image{ID: 11, URL: url}, nil
+	}
+	api.Recognize.RecognizeFunc = func(ctx context.Context, rr inspector.RecognizeRequest) (*inspector.RecognizeResponse, error) {
+		return &inspector.RecognizeResponse{ID: 3, Images: rr.Images, Reports: map[string]int{inspector.ReportTypeFACING_COUNT: 30}}, nil
+	}
+	api.Report.WaitForReportFunc = func(ctx context.Context, id int, opts *inspector.ReportWaitOptions) (*inspector.Report, error) {
+		return &inspector.Report{ID: id, Status: inspector.ReportStatusREADY}, nil
+	}
+
+	session := inspector.NewVisitSession(api, inspector.VisitSessionOptions{Visit: inspector.VisitCreateRequest{Shop: 42}})
+	session.AddPhotoURL(1, "https://example.com/a.jpg")
+	summary, err := session.Run(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, summary.Err())
+	assert.Equal(t, 7, summary.Visit.ID)
+	assert.Len(t, summary.Displays, 1)
+	assert.Equal(t, 30, summary.Displays[0].Reports[inspector.ReportTypeFACING_COUNT].ID)
+
+	rr := api.Recognize.CallsTo("Recognize")[0].Args[1].(inspector.RecognizeRequest)
+	assert.Equal(t, 7, rr.Visit)
+	assert.Equal(t, 1, rr.Display)
+	assert.Equal(t, []int{11}, rr.Images)
+
+	t.Run("unexpected call", func(t *testing.T) {
+		api := inspectormock.NewClient()
+		api.Visit.CreateVisitFunc = func(ctx context.Context, vr inspector.VisitCreateRequest) (*inspector.Visit, error) {
+			return &inspector.Visit{ID: 7}, nil
+		}
+		session := inspector.NewVisitSession(api, inspector.VisitSessionOptions{})
+		session.AddPhotoURL(1, "https://example.com/a.jpg")
+		summary, err := session.Run(context.Background())
+		assert.NoError(t, err)
+		assert.ErrorIs(t, summary.Displays[0].Err, inspectormock.ErrUnexpectedCall)
+	})
+}
@@ -0,0 +1,203 @@
+package inspector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVisitSession_Run(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		nextImage  = 100
+		recognized = map[int]RecognizeRequest{}
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+endpointVisits, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, methodPOST, r.Method)
+		fmt.Fprint(w, `{"id":7,"shop":42,"agent":"route-7"}`)
+	})
+	mux.HandleFunc("/"+endpointUploads, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		nextImage++
+		id := nextImage
+		mu.Unlock()
+		fmt.Fprintf(w, `{"id":%d}`, id)
+	})
+	mux.HandleFunc("/"+endpointUploadsByURL, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if strings.Contains(body["url"], "broken") {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"detail":"bad image"}`)
+			return
+		}
+		mu.Lock()
+		nextImage++
+		id := nextImage
+		mu.Unlock()
+		fmt.Fprintf(w, `{"id":%d,"file":%q}`, id, body["url"])
+	})
+	mux.HandleFunc("/"+endpointRecognize, func(w http.ResponseWriter, r *http.Request) {
+		var rr RecognizeRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&rr))
+		mu.Lock()
+		recognized[rr.Display] = rr
+		mu.Unlock()
+		fmt.Fprintf(w, `{"id":%d,"reports":{"FACING_COUNT":%d}}`, rr.Display, rr.Display*10)
+	})
+	mux.HandleFunc("/reports/", func(w http.ResponseWriter, r *http.Request) {
+		var id int
+		fmt.Sscanf(r.URL.Path, "/reports/%d/", &id)
+		status := ReportStatusREADY
+		if id == 20 {
+			status = "ERROR"
+		}
+		fmt.Fprintf(w, `{"id":%d,"status":%q,"report_type":"FACING_COUNT","json":[]}`, id, status)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: ""})
+	assert.NoError(t, err)
+
+	dt := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	session := NewVisitSession(client, VisitSessionOptions{
+		Visit:       VisitCreateRequest{Shop: 42, Agent: "route-7"},
+		Datetime:    &dt,
+		CountryCode: "RU",
+		RetailChain: "Chain",
+		Wait:        &ReportWaitOptions{Interval: time.Millisecond, Timeout: time.Second},
+	})
+	session.AddPhoto(1, strings.NewReader("a"), "a.jpg")
+	session.AddPhotoURL(1, "https://example.com/b.jpg")
+	session.AddPhotoURL(2, "https://example.com/c.jpg")
+	session.AddPhotoURL(3, "https://example.com/broken.jpg")
+
+	summary, err := session.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 7, summary.Visit.ID)
+	assert.Len(t, summary.Displays, 3)
+	assert.Equal(t, 2, summary.Failed())
+	assert.Error(t, summary.Err())
+
+	t.Run("display ok", func(t *testing.T) {
+		d := summary.Displays[0]
+		assert.Equal(t, 1, d.Display)
+		assert.False(t, d.Failed())
+		assert.Len(t, d.Images, 2)
+		assert.Equal(t, 10, d.Reports[ReportTypeFACING_COUNT].ID)
+
+		rr := recognized[1]
+		assert.Equal(t, 7, rr.Visit)
+		assert.Len(t, rr.Images, 2)
+		assert.Equal(t, "RU", rr.CountryCode)
+		assert.Equal(t, "Chain", rr.RetailChain)
+		assert.Equal(t, []string{ReportTypeFACING_COUNT}, rr.ReportTypes)
+		assert.True(t, dt.Equal(*rr.Datetime))
+	})
+
+	t.Run("report error", func(t *testing.T) {
+		d := summary.Displays[1]
+		assert.Equal(t, 2, d.Display)
+		assert.NoError(t, d.Err)
+		assert.Error(t, d.ReportErrors[ReportTypeFACING_COUNT])
+		assert.Empty(t, d.Reports)
+	})
+
+	t.Run("second run", func(t *testing.T) {
+		_, err := session.Run(context.Background())
+		assert.ErrorIs(t, err, ErrSessionUsed)
+	})
+
+	t.Run("upload error", func(t *testing.T) {
+		d := summary.Displays[2]
+		assert.Equal(t, 3, d.Display)
+		assert.Error(t, d.Err)
+		assert.Nil(t, d.Recognition)
+		_, ok := recognized[3]
+		assert.False(t, ok)
+	})
+}
+
+func TestVisitSession_RunCanceled(t *testing.T) {
+	var uploads int32
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/"+fmt.Sprintf(endpointVisitByID, 55) {
+			fmt.Fprint(w, `{"id":55}`)
+			return
+		}
+		atomic.AddInt32(&uploads, 1)
+		<-release // uploads hang until the test ends
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: ""})
+	assert.NoError(t, err)
+
+	session := NewVisitSession(client, VisitSessionOptions{VisitID: 55, Concurrency: 1})
+	for display := 1; display <= 3; display++ {
+		session.AddPhotoURL(display, "https://example.com/shelf.jpg")
+	}
+	_, err = session.Open(context.Background())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	summary, err := session.Run(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 3, summary.Failed())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&uploads)) // waiting displays do not start after ctx is done
+}
+
+func TestVisitSession_AddPhotoDuringOpen(t *testing.T) {
+	requested := make(chan struct{})
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(requested)
+		<-release
+		fmt.Fprint(w, `{"id":7}`)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: ""})
+	assert.NoError(t, err)
+
+	session := NewVisitSession(client, VisitSessionOptions{Visit: VisitCreateRequest{Shop: 42}})
+	opened := make(chan *Visit)
+	go func() {
+		visit, err := session.Open(context.Background())
+		assert.NoError(t, err)
+		opened <- visit
+	}()
+	<-requested
+
+	added := make(chan struct{})
+	go func() {
+		session.AddPhotoURL(1, "https://example.com/a.jpg")
+		close(added)
+	}()
+	select {
+	case <-added:
+	case <-time.After(time.Second):
+		t.Error("AddPhotoURL blocked by Open")
+	}
+
+	close(release)
+	visit := <-opened
+	assert.Equal(t, 7, visit.ID)
+	again, err := session.Open(context.Background())
+	assert.NoError(t, err)
+	assert.Same(t, visit, again)
+}
@@ -0,0 +1,193 @@
+package inspector
+
+import (
+	"sort"
+)
+
+// SkuLookup resolves SKUs by ID for report analytics.
+type SkuLookup interface {
+	LookupSKU(id int) (Sku, bool)
+}
+
+// SkuIndex is an in-memory SkuLookup, e.g. built from GetAllSKU.
+type SkuIndex map[int]Sku
+
+var _ SkuLookup = SkuIndex(nil)
+
+// NewSkuIndex indexes skus by ID.
+func NewSkuIndex(skus []Sku) SkuIndex {
+	idx := make(SkuIndex, len(skus))
+	for _, s := range skus {
+		idx[s.ID] = s
+	}
+	return idx
+}
+
+// LookupSKU implements SkuLookup.
+func (idx SkuIndex) LookupSKU(id int) (Sku, bool) {
+	s, ok := idx[id]
+	return s, ok
+}
+
+// ShareOfShelfOptions configures ComputeShareOfShelf.
+type ShareOfShelfOptions struct {
+	Realogram    *RealogramOptions // shelf assignment and duplicate handling
+	CountStacked bool              // count products stacked on others as facings and shelf width
+}
+
+// ShareOfShelfEntry is the shelf presence of one brand, manufacturer or category.
+type ShareOfShelfEntry struct {
+	ID          int     `json:"id"` // 0 for SKUs unknown to the lookup or without the attribute
+	Facings     int     `json:"facings"`
+	Pixels      int     `json:"pixels"`
+	MM          float64 `json:"mm"`
+	FacingShare float64 `json:"facing_share"`
+	PixelShare  float64 `json:"pixel_share"`
+	MMShare     float64 `json:"mm_share"`
+}
+
+// ShareOfShelf holds shares of facings, linear pixels and estimated linear millimetres.
+type ShareOfShelf struct {
+	Brands        []ShareOfShelfEntry `json:"brands"` // by facings, descending
+	Manufacturers []ShareOfShelfEntry `json:"manufacturers"`
+	Categories    []ShareOfShelfEntry `json:"categories"`
+	Facings       int                 `json:"facings"`
+	Pixels        int                 `json:"pixels"`
+	MM            float64             `json:"mm"`
+	UnknownSKUs   []int               `json:"unknown_skus,omitempty"` // SKU IDs missing from the lookup
+	UnscaledPx    int                 `json:"unscaled_px,omitempty"`  // width of products without any millimetre estimate
+}
+
+// ComputeShareOfShelf computes share of shelf by brand, manufacturer and category
+// from realogram images and a SKU lookup.
+//
+// Facings and pixel widths come from AnalyzeRealogram: front-row products on shelves
+// plus unassigned products. Millimetres use the SKU SizeXMM of each facing; SKUs without
+// a size are scaled by the mm-per-pixel ratio of the sized products of the same image.
+// Products of an image without any sized SKU are counted in UnscaledPx.
+func ComputeShareOfShelf(realograms []ReportRealogramJson, skus SkuLookup, opts *ShareOfShelfOptions) *ShareOfShelf {
+	var o ShareOfShelfOptions
+	if opts != nil {
+		o = *opts
+	}
+	counted := func(p ShelfProduct) bool {
+		if p.Duplicate && (o.Realogram == nil || !o.Realogram.IncludeDuplicates) {
+			return false
+		}
+		return o.CountStacked || !p.Stacked
+	}
+
+	type facing struct {
+		sku   Sku
+		found bool
+		px    int
+		mm    float64
+		sized bool
+	}
+
+	res := &ShareOfShelf{}
+	brands, manufacturers, categories := map[int]*ShareOfShelfEntry{}, map[int]*ShareOfShelfEntry{}, map[int]*ShareOfShelfEntry{}
+	unknown := map[int]bool{}
+
+	for _, r := range realograms {
+		layout := AnalyzeRealogram(r, o.Realogram)
+		products := append([]ShelfProduct(nil), layout.Unassigned...)
+		for _, s := range layout.Shelves {
+			products = append(products, s.Products...)
+		}
+
+		var facings []facing
+		var sizedPx int
+		var sizedMM float64
+		for _, p := range products {
+			if !counted(p) {
+				continue
+			}
+			f := facing{px: p.W}
+			f.sku, f.found = skus.LookupSKU(p.SkuId)
+			if !f.found {
+				unknown[p.SkuId] = true
+			}
+			if f.found && f.sku.SizeXMM != nil && *f.sku.SizeXMM > 0 {
+				f.mm, f.sized = *f.sku.SizeXMM, true
+				sizedPx += f.px
+				sizedMM += f.mm
+			}
+			facings = append(facings, f)
+		}
+
+		for _, f := range facings {
+			if !f.sized {
+				if sizedPx == 0 {
+					res.UnscaledPx += f.px
+				} else {
+					f.mm = float64(f.px) * sizedMM / float64(sizedPx)
+				}
+			}
+			res.Facings++
+			res.Pixels += f.px
+			res.MM += f.mm
+
+			var brand, manufacturer, category int
+			if f.found {
+				brand, manufacturer, category = derefOrZero(f.sku.Brand), derefOrZero(f.sku.Manufacturer), derefOrZero(f.sku.Category)
+			}
+			for _, e := range []*ShareOfShelfEntry{
+				shareEntry(brands, brand), shareEntry(manufacturers, manufacturer), shareEntry(categories, category),
+			} {
+				e.Facings++
+				e.Pixels += f.px
+				e.MM += f.mm
+			}
+		}
+	}
+
+	res.Brands = shareEntries(brands, res)
+	res.Manufacturers = shareEntries(manufacturers, res)
+	res.Categories = shareEntries(categories, res)
+	for id := range unknown {
+		res.UnknownSKUs = append(res.UnknownSKUs, id)
+	}
+	sort.Ints(res.UnknownSKUs)
+	return res
+}
+
+func derefOrZero(p *int) int {
+	if p == nil {
+		return 0
+	}
+	return *p
+}
+
+func shareEntry(m map[int]*ShareOfShelfEntry, id int) *ShareOfShelfEntry {
+	e, ok := m[id]
+	if !ok {
+		e = &ShareOfShelfEntry{ID: id}
+		m[id] = e
+	}
+	return e
+}
+
+// shareEntries computes the shares against the totals and orders entries by facings.
+func shareEntries(m map[int]*ShareOfShelfEntry, total *ShareOfShelf) []ShareOfShelfEntry {
+	out := make([]ShareOfShelfEntry, 0, len(m))
+	for _, e := range m {
+		if total.Facings > 0 {
+			e.FacingShare = float64(e.Facings) / float64(total.Facings)
+		}
+		if total.Pixels > 0 {
+			e.PixelShare = float64(e.Pixels) / float64(total.Pixels)
+		}
+		if total.MM > 0 {
+			e.MMShare = e.MM / total.MM
+		}
+		out = append(out, *e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Facings != out[j].Facings {
+			return out[i].Facings > out[j].Facings
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
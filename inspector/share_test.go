@@ -0,0 +1,77 @@
+package inspector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeShareOfShelf(t *testing.T) {
+	intp := func(v int) *int { return &v }
+	mm := func(v float64) *float64 { return &v }
+	skus := NewSkuIndex([]Sku{
+		{ID: 1, Brand: intp(10), Manufacturer: intp(100), Category: intp(7), SizeXMM: mm(100)},
+		{ID: 2, Brand: intp(10), Manufacturer: intp(100), Category: intp(7)},
+		{ID: 3, Brand: intp(20), Manufacturer: intp(100), Category: intp(8), SizeXMM: mm(200)},
+	})
+	realogram := ReportRealogramJson{
+		Image: 1,
+		Annotations: []ReportRealogramAnnotations{
+			{X: 50, Y: 150, W: 100, H: 100, SkuId: 1},
+			{X: 50, Y: 50, W: 100, H: 100, SkuId: 1},                   // stacked on the first product
+			{X: 125, Y: 150, W: 50, H: 100, SkuId: 2},                  // no size, scaled 1mm per pixel
+			{X: 250, Y: 150, W: 200, H: 100, SkuId: 3},                 // sized
+			{X: 375, Y: 150, W: 50, H: 100, SkuId: 99},                 // unknown to the lookup
+			{X: 375, Y: 150, W: 50, H: 100, SkuId: 3, Duplicate: true}, // not counted
+		},
+		ShelfAnnotations: []ReportRealogramShelfAnnotations{{X1: 0, Y1: 200, X2: 500, Y2: 200}},
+	}
+
+	t.Run("shares by brand, manufacturer and category", func(t *testing.T) {
+		got := ComputeShareOfShelf([]ReportRealogramJson{realogram}, skus, nil)
+
+		assert.Equal(t, 4, got.Facings)
+		assert.Equal(t, 400, got.Pixels)
+		assert.InDelta(t, 400, got.MM, 1e-9)
+		assert.Equal(t, []int{99}, got.UnknownSKUs)
+		assert.Zero(t, got.UnscaledPx)
+
+		assert.Equal(t, []ShareOfShelfEntry{
+			{ID: 10, Facings: 2, Pixels: 150, MM: 150, FacingShare: 0.5, PixelShare: 0.375, MMShare: 0.375},
+			{ID: 0, Facings: 1, Pixels: 50, MM: 50, FacingShare: 0.25, PixelShare: 0.125, MMShare: 0.125},
+			{ID: 20, Facings: 1, Pixels: 200, MM: 200, FacingShare: 0.25, PixelShare: 0.5, MMShare: 0.5},
+		}, got.Brands)
+		assert.Equal(t, 100, got.Manufacturers[0].ID)
+		assert.Equal(t, 3, got.Manufacturers[0].Facings)
+		assert.Len(t, got.Categories, 3)
+	})
+
+	t.Run("stacked and duplicate products", func(t *testing.T) {
+		got := ComputeShareOfShelf([]ReportRealogramJson{realogram}, skus, &ShareOfShelfOptions{
+			CountStacked: true,
+			Realogram:    &RealogramOptions{IncludeDuplicates: true},
+		})
+		assert.Equal(t, 6, got.Facings)
+		assert.Equal(t, 3, got.Brands[0].Facings)
+	})
+
+	t.Run("millimetres are scaled by the sized SKUs", func(t *testing.T) {
+		r := realogram
+		r.Annotations = []ReportRealogramAnnotations{
+			{X: 50, Y: 150, W: 50, H: 100, SkuId: 1},  // 100mm over 50px: 2mm per pixel
+			{X: 125, Y: 150, W: 50, H: 100, SkuId: 2}, // estimated 100mm
+		}
+		got := ComputeShareOfShelf([]ReportRealogramJson{r}, skus, nil)
+		assert.InDelta(t, 200, got.MM, 1e-9)
+		assert.InDelta(t, 200, got.Brands[0].MM, 1e-9)
+	})
+
+	t.Run("report testdata without catalog", func(t *testing.T) {
+		got := ComputeShareOfShelf(loadRealogramReport(t), SkuIndex{}, nil)
+		assert.Equal(t, 55, got.Facings)
+		assert.Equal(t, got.Pixels, got.UnscaledPx)
+		assert.Zero(t, got.MM)
+		assert.Equal(t, []ShareOfShelfEntry{{ID: 0, Facings: 55, Pixels: got.Pixels, FacingShare: 1, PixelShare: 1}}, got.Brands)
+		assert.Len(t, got.UnknownSKUs, 17)
+	})
+}
@@ -0,0 +1,170 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ErrShopNotFound is returned when a shop lookup matches nothing.
+var ErrShopNotFound = errors.New("shop not found")
+
+// Shop represents a IC customer/outlet/shop
+type Shop struct {
+	ID           int      `json:"id"`                      // Unique shop ID
+	Name         string   `json:"name"`                    // Human readable shop name
+	Address      string   `json:"address,omitempty"`       // Postal address
+	RetailChain  string   `json:"retail_chain,omitempty"`  // Retail chain identifier, see RecognizeRequest.RetailChain
+	Latitude     *float64 `json:"latitude,omitempty"`      // Shop location
+	Longitude    *float64 `json:"longitude,omitempty"`     // Shop location
+	ExternalCode string   `json:"external_code,omitempty"` // Client-specific store code
+}
+
+// ShopQuery holds server-side filters for shop listing.
+// Zero values are not sent to the API.
+type ShopQuery struct {
+	Search       string // full-text search by name and address
+	RetailChain  string // exact retail chain identifier
+	ExternalCode string // exact client-specific store code
+}
+
+// Values encodes the query to url.Values.
+func (q *ShopQuery) Values() url.Values {
+	v := url.Values{}
+	if q == nil {
+		return v
+	}
+	if q.Search != "" {
+		v.Set(queryParamSearch, q.Search)
+	}
+	if q.RetailChain != "" {
+		v.Set(queryParamRetailChain, q.RetailChain)
+	}
+	if q.ExternalCode != "" {
+		v.Set(queryParamExternalCode, q.ExternalCode)
+	}
+	return v
+}
+
+// NewVisitRequest returns a payload for creating a visit to the shop.
+func (s Shop) NewVisitRequest(agent string, started time.Time) VisitCreateRequest {
+	return VisitCreateRequest{Shop: s.ID, Agent: agent, StartedDate: &started}
+}
+
+// ApplyTo copies shop-derived fields to the recognize request.
+func (s Shop) ApplyTo(rr *RecognizeRequest) {
+	if s.RetailChain != "" {
+		rr.RetailChain = s.RetailChain
+	}
+}
+
+// ShopService provides access to the Shop functions in the IC API.
+type ShopService struct {
+	client *Client
+}
+
+// ShopIterator provides paginated iteration over shops.
+type ShopIterator = Iterator[Shop]
+
+// CreateShop creates a new shop. The ID of shop is ignored by the server.
+func (srv *ShopService) CreateShop(ctx context.Context, shop Shop) (*Shop, error) {
+	req, err := srv.client.httpClient.NewRequest(methodPOST, endpointShops, shop)
+	if err != nil {
+		return nil, fmt.Errorf("failed to NewRequest(%s, %s, %v):%w", methodPOST, endpointShops, shop, err)
+	}
+
+	var created Shop
+	if _, err = srv.client.httpClient.Do(ctx, req, &created); err != nil {
+		return nil, fmt.Errorf("failed to Do with Request(%s, %s, %v):%w", methodPOST, endpointShops, shop, err)
+	}
+
+	return &created, nil
+}
+
+// GetShop requests shop for the given id
+func (srv *ShopService) GetShop(ctx context.Context, id int) (*Shop, error) {
+	var s Shop
+	if err := srv.client.getObject(ctx, fmt.Sprintf(endpointShopByID, id), &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// UpdateShop replaces shop identified by shop.ID
+func (srv *ShopService) UpdateShop(ctx context.Context, shop Shop) (*Shop, error) {
+	if shop.ID == 0 {
+		return nil, fmt.Errorf("failed to UpdateShop: empty id")
+	}
+	path := fmt.Sprintf(endpointShopByID, shop.ID)
+	req, err := srv.client.httpClient.NewRequest(methodPUT, path, shop)
+	if err != nil {
+		return nil, fmt.Errorf("failed to NewRequest(%s, %s, %v):%w", methodPUT, path, shop, err)
+	}
+
+	var updated Shop
+	if _, err = srv.client.httpClient.Do(ctx, req, &updated); err != nil {
+		return nil, fmt.Errorf("failed to Do with Request(%s, %s, %v):%w", methodPUT, path, shop, err)
+	}
+
+	return &updated, nil
+}
+
+// DeleteShop deletes shop for the given id
+func (srv *ShopService) DeleteShop(ctx context.Context, id int) error {
+	path := fmt.Sprintf(endpointShopByID, id)
+	req, err := srv.client.httpClient.NewRequest(methodDELETE, path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to NewRequest(%s, %s):%w", methodDELETE, path, err)
+	}
+
+	if _, err = srv.client.httpClient.Do(ctx, req, nil); err != nil {
+		return fmt.Errorf("failed to Do with Request(%s, %s):%w", methodDELETE, path, err)
+	}
+
+	return nil
+}
+
+// IterateShops returns an iterator over shops.
+// pageSize controls how many items are fetched per page (default: 100).
+// An optional ShopQuery is applied to every page.
+func (srv *ShopService) IterateShops(ctx context.Context, pageSize int, query ...*ShopQuery) ShopIterator {
+	var q *ShopQuery
+	if len(query) > 0 {
+		q = query[0]
+	}
+	return NewPaginator[Shop](ctx, srv.client, endpointShops, q.Values(), pageSize)
+}
+
+// ListShops fetches all shops matching query using automatic pagination.
+func (srv *ShopService) ListShops(ctx context.Context, query *ShopQuery) ([]Shop, error) {
+	return srv.IterateShops(ctx, DefaultPageSize, query).Collect()
+}
+
+// GetShopByExternalCode returns the shop with the given client-specific store code.
+// Returns ErrShopNotFound if no shop matches.
+func (srv *ShopService) GetShopByExternalCode(ctx context.Context, code string) (*Shop, error) {
+	if code == "" {
+		return nil, fmt.Errorf("failed to GetShopByExternalCode: empty code")
+	}
+	shops, err := srv.ListShops(ctx, &ShopQuery{ExternalCode: code})
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetShopByExternalCode(%s):%w", code, err)
+	}
+
+	var found []Shop
+	for _, s := range shops {
+		if s.ExternalCode == code {
+			found = append(found, s)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("failed to GetShopByExternalCode(%s):%w", code, ErrShopNotFound)
+	case 1:
+		return &found[0], nil
+	default:
+		return nil, fmt.Errorf("failed to GetShopByExternalCode(%s): %d shops share the code", code, len(found))
+	}
+}
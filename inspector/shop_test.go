@@ -0,0 +1,100 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShopService_CRUD(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == methodPOST && r.URL.Path == "/"+endpointShops:
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{"id":0,"name":"Magnit #12","address":"Lenina 1","retail_chain":"Magnit","latitude":55.7,"longitude":37.6,"external_code":"MAG-012"}`, string(body))
+			fmt.Fprint(w, `{"id":12,"name":"Magnit #12","address":"Lenina 1","retail_chain":"Magnit","latitude":55.7,"longitude":37.6,"external_code":"MAG-012"}`)
+		case r.Method == methodGET && r.URL.Path == "/shops/12/":
+			fmt.Fprint(w, `{"id":12,"name":"Magnit #12","retail_chain":"Magnit","external_code":"MAG-012"}`)
+		case r.Method == methodPUT && r.URL.Path == "/shops/12/":
+			fmt.Fprint(w, `{"id":12,"name":"Magnit #12 (renovated)","retail_chain":"Magnit","external_code":"MAG-012"}`)
+		case r.Method == methodDELETE && r.URL.Path == "/shops/12/":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: ""})
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	lat, lon := 55.7, 37.6
+	shop, err := client.Shop.CreateShop(ctx, Shop{Name: "Magnit #12", Address: "Lenina 1", RetailChain: "Magnit", Latitude: &lat, Longitude: &lon, ExternalCode: "MAG-012"})
+	assert.NoError(t, err)
+	assert.Equal(t, 12, shop.ID)
+
+	shop, err = client.Shop.GetShop(ctx, 12)
+	assert.NoError(t, err)
+	assert.Equal(t, "MAG-012", shop.ExternalCode)
+
+	shop.Name = "Magnit #12 (renovated)"
+	shop, err = client.Shop.UpdateShop(ctx, *shop)
+	assert.NoError(t, err)
+	assert.Equal(t, "Magnit #12 (renovated)", shop.Name)
+
+	_, err = client.Shop.UpdateShop(ctx, Shop{Name: "no id"})
+	assert.Error(t, err)
+
+	assert.NoError(t, client.Shop.DeleteShop(ctx, 12))
+}
+
+func TestShopService_GetShopByExternalCode(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/"+endpointShops, r.URL.Path)
+		switch r.URL.Query().Get("external_code") {
+		case "MAG-012":
+			writePage(w, 1, nil, `[{"id":12,"name":"Magnit #12","retail_chain":"Magnit","external_code":"MAG-012"}]`)
+		case "DUP":
+			writePage(w, 2, nil, `[{"id":1,"external_code":"DUP"},{"id":2,"external_code":"DUP"}]`)
+		default:
+			writePage(w, 0, nil, `[]`)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: ""})
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	shop, err := client.Shop.GetShopByExternalCode(ctx, "MAG-012")
+	assert.NoError(t, err)
+	assert.Equal(t, 12, shop.ID)
+
+	_, err = client.Shop.GetShopByExternalCode(ctx, "NOPE")
+	assert.True(t, errors.Is(err, ErrShopNotFound))
+
+	_, err = client.Shop.GetShopByExternalCode(ctx, "DUP")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "2 shops share the code")
+}
+
+func TestShop_Derive(t *testing.T) {
+	shop := Shop{ID: 12, RetailChain: "Magnit"}
+	started := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	vr := shop.NewVisitRequest("route-7", started)
+	assert.Equal(t, VisitCreateRequest{Shop: 12, Agent: "route-7", StartedDate: &started}, vr)
+
+	rr := RecognizeRequest{Images: []int{1}}
+	shop.ApplyTo(&rr)
+	assert.Equal(t, "Magnit", rr.RetailChain)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
 )
@@ -29,25 +30,65 @@ type SkuService struct {
 	client *Client
 }
 
-// GetSKU requests list of SKU.
-// Return Pagination for the given offset and limit
-func (srv *SkuService) GetSKU(ctx context.Context, offset, limit int) (*Pagination, error) {
-	path := endpointSKU
-	req, err := srv.client.httpClient.NewRequest(methodGET, path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to NewRequest(%s, %s):%w", methodGET, path, err)
-	}
+// SkuQuery holds server-side filters for SKU listing.
+// Zero values are not sent to the API.
+type SkuQuery struct {
+	Search        string     // full-text search by SKU name and codes
+	EAN13         string     // exact European Article Number
+	CID           string     // exact client-specific SKU ID
+	Brand         *int       // Brand ID
+	Category      *int       // Category ID
+	Manufacturer  *int       // Manufacturer ID
+	Ordering      string     // field name to order by, prefixed with "-" for descending order
+	ModifiedSince *time.Time // only SKUs modified at or after this time
+}
 
-	q := fmt.Sprintf("limit=%d&offset=%d", limit, offset)
-	req.URL.RawQuery += q
+// Values encodes the query to url.Values.
+func (q *SkuQuery) Values() url.Values {
+	v := url.Values{}
+	if q == nil {
+		return v
+	}
+	if q.Search != "" {
+		v.Set(queryParamSearch, q.Search)
+	}
+	if q.EAN13 != "" {
+		v.Set(queryParamEAN13, q.EAN13)
+	}
+	if q.CID != "" {
+		v.Set(queryParamCID, q.CID)
+	}
+	if q.Brand != nil {
+		v.Set(queryParamBrand, strconv.Itoa(*q.Brand))
+	}
+	if q.Category != nil {
+		v.Set(queryParamCategory, strconv.Itoa(*q.Category))
+	}
+	if q.Manufacturer != nil {
+		v.Set(queryParamManufacturer, strconv.Itoa(*q.Manufacturer))
+	}
+	if q.Ordering != "" {
+		v.Set(queryParamOrdering, q.Ordering)
+	}
+	if q.ModifiedSince != nil {
+		v.Set(queryParamModifiedSince, q.ModifiedSince.UTC().Format(time.RFC3339))
+	}
+	return v
+}
 
-	var pag Pagination
-	_, err = srv.client.httpClient.Do(ctx, req, &pag)
-	if err != nil {
-		return nil, fmt.Errorf("failed to Do with Request(%s, %s):%w", methodGET, req.URL.RawQuery, err)
+// firstSkuQuery returns the optional query passed to variadic SKU methods.
+func firstSkuQuery(query []*SkuQuery) *SkuQuery {
+	if len(query) == 0 {
+		return nil
 	}
+	return query[0]
+}
 
-	return &pag, nil
+// GetSKU requests list of SKU.
+// Return Pagination for the given offset and limit.
+// An optional SkuQuery narrows the list on the server side.
+func (srv *SkuService) GetSKU(ctx context.Context, offset, limit int, query ...*SkuQuery) (*Pagination, error) {
+	return srv.client.getPage(ctx, endpointSKU, firstSkuQuery(query).Values(), offset, limit)
 }
 
 // ToSku parses json to []Sku
@@ -60,115 +101,358 @@ func (srv *SkuService) ToSku(v any) ([]Sku, error) {
 }
 
 // SKUIterator provides paginated iteration over SKUs.
-type SKUIterator struct {
-	client    *SkuService
-	ctx       context.Context
-	pageSize  int
-	offset    int
-	hasMore   bool
-	seenPages map[int]bool
-	maxPages  int
-}
+type SKUIterator = Iterator[Sku]
 
 // IterateSKU returns an iterator for paginated SKU retrieval.
 // pageSize controls how many items are fetched per page (default: 100).
 // The iterator automatically handles pagination and includes safeguards
-// against infinite loops.
-func (srv *SkuService) IterateSKU(ctx context.Context, pageSize int) *SKUIterator {
-	if pageSize <= 0 {
-		pageSize = DefaultPageSize
+// against infinite loops. An optional SkuQuery is applied to every page.
+func (srv *SkuService) IterateSKU(ctx context.Context, pageSize int, query ...*SkuQuery) SKUIterator {
+	it := NewPaginator[Sku](ctx, srv.client, endpointSKU, firstSkuQuery(query).Values(), pageSize)
+	it.name = "SKU"
+	return it
+}
+
+// GetAllSKU fetches all SKUs using automatic pagination.
+// pageSize controls how many items are fetched per page (default: 100).
+// An optional SkuQuery is applied to every page.
+func (srv *SkuService) GetAllSKU(ctx context.Context, pageSize int, query ...*SkuQuery) ([]Sku, error) {
+	return srv.IterateSKU(ctx, pageSize, query...).Collect()
+}
+
+// GetAllSKUParallel fetches all SKUs like GetAllSKU, but after the first page
+// requests the remaining pages concurrently. Results keep the server order.
+// If the catalog changes during the pull (count drift, duplicate IDs) the pull is
+// retried up to opts.MaxRetries times; afterwards the deduplicated result is returned
+// and opts.OnWarning is called, or an error is returned when opts.Strict is set.
+func (srv *SkuService) GetAllSKUParallel(ctx context.Context, opts *ParallelFetchOptions, query ...*SkuQuery) ([]Sku, error) {
+	skus, err := fetchAllParallel(ctx, srv.client, endpointSKU, firstSkuQuery(query).Values(), opts, func(s Sku) int { return s.ID })
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetAllSKUParallel:%w", err)
 	}
-	return &SKUIterator{
-		client:    srv,
-		ctx:       ctx,
-		pageSize:  pageSize,
-		offset:    0,
-		hasMore:   true,
-		seenPages: make(map[int]bool),
-		maxPages:  MaxPaginationPages, // Safety limit to prevent infinite loops
+	return skus, nil
+}
+
+// FindByEAN returns all SKUs with the given European Article Number.
+func (srv *SkuService) FindByEAN(ctx context.Context, ean13 string) ([]Sku, error) {
+	if ean13 == "" {
+		return nil, fmt.Errorf("failed to FindByEAN: empty ean13")
 	}
+	return srv.GetAllSKU(ctx, DefaultPageSize, &SkuQuery{EAN13: ean13})
 }
 
-// Next returns the next page of SKUs.
-// Returns nil, nil when no more pages are available.
-func (it *SKUIterator) Next() ([]Sku, error) {
-	if !it.hasMore {
-		return nil, nil
+// FindByCID returns all SKUs with the given client-specific SKU ID.
+func (srv *SkuService) FindByCID(ctx context.Context, cid string) ([]Sku, error) {
+	if cid == "" {
+		return nil, fmt.Errorf("failed to FindByCID: empty cid")
 	}
+	return srv.GetAllSKU(ctx, DefaultPageSize, &SkuQuery{CID: cid})
+}
+
+// SkuPatch represents a payload of partial SKU update.
+// Nil fields are left unchanged on the server.
+type SkuPatch struct {
+	CID          *string  `json:"cid,omitempty"`
+	EAN13        *string  `json:"ean13,omitempty"`
+	Image        *int     `json:"image,omitempty"`
+	Name         *string  `json:"name,omitempty"`
+	Brand        *int     `json:"brand,omitempty"`
+	Category     *int     `json:"category,omitempty"`
+	Manufacturer *int     `json:"manufacturer,omitempty"`
+	SizeXMM      *float64 `json:"size_x_mm,omitempty"`
+	SizeYMM      *float64 `json:"size_y_mm,omitempty"`
+	SizeZMM      *float64 `json:"size_z_mm,omitempty"`
+}
+
+// IsEmpty reports whether the patch changes nothing.
+func (p SkuPatch) IsEmpty() bool {
+	return p == SkuPatch{}
+}
 
-	// Check infinite loop safeguard
-	if it.seenPages[it.offset] {
-		return nil, fmt.Errorf("detected pagination loop at offset %d", it.offset)
+// Fields returns json names of the fields set in the patch.
+func (p SkuPatch) Fields() []string {
+	var fields []string
+	add := func(set bool, name string) {
+		if set {
+			fields = append(fields, name)
+		}
+	}
+	add(p.CID != nil, "cid")
+	add(p.EAN13 != nil, "ean13")
+	add(p.Image != nil, "image")
+	add(p.Name != nil, "name")
+	add(p.Brand != nil, "brand")
+	add(p.Category != nil, "category")
+	add(p.Manufacturer != nil, "manufacturer")
+	add(p.SizeXMM != nil, "size_x_mm")
+	add(p.SizeYMM != nil, "size_y_mm")
+	add(p.SizeZMM != nil, "size_z_mm")
+	return fields
+}
+
+// DiffSku returns the minimal patch turning current into desired.
+// Nil pointer fields, zero Image and empty CID/Name of desired are treated
+// as "not managed" and never clear values on the server.
+func DiffSku(current, desired Sku) SkuPatch {
+	var p SkuPatch
+	if desired.CID != "" && desired.CID != current.CID {
+		p.CID = &desired.CID
+	}
+	if desired.Name != "" && desired.Name != current.Name {
+		p.Name = &desired.Name
+	}
+	if desired.Image != 0 && desired.Image != current.Image {
+		p.Image = &desired.Image
 	}
-	if len(it.seenPages) >= it.maxPages {
-		return nil, fmt.Errorf("exceeded maximum page limit of %d", it.maxPages)
+	if desired.EAN13 != nil && (current.EAN13 == nil || *desired.EAN13 != *current.EAN13) {
+		p.EAN13 = desired.EAN13
 	}
+	p.Brand = diffIntPtr(current.Brand, desired.Brand)
+	p.Category = diffIntPtr(current.Category, desired.Category)
+	p.Manufacturer = diffIntPtr(current.Manufacturer, desired.Manufacturer)
+	p.SizeXMM = diffFloatPtr(current.SizeXMM, desired.SizeXMM)
+	p.SizeYMM = diffFloatPtr(current.SizeYMM, desired.SizeYMM)
+	p.SizeZMM = diffFloatPtr(current.SizeZMM, desired.SizeZMM)
+	return p
+}
 
-	// Mark this page as seen
-	it.seenPages[it.offset] = true
+func diffIntPtr(current, desired *int) *int {
+	if desired == nil || (current != nil && *current == *desired) {
+		return nil
+	}
+	return desired
+}
 
-	// Fetch the page
-	pag, err := it.client.GetSKU(it.ctx, it.offset, it.pageSize)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch SKU page at offset %d:%w", it.offset, err)
+func diffFloatPtr(current, desired *float64) *float64 {
+	if desired == nil || (current != nil && *current == *desired) {
+		return nil
+	}
+	return desired
+}
+
+// GetSKUByID requests SKU for the given id
+func (srv *SkuService) GetSKUByID(ctx context.Context, id int) (*Sku, error) {
+	var sku Sku
+	if err := srv.client.getObject(ctx, fmt.Sprintf(endpointSKUByID, id), &sku); err != nil {
+		return nil, err
 	}
+	return &sku, nil
+}
 
-	// Convert results to SKUs
-	skus, err := it.client.ToSku(pag.Results)
+// CreateSKU creates a new SKU. The ID of sku is ignored by the server.
+func (srv *SkuService) CreateSKU(ctx context.Context, sku Sku) (*Sku, error) {
+	req, err := srv.client.httpClient.NewRequest(methodPOST, endpointSKU, sku)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse SKU page at offset %d:%w", it.offset, err)
+		return nil, fmt.Errorf("failed to NewRequest(%s, %s, %v):%w", methodPOST, endpointSKU, sku, err)
+	}
+
+	var created Sku
+	if _, err = srv.client.httpClient.Do(ctx, req, &created); err != nil {
+		return nil, fmt.Errorf("failed to Do with Request(%s, %s, %v):%w", methodPOST, endpointSKU, sku, err)
 	}
 
-	// Check if we have more pages
-	it.hasMore = pag.Next != nil
+	return &created, nil
+}
 
-	// Update iterator state using next page offset when available
-	if pag.Next != nil {
-		nextOffset, ok := parseNextOffset(*pag.Next)
-		if ok {
-			it.offset = nextOffset
-		} else {
-			it.offset += len(skus)
-		}
-	} else {
-		it.offset += len(skus)
+// UpdateSKU replaces SKU identified by sku.ID (PUT semantics).
+// Nil pointer fields are omitted from the payload and fall back to server defaults.
+func (srv *SkuService) UpdateSKU(ctx context.Context, sku Sku) (*Sku, error) {
+	if sku.ID == 0 {
+		return nil, fmt.Errorf("failed to UpdateSKU: empty id")
+	}
+	path := fmt.Sprintf(endpointSKUByID, sku.ID)
+	req, err := srv.client.httpClient.NewRequest(methodPUT, path, sku)
+	if err != nil {
+		return nil, fmt.Errorf("failed to NewRequest(%s, %s, %v):%w", methodPUT, path, sku, err)
 	}
 
-	return skus, nil
+	var updated Sku
+	if _, err = srv.client.httpClient.Do(ctx, req, &updated); err != nil {
+		return nil, fmt.Errorf("failed to Do with Request(%s, %s, %v):%w", methodPUT, path, sku, err)
+	}
+
+	return &updated, nil
 }
 
-func parseNextOffset(nextURL string) (int, bool) {
-	parsed, err := url.Parse(nextURL)
+// PatchSKU partially updates SKU for the given id (PATCH semantics).
+// Only non-nil fields of patch are sent.
+func (srv *SkuService) PatchSKU(ctx context.Context, id int, patch SkuPatch) (*Sku, error) {
+	path := fmt.Sprintf(endpointSKUByID, id)
+	req, err := srv.client.httpClient.NewRequest(methodPATCH, path, patch)
 	if err != nil {
-		return 0, false
+		return nil, fmt.Errorf("failed to NewRequest(%s, %s, %v):%w", methodPATCH, path, patch.Fields(), err)
 	}
-	offsetParam := parsed.Query().Get("offset")
-	if offsetParam == "" {
-		return 0, false
+
+	var updated Sku
+	if _, err = srv.client.httpClient.Do(ctx, req, &updated); err != nil {
+		return nil, fmt.Errorf("failed to Do with Request(%s, %s, %v):%w", methodPATCH, path, patch.Fields(), err)
 	}
-	value, err := strconv.Atoi(offsetParam)
+
+	return &updated, nil
+}
+
+// DeleteSKU deletes SKU for the given id
+func (srv *SkuService) DeleteSKU(ctx context.Context, id int) error {
+	path := fmt.Sprintf(endpointSKUByID, id)
+	req, err := srv.client.httpClient.NewRequest(methodDELETE, path, nil)
 	if err != nil {
-		return 0, false
+		return fmt.Errorf("failed to NewRequest(%s, %s):%w", methodDELETE, path, err)
+	}
+
+	if _, err = srv.client.httpClient.Do(ctx, req, nil); err != nil {
+		return fmt.Errorf("failed to Do with Request(%s, %s):%w", methodDELETE, path, err)
 	}
-	return value, true
+
+	return nil
 }
 
-// GetAllSKU fetches all SKUs using automatic pagination.
-// pageSize controls how many items are fetched per page (default: 100).
-func (srv *SkuService) GetAllSKU(ctx context.Context, pageSize int) ([]Sku, error) {
-	iterator := srv.IterateSKU(ctx, pageSize)
-	var allSKUs []Sku
-
-	for {
-		page, err := iterator.Next()
-		if err != nil {
-			return nil, err
+// SKU upsert operations
+const (
+	SkuUpsertCreate = "create"
+	SkuUpsertUpdate = "update"
+	SkuUpsertDelete = "delete"
+)
+
+// SkuUpsertOptions configures UpsertSKUs.
+type SkuUpsertOptions struct {
+	DryRun        bool      // only plan changes, do not apply them
+	DeleteMissing bool      // delete current SKUs whose CID is absent from the desired catalog
+	PageSize      int       // page size for fetching the current catalog (default: DefaultPageSize)
+	Query         *SkuQuery // optional filter limiting the current catalog, e.g. to one brand
+}
+
+// SkuUpsertChange describes a single planned or applied change.
+type SkuUpsertChange struct {
+	Op     string   `json:"op"`               // SkuUpsertCreate, SkuUpsertUpdate or SkuUpsertDelete
+	CID    string   `json:"cid"`              // client-specific SKU ID used for matching
+	ID     int      `json:"id,omitempty"`     // IC SKU ID, zero for planned creations
+	Fields []string `json:"fields,omitempty"` // changed fields for updates
+	Error  string   `json:"error,omitempty"`  // error of applying the change
+}
+
+// SkuUpsertReport represents the result of UpsertSKUs.
+type SkuUpsertReport struct {
+	DryRun    bool              `json:"dry_run"`
+	Changes   []SkuUpsertChange `json:"changes"`
+	Unchanged int               `json:"unchanged"`
+	Failed    int               `json:"failed"`
+}
+
+// String returns a short human readable summary of the report.
+func (r *SkuUpsertReport) String() string {
+	counts := map[string]int{}
+	for _, c := range r.Changes {
+		counts[c.Op]++
+	}
+	mode := "applied"
+	if r.DryRun {
+		mode = "dry-run"
+	}
+	return fmt.Sprintf("%s: %d to create, %d to update, %d to delete, %d unchanged, %d failed",
+		mode, counts[SkuUpsertCreate], counts[SkuUpsertUpdate], counts[SkuUpsertDelete], r.Unchanged, r.Failed)
+}
+
+// UpsertSKUs diffs the desired catalog against the current one, matching SKUs by CID,
+// and applies the minimal set of create/patch/delete calls.
+// With DryRun the returned report lists planned changes without calling the API.
+// Failed changes are recorded in the report; a non-nil error is returned if any failed.
+// The desired catalog is validated before the first change, and when ctx is done
+// mid-run the partial report is returned with the error.
+func (srv *SkuService) UpsertSKUs(ctx context.Context, desired []Sku, opts *SkuUpsertOptions) (*SkuUpsertReport, error) {
+	var options SkuUpsertOptions
+	if opts != nil {
+		options = *opts
+	}
+
+	seen := make(map[string]bool, len(desired))
+	for _, want := range desired {
+		if want.CID == "" {
+			return nil, fmt.Errorf("failed to UpsertSKUs: desired SKU %q has empty cid", want.Name)
 		}
-		if page == nil {
-			break
+		if seen[want.CID] {
+			return nil, fmt.Errorf("failed to UpsertSKUs: duplicate cid %q in desired catalog", want.CID)
 		}
-		allSKUs = append(allSKUs, page...)
+		seen[want.CID] = true
 	}
 
-	return allSKUs, nil
+	current, err := srv.GetAllSKU(ctx, options.PageSize, options.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to UpsertSKUs:%w", err)
+	}
+
+	byCID := make(map[string]Sku, len(current))
+	for _, sku := range current {
+		if sku.CID == "" {
+			continue
+		}
+		if prev, ok := byCID[sku.CID]; ok {
+			return nil, fmt.Errorf("failed to UpsertSKUs: duplicate cid %q in current catalog (ids %d and %d)", sku.CID, prev.ID, sku.ID)
+		}
+		byCID[sku.CID] = sku
+	}
+
+	report := &SkuUpsertReport{DryRun: options.DryRun}
+	for _, want := range desired {
+		if err := ctx.Err(); err != nil {
+			return report, fmt.Errorf("failed to UpsertSKUs:%w", err)
+		}
+
+		have, ok := byCID[want.CID]
+		if !ok {
+			change := SkuUpsertChange{Op: SkuUpsertCreate, CID: want.CID}
+			if !options.DryRun {
+				created, err := srv.CreateSKU(ctx, want)
+				if err != nil {
+					change.Error = err.Error()
+				} else {
+					change.ID = created.ID
+				}
+			}
+			report.add(change)
+			continue
+		}
+
+		patch := DiffSku(have, want)
+		if patch.IsEmpty() {
+			report.Unchanged++
+			continue
+		}
+		change := SkuUpsertChange{Op: SkuUpsertUpdate, CID: want.CID, ID: have.ID, Fields: patch.Fields()}
+		if !options.DryRun {
+			if _, err := srv.PatchSKU(ctx, have.ID, patch); err != nil {
+				change.Error = err.Error()
+			}
+		}
+		report.add(change)
+	}
+
+	if options.DeleteMissing {
+		for _, have := range current {
+			if have.CID == "" || seen[have.CID] {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return report, fmt.Errorf("failed to UpsertSKUs:%w", err)
+			}
+			change := SkuUpsertChange{Op: SkuUpsertDelete, CID: have.CID, ID: have.ID}
+			if !options.DryRun {
+				if err := srv.DeleteSKU(ctx, have.ID); err != nil {
+					change.Error = err.Error()
+				}
+			}
+			report.add(change)
+		}
+	}
+
+	if report.Failed > 0 {
+		return report, fmt.Errorf("failed to UpsertSKUs: %d of %d changes failed", report.Failed, len(report.Changes))
+	}
+	return report, nil
+}
+
+func (r *SkuUpsertReport) add(change SkuUpsertChange) {
+	if change.Error != "" {
+		r.Failed++
+	}
+	r.Changes = append(r.Changes, change)
 }
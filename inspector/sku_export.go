@@ -0,0 +1,481 @@
+package inspector
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// SKU export formats
+const (
+	SkuFormatCSV      = "csv"      // comma separated values with a header row
+	SkuFormatJSONL    = "jsonl"    // one JSON object per line
+	SkuFormatColumnar = "columnar" // JSON header line followed by one column-oriented row group per page
+
+	skuColumnarMagic   = "ic-sku-columnar"
+	skuColumnarVersion = 1
+)
+
+// DefaultSkuColumns lists every exportable SKU column in default order.
+var DefaultSkuColumns = []string{
+	"id", "cid", "ean13", "image", "name", "brand", "category", "manufacturer",
+	"size_x_mm", "size_y_mm", "size_z_mm",
+}
+
+// SkuExportOptions configures ExportSKU.
+type SkuExportOptions struct {
+	Format    string    // SkuFormatCSV (default), SkuFormatJSONL or SkuFormatColumnar
+	Columns   []string  // CSV and columnar columns (default: DefaultSkuColumns)
+	NullValue string    // CSV cell value for nil fields (default: empty string)
+	PageSize  int       // items per page (default: DefaultPageSize)
+	Query     *SkuQuery // optional server-side filter
+}
+
+// SkuImportOptions configures ImportSKU.
+type SkuImportOptions struct {
+	Format    string // SkuFormatCSV (default), SkuFormatJSONL or SkuFormatColumnar
+	NullValue string // CSV cell value read as nil (default: empty string)
+}
+
+// skuColumnKind defines how a column value is parsed.
+type skuColumnKind int
+
+const (
+	skuColumnInt skuColumnKind = iota
+	skuColumnFloat
+	skuColumnString
+)
+
+// skuColumn describes one exportable SKU field.
+type skuColumn struct {
+	kind     skuColumnKind
+	nullable bool
+	get      func(s *Sku) any // nil for nil fields, otherwise int, float64 or string
+	set      func(s *Sku, v any)
+}
+
+var skuColumns = map[string]skuColumn{
+	"id": {kind: skuColumnInt,
+		get: func(s *Sku) any { return s.ID },
+		set: func(s *Sku, v any) { s.ID = v.(int) }},
+	"cid": {kind: skuColumnString,
+		get: func(s *Sku) any { return s.CID },
+		set: func(s *Sku, v any) { s.CID = v.(string) }},
+	"ean13": {kind: skuColumnString, nullable: true,
+		get: func(s *Sku) any { return derefOrNil(s.EAN13) },
+		set: func(s *Sku, v any) { s.EAN13 = ptrOrNil[string](v) }},
+	"image": {kind: skuColumnInt,
+		get: func(s *Sku) any { return s.Image },
+		set: func(s *Sku, v any) { s.Image = v.(int) }},
+	"name": {kind: skuColumnString,
+		get: func(s *Sku) any { return s.Name },
+		set: func(s *Sku, v any) { s.Name = v.(string) }},
+	"brand": {kind: skuColumnInt, nullable: true,
+		get: func(s *Sku) any { return derefOrNil(s.Brand) },
+		set: func(s *Sku, v any) { s.Brand = ptrOrNil[int](v) }},
+	"category": {kind: skuColumnInt, nullable: true,
+		get: func(s *Sku) any { return derefOrNil(s.Category) },
+		set: func(s *Sku, v any) { s.Category = ptrOrNil[int](v) }},
+	"manufacturer": {kind: skuColumnInt, nullable: true,
+		get: func(s *Sku) any { return derefOrNil(s.Manufacturer) },
+		set: func(s *Sku, v any) { s.Manufacturer = ptrOrNil[int](v) }},
+	"size_x_mm": {kind: skuColumnFloat, nullable: true,
+		get: func(s *Sku) any { return derefOrNil(s.SizeXMM) },
+		set: func(s *Sku, v any) { s.SizeXMM = ptrOrNil[float64](v) }},
+	"size_y_mm": {kind: skuColumnFloat, nullable: true,
+		get: func(s *Sku) any { return derefOrNil(s.SizeYMM) },
+		set: func(s *Sku, v any) { s.SizeYMM = ptrOrNil[float64](v) }},
+	"size_z_mm": {kind: skuColumnFloat, nullable: true,
+		get: func(s *Sku) any { return derefOrNil(s.SizeZMM) },
+		set: func(s *Sku, v any) { s.SizeZMM = ptrOrNil[float64](v) }},
+}
+
+func derefOrNil[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func ptrOrNil[T any](v any) *T {
+	if v == nil {
+		return nil
+	}
+	t := v.(T)
+	return &t
+}
+
+func lookupSkuColumns(names []string) ([]skuColumn, error) {
+	cols := make([]skuColumn, len(names))
+	for i, name := range names {
+		col, ok := skuColumns[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown SKU column %q", name)
+		}
+		cols[i] = col
+	}
+	return cols, nil
+}
+
+// formatCell converts a column value to CSV text.
+func (c skuColumn) formatCell(v any, null string) string {
+	switch v := v.(type) {
+	case nil:
+		return null
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return v.(string)
+	}
+}
+
+// parseCell converts CSV text to a column value.
+func (c skuColumn) parseCell(cell, null string) (any, error) {
+	if c.nullable && cell == null {
+		return nil, nil
+	}
+	switch c.kind {
+	case skuColumnInt:
+		return strconv.Atoi(cell)
+	case skuColumnFloat:
+		return strconv.ParseFloat(cell, 64)
+	default:
+		return cell, nil
+	}
+}
+
+// parseJSON converts a columnar JSON value to a column value.
+func (c skuColumn) parseJSON(raw json.RawMessage) (any, error) {
+	if string(raw) == "null" {
+		if !c.nullable {
+			return nil, fmt.Errorf("unexpected null")
+		}
+		return nil, nil
+	}
+	var err error
+	switch c.kind {
+	case skuColumnInt:
+		var v int
+		err = json.Unmarshal(raw, &v)
+		return v, err
+	case skuColumnFloat:
+		var v float64
+		err = json.Unmarshal(raw, &v)
+		return v, err
+	default:
+		var v string
+		err = json.Unmarshal(raw, &v)
+		return v, err
+	}
+}
+
+// skuPageWriter writes SKU pages in one export format.
+type skuPageWriter interface {
+	writeHeader() error
+	writePage(skus []Sku) error
+	flush() error
+}
+
+func newSkuPageWriter(w io.Writer, opts SkuExportOptions) (skuPageWriter, error) {
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = DefaultSkuColumns
+	}
+	cols, err := lookupSkuColumns(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	switch opts.Format {
+	case "", SkuFormatCSV:
+		return &skuCSVWriter{w: csv.NewWriter(w), names: columns, cols: cols, null: opts.NullValue}, nil
+	case SkuFormatJSONL:
+		bw := bufio.NewWriter(w)
+		return &skuJSONLWriter{w: bw, enc: json.NewEncoder(bw)}, nil
+	case SkuFormatColumnar:
+		bw := bufio.NewWriter(w)
+		return &skuColumnarWriter{w: bw, enc: json.NewEncoder(bw), names: columns, cols: cols}, nil
+	default:
+		return nil, fmt.Errorf("unknown SKU export format %q", opts.Format)
+	}
+}
+
+type skuCSVWriter struct {
+	w     *csv.Writer
+	names []string
+	cols  []skuColumn
+	null  string
+}
+
+func (cw *skuCSVWriter) writeHeader() error {
+	return cw.w.Write(cw.names)
+}
+
+func (cw *skuCSVWriter) writePage(skus []Sku) error {
+	record := make([]string, len(cw.cols))
+	for i := range skus {
+		for j, col := range cw.cols {
+			record[j] = col.formatCell(col.get(&skus[i]), cw.null)
+		}
+		if err := cw.w.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *skuCSVWriter) flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+type skuJSONLWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func (jw *skuJSONLWriter) writeHeader() error {
+	return nil
+}
+
+func (jw *skuJSONLWriter) writePage(skus []Sku) error {
+	for _, sku := range skus {
+		if err := jw.enc.Encode(sku); err != nil {
+			return err
+		}
+	}
+	return jw.w.Flush()
+}
+
+func (jw *skuJSONLWriter) flush() error {
+	return jw.w.Flush()
+}
+
+// skuColumnarHeader is the first line of the columnar format.
+type skuColumnarHeader struct {
+	Format  string   `json:"format"`
+	Version int      `json:"version"`
+	Columns []string `json:"columns"`
+}
+
+// skuColumnarRowGroup holds the values of one page, column by column.
+type skuColumnarRowGroup struct {
+	Rows    int                          `json:"rows"`
+	Columns map[string][]json.RawMessage `json:"columns"`
+}
+
+type skuColumnarWriter struct {
+	w     *bufio.Writer
+	enc   *json.Encoder
+	names []string
+	cols  []skuColumn
+}
+
+func (cw *skuColumnarWriter) writeHeader() error {
+	return cw.enc.Encode(skuColumnarHeader{Format: skuColumnarMagic, Version: skuColumnarVersion, Columns: cw.names})
+}
+
+func (cw *skuColumnarWriter) writePage(skus []Sku) error {
+	if len(skus) == 0 {
+		return nil
+	}
+	group := skuColumnarRowGroup{Rows: len(skus), Columns: make(map[string][]json.RawMessage, len(cw.cols))}
+	for j, col := range cw.cols {
+		values := make([]json.RawMessage, len(skus))
+		for i := range skus {
+			b, err := json.Marshal(col.get(&skus[i]))
+			if err != nil {
+				return err
+			}
+			values[i] = b
+		}
+		group.Columns[cw.names[j]] = values
+	}
+	if err := cw.enc.Encode(group); err != nil {
+		return err
+	}
+	return cw.w.Flush()
+}
+
+func (cw *skuColumnarWriter) flush() error {
+	return cw.w.Flush()
+}
+
+// ExportSKU streams the SKU catalog page by page to w.
+// Memory usage is bounded by one page regardless of catalog size.
+// Returns the number of exported SKUs.
+func (srv *SkuService) ExportSKU(ctx context.Context, w io.Writer, opts *SkuExportOptions) (int, error) {
+	var options SkuExportOptions
+	if opts != nil {
+		options = *opts
+	}
+
+	pw, err := newSkuPageWriter(w, options)
+	if err != nil {
+		return 0, fmt.Errorf("failed to ExportSKU:%w", err)
+	}
+	if err := pw.writeHeader(); err != nil {
+		return 0, fmt.Errorf("failed to ExportSKU header:%w", err)
+	}
+
+	exported := 0
+	it := srv.IterateSKU(ctx, options.PageSize, options.Query)
+	for {
+		page, err := it.Next()
+		if err != nil {
+			return exported, fmt.Errorf("failed to ExportSKU:%w", err)
+		}
+		if page == nil {
+			break
+		}
+		if err := pw.writePage(page); err != nil {
+			return exported, fmt.Errorf("failed to ExportSKU page:%w", err)
+		}
+		exported += len(page)
+	}
+
+	if err := pw.flush(); err != nil {
+		return exported, fmt.Errorf("failed to ExportSKU:%w", err)
+	}
+	return exported, nil
+}
+
+// ImportSKU reads SKUs written by ExportSKU.
+func ImportSKU(r io.Reader, opts *SkuImportOptions) ([]Sku, error) {
+	var options SkuImportOptions
+	if opts != nil {
+		options = *opts
+	}
+
+	var (
+		skus []Sku
+		err  error
+	)
+	switch options.Format {
+	case "", SkuFormatCSV:
+		skus, err = importSkuCSV(r, options.NullValue)
+	case SkuFormatJSONL:
+		skus, err = importSkuJSONL(r)
+	case SkuFormatColumnar:
+		skus, err = importSkuColumnar(r)
+	default:
+		err = fmt.Errorf("unknown SKU import format %q", options.Format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to ImportSKU:%w", err)
+	}
+	return skus, nil
+}
+
+func importSkuCSV(r io.Reader, null string) ([]Sku, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	cols, err := lookupSkuColumns(header)
+	if err != nil {
+		return nil, err
+	}
+	cr.FieldsPerRecord = len(header)
+
+	var skus []Sku
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return skus, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var sku Sku
+		for i, col := range cols {
+			v, err := col.parseCell(record[i], null)
+			if err != nil {
+				return nil, fmt.Errorf("line %d, column %q: %w", line, header[i], err)
+			}
+			if v != nil {
+				col.set(&sku, v)
+			}
+		}
+		skus = append(skus, sku)
+	}
+}
+
+func importSkuJSONL(r io.Reader) ([]Sku, error) {
+	dec := json.NewDecoder(r)
+	var skus []Sku
+	for {
+		var sku Sku
+		if err := dec.Decode(&sku); err != nil {
+			if errors.Is(err, io.EOF) {
+				return skus, nil
+			}
+			return nil, err
+		}
+		skus = append(skus, sku)
+	}
+}
+
+func importSkuColumnar(r io.Reader) ([]Sku, error) {
+	dec := json.NewDecoder(r)
+	var header skuColumnarHeader
+	if err := dec.Decode(&header); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if header.Format != skuColumnarMagic || header.Version != skuColumnarVersion {
+		return nil, fmt.Errorf("unsupported columnar format %q version %d", header.Format, header.Version)
+	}
+	cols, err := lookupSkuColumns(header.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	var skus []Sku
+	for group := 1; ; group++ {
+		var rg skuColumnarRowGroup
+		if err := dec.Decode(&rg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return skus, nil
+			}
+			return nil, err
+		}
+		// rows comes from the input, check it before allocating the page
+		if rg.Rows < 0 || (len(cols) == 0 && rg.Rows > 0) {
+			return nil, fmt.Errorf("row group %d: invalid row count %d for %d columns", group, rg.Rows, len(cols))
+		}
+		for _, name := range header.Columns {
+			if n := len(rg.Columns[name]); n != rg.Rows {
+				return nil, fmt.Errorf("row group %d, column %q: %d values for %d rows", group, name, n, rg.Rows)
+			}
+		}
+		page := make([]Sku, rg.Rows)
+		for i, col := range cols {
+			name := header.Columns[i]
+			for row, raw := range rg.Columns[name] {
+				v, err := col.parseJSON(raw)
+				if err != nil {
+					return nil, fmt.Errorf("row group %d, column %q, row %d: %w", group, name, row, err)
+				}
+				if v != nil {
+					col.set(&page[row], v)
+				}
+			}
+		}
+		skus = append(skus, page...)
+	}
+}
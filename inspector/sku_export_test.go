@@ -0,0 +1,151 @@
+package inspector
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newExportServer(t *testing.T) *httptest.Server {
+	var serverURL string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/"+endpointSKU, r.URL.Path)
+		if r.URL.Query().Get("offset") == "0" {
+			next := fmt.Sprintf("%s/sku/?limit=2&offset=2", serverURL)
+			writePage(w, 3, &next, testPaginationResults)
+			return
+		}
+		writePage(w, 3, nil, shortPageResults)
+	}))
+	serverURL = ts.URL
+	return ts
+}
+
+func TestSkuService_ExportSKU(t *testing.T) {
+	ts := newExportServer(t)
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+	assert.NoError(t, err)
+
+	want, err := client.Sku.GetAllSKU(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(want))
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := client.Sku.ExportSKU(context.Background(), &buf, &SkuExportOptions{PageSize: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Equal(t, 4, len(lines))
+		assert.Equal(t, "id,cid,ean13,image,name,brand,category,manufacturer,size_x_mm,size_y_mm,size_z_mm", lines[0])
+		assert.Equal(t, "26,4601501027624,,3166335,\"Heineken банка 0,5 л\",25,7,,,,", lines[1])
+		assert.Equal(t, "10,SKU010,1234567890999,1010,Short Page Product,3,3,3,110,210,55", lines[3])
+
+		got, err := ImportSKU(&buf, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("csv columns and null value", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := client.Sku.ExportSKU(context.Background(), &buf, &SkuExportOptions{
+			PageSize:  2,
+			Columns:   []string{"id", "ean13", "size_x_mm"},
+			NullValue: "NULL",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "id,ean13,size_x_mm\n26,NULL,NULL\n12423,NULL,NULL\n10,1234567890999,110\n", buf.String())
+
+		got, err := ImportSKU(&buf, &SkuImportOptions{NullValue: "NULL"})
+		assert.NoError(t, err)
+		assert.Nil(t, got[0].EAN13)
+		assert.Equal(t, "1234567890999", *got[2].EAN13)
+		assert.Equal(t, 110.0, *got[2].SizeXMM)
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := client.Sku.ExportSKU(context.Background(), &buf, &SkuExportOptions{PageSize: 2, Format: SkuFormatJSONL})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+		assert.Equal(t, 3, strings.Count(buf.String(), "\n"))
+
+		got, err := ImportSKU(&buf, &SkuImportOptions{Format: SkuFormatJSONL})
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("columnar", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := client.Sku.ExportSKU(context.Background(), &buf, &SkuExportOptions{PageSize: 2, Format: SkuFormatColumnar})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Equal(t, 3, len(lines)) // header + one row group per page
+		assert.Contains(t, lines[0], `"format":"ic-sku-columnar"`)
+		assert.Contains(t, lines[1], `"rows":2`)
+		assert.Contains(t, lines[1], `"ean13":[null,null]`)
+
+		got, err := ImportSKU(&buf, &SkuImportOptions{Format: SkuFormatColumnar})
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := client.Sku.ExportSKU(context.Background(), &bytes.Buffer{}, &SkuExportOptions{Format: "parquet"})
+		assert.Error(t, err)
+
+		_, err = client.Sku.ExportSKU(context.Background(), &bytes.Buffer{}, &SkuExportOptions{Columns: []string{"price"}})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown SKU column "price"`)
+	})
+}
+
+func TestImportSKU_Errors(t *testing.T) {
+	t.Run("bad number", func(t *testing.T) {
+		_, err := ImportSKU(strings.NewReader("id,name\nx,Cola\n"), nil)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `line 2, column "id"`)
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		got, err := ImportSKU(strings.NewReader(""), nil)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+
+	t.Run("columnar version", func(t *testing.T) {
+		_, err := ImportSKU(strings.NewReader(`{"format":"ic-sku-columnar","version":2,"columns":["id"]}`), &SkuImportOptions{Format: SkuFormatColumnar})
+		assert.Error(t, err)
+	})
+
+	t.Run("columnar invalid row count", func(t *testing.T) {
+		for _, rg := range []string{
+			`{"rows":-1,"columns":{"id":[]}}`,
+			`{"rows":1000000000000,"columns":{"id":[1]}}`,
+		} {
+			_, err := ImportSKU(strings.NewReader(`{"format":"ic-sku-columnar","version":1,"columns":["id"]}`+"\n"+rg), &SkuImportOptions{Format: SkuFormatColumnar})
+			assert.Error(t, err, rg)
+		}
+		_, err := ImportSKU(strings.NewReader(`{"format":"ic-sku-columnar","version":1,"columns":[]}
+{"rows":1000000000000,"columns":{}}`), &SkuImportOptions{Format: SkuFormatColumnar})
+		assert.Error(t, err)
+	})
+
+	t.Run("columnar null in non-nullable column", func(t *testing.T) {
+		in := `{"format":"ic-sku-columnar","version":1,"columns":["id"]}
+{"rows":1,"columns":{"id":[null]}}`
+		_, err := ImportSKU(strings.NewReader(in), &SkuImportOptions{Format: SkuFormatColumnar})
+		assert.Error(t, err)
+	})
+}
@@ -1,12 +1,18 @@
 package inspector
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -444,3 +450,549 @@ func TestSkuService_GetAllSKU(t *testing.T) {
 		assert.Equal(t, 0, len(allSKUs))
 	})
 }
+
+func TestSkuQuery_Values(t *testing.T) {
+	t.Run("nil query", func(t *testing.T) {
+		var q *SkuQuery
+		assert.Equal(t, "", q.Values().Encode())
+	})
+
+	t.Run("all fields", func(t *testing.T) {
+		brand, category, manufacturer := 1, 2, 3
+		since := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		q := &SkuQuery{
+			Search:        "cola & lime",
+			EAN13:         "4601501027624",
+			CID:           "SKU001",
+			Brand:         &brand,
+			Category:      &category,
+			Manufacturer:  &manufacturer,
+			Ordering:      "-id",
+			ModifiedSince: &since,
+		}
+
+		v := q.Values()
+		assert.Equal(t, "cola & lime", v.Get("search"))
+		assert.Equal(t, "4601501027624", v.Get("ean13"))
+		assert.Equal(t, "SKU001", v.Get("cid"))
+		assert.Equal(t, "1", v.Get("brand"))
+		assert.Equal(t, "2", v.Get("category"))
+		assert.Equal(t, "3", v.Get("manufacturer"))
+		assert.Equal(t, "-id", v.Get("ordering"))
+		assert.Equal(t, "2024-03-01T12:00:00Z", v.Get("modified_since"))
+		assert.Contains(t, v.Encode(), "search=cola+%26+lime")
+	})
+}
+
+func TestSkuService_GetSKU_WithQuery(t *testing.T) {
+	brand := 25
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "10", query.Get("limit"))
+		assert.Equal(t, "20", query.Get("offset"))
+		assert.Equal(t, "heineken", query.Get("search"))
+		assert.Equal(t, "25", query.Get("brand"))
+		assert.Equal(t, "", query.Get("ean13"))
+
+		w.Header().Set(headerContentType, contentTypeJSON)
+		fmt.Fprintf(w, `{"count":2,"next":null,"previous":null,"results":%s}`, testPaginationResults)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+	assert.NoError(t, err)
+
+	pag, err := client.Sku.GetSKU(context.Background(), 20, 10, &SkuQuery{Search: "heineken", Brand: &brand})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pag.Count)
+}
+
+func TestSkuService_FindByEAN(t *testing.T) {
+	var serverURL string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "1234567890123", query.Get("ean13"))
+
+		var response string
+		var next *string
+		if query.Get("offset") == "0" {
+			response = `[{"id": 1, "cid": "SKU001", "ean13": "1234567890123", "name": "Product 1", "image": 1001}]`
+			n := fmt.Sprintf("%s/sku/?ean13=1234567890123&limit=1&offset=1", serverURL)
+			next = &n
+		} else {
+			response = `[{"id": 7, "cid": "SKU007", "ean13": "1234567890123", "name": "Product 7", "image": 1007}]`
+		}
+
+		w.Header().Set(headerContentType, contentTypeJSON)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"count":    2,
+			"next":     next,
+			"previous": nil,
+			"results":  json.RawMessage(response),
+		})
+	}))
+	defer ts.Close()
+	serverURL = ts.URL
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+	assert.NoError(t, err)
+
+	skus, err := client.Sku.FindByEAN(context.Background(), "1234567890123")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(skus))
+	assert.Equal(t, 7, skus[1].ID)
+
+	_, err = client.Sku.FindByEAN(context.Background(), "")
+	assert.Error(t, err)
+}
+
+func TestSkuService_FindByCID(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "SKU001", r.URL.Query().Get("cid"))
+		w.Header().Set(headerContentType, contentTypeJSON)
+		fmt.Fprint(w, `{"count":1,"next":null,"previous":null,"results":[{"id": 1, "cid": "SKU001", "name": "Product 1", "image": 1001}]}`)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+	assert.NoError(t, err)
+
+	skus, err := client.Sku.FindByCID(context.Background(), "SKU001")
+	assert.NoError(t, err)
+	assert.Equal(t, []Sku{{ID: 1, CID: "SKU001", Name: "Product 1", Image: 1001}}, skus)
+
+	_, err = client.Sku.FindByCID(context.Background(), "")
+	assert.Error(t, err)
+}
+
+func TestSkuService_GetSKUByID(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, methodGET, r.Method)
+		assert.Equal(t, "/sku/26/", r.URL.Path)
+		fmt.Fprint(w, `{"id": 26, "cid": "4601501027624", "image": 3166335, "name": "Heineken", "brand": 25}`)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+	assert.NoError(t, err)
+
+	sku, err := client.Sku.GetSKUByID(context.Background(), 26)
+	assert.NoError(t, err)
+	assert.Equal(t, 26, sku.ID)
+	assert.Equal(t, 25, *sku.Brand)
+}
+
+func TestSkuService_CreateSKU(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, methodPOST, r.Method)
+		assert.Equal(t, "/"+endpointSKU, r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"id":0,"cid":"SKU100","image":5,"name":"New","size_x_mm":80}`, string(body))
+		fmt.Fprint(w, `{"id": 100, "cid": "SKU100", "image": 5, "name": "New", "size_x_mm": 80}`)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+	assert.NoError(t, err)
+
+	width := 80.0
+	sku, err := client.Sku.CreateSKU(context.Background(), Sku{CID: "SKU100", Image: 5, Name: "New", SizeXMM: &width})
+	assert.NoError(t, err)
+	assert.Equal(t, 100, sku.ID)
+}
+
+func TestSkuService_UpdateSKU(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, methodPUT, r.Method)
+		assert.Equal(t, "/sku/7/", r.URL.Path)
+		fmt.Fprint(w, `{"id": 7, "cid": "SKU007", "image": 1, "name": "Renamed"}`)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+	assert.NoError(t, err)
+
+	sku, err := client.Sku.UpdateSKU(context.Background(), Sku{ID: 7, CID: "SKU007", Image: 1, Name: "Renamed"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Renamed", sku.Name)
+
+	_, err = client.Sku.UpdateSKU(context.Background(), Sku{CID: "SKU007"})
+	assert.Error(t, err)
+}
+
+func TestSkuService_PatchSKU(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, methodPATCH, r.Method)
+		assert.Equal(t, "/sku/7/", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"ean13":"1234567890123","size_z_mm":55.5}`, string(body))
+		fmt.Fprint(w, `{"id": 7, "cid": "SKU007", "image": 1, "name": "Product", "ean13": "1234567890123", "size_z_mm": 55.5}`)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+	assert.NoError(t, err)
+
+	ean, depth := "1234567890123", 55.5
+	sku, err := client.Sku.PatchSKU(context.Background(), 7, SkuPatch{EAN13: &ean, SizeZMM: &depth})
+	assert.NoError(t, err)
+	assert.Equal(t, ean, *sku.EAN13)
+}
+
+func TestSkuService_DeleteSKU(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, methodDELETE, r.Method)
+		assert.Equal(t, "/sku/7/", r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.Sku.DeleteSKU(context.Background(), 7))
+}
+
+func TestDiffSku(t *testing.T) {
+	brand, otherBrand := 1, 2
+	width := 100.0
+	current := Sku{ID: 1, CID: "A", Name: "Cola", Image: 10, Brand: &brand, SizeXMM: &width}
+
+	t.Run("no changes", func(t *testing.T) {
+		same := 1
+		sameWidth := 100.0
+		patch := DiffSku(current, Sku{CID: "A", Name: "Cola", Brand: &same, SizeXMM: &sameWidth})
+		assert.True(t, patch.IsEmpty())
+	})
+
+	t.Run("nil fields are not managed", func(t *testing.T) {
+		patch := DiffSku(current, Sku{CID: "A"})
+		assert.True(t, patch.IsEmpty())
+	})
+
+	t.Run("changed fields", func(t *testing.T) {
+		ean := "1234567890123"
+		patch := DiffSku(current, Sku{CID: "A", Name: "Cola Zero", Brand: &otherBrand, EAN13: &ean})
+		assert.Equal(t, []string{"ean13", "name", "brand"}, patch.Fields())
+		assert.Equal(t, "Cola Zero", *patch.Name)
+		assert.Equal(t, 2, *patch.Brand)
+	})
+}
+
+func TestSkuService_UpsertSKUs(t *testing.T) {
+	current := `[
+		{"id": 1, "cid": "A", "name": "Cola", "image": 10, "brand": 1},
+		{"id": 2, "cid": "B", "name": "Fanta", "image": 11},
+		{"id": 3, "cid": "C", "name": "Sprite", "image": 12}
+	]`
+
+	newServer := func(calls *[]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == methodGET {
+				w.Header().Set(headerContentType, contentTypeJSON)
+				fmt.Fprintf(w, `{"count":3,"next":null,"previous":null,"results":%s}`, current)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			*calls = append(*calls, fmt.Sprintf("%s %s %s", r.Method, r.URL.Path, body))
+			switch r.Method {
+			case methodPOST:
+				fmt.Fprint(w, `{"id": 4, "cid": "D", "name": "Water", "image": 13}`)
+			case methodPATCH:
+				fmt.Fprint(w, `{"id": 1, "cid": "A", "name": "Cola", "image": 10, "brand": 5}`)
+			case methodDELETE:
+				w.WriteHeader(http.StatusNoContent)
+			}
+		}))
+	}
+
+	brand := 5
+	desired := []Sku{
+		{CID: "A", Name: "Cola", Brand: &brand},
+		{CID: "B", Name: "Fanta"},
+		{CID: "D", Name: "Water", Image: 13},
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		var calls []string
+		ts := newServer(&calls)
+		defer ts.Close()
+
+		client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+		assert.NoError(t, err)
+
+		report, err := client.Sku.UpsertSKUs(context.Background(), desired, &SkuUpsertOptions{DryRun: true, DeleteMissing: true})
+		assert.NoError(t, err)
+		assert.Empty(t, calls)
+		assert.Equal(t, []SkuUpsertChange{
+			{Op: SkuUpsertUpdate, CID: "A", ID: 1, Fields: []string{"brand"}},
+			{Op: SkuUpsertCreate, CID: "D"},
+			{Op: SkuUpsertDelete, CID: "C", ID: 3},
+		}, report.Changes)
+		assert.Equal(t, 1, report.Unchanged)
+		assert.Equal(t, "dry-run: 1 to create, 1 to update, 1 to delete, 1 unchanged, 0 failed", report.String())
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		var calls []string
+		ts := newServer(&calls)
+		defer ts.Close()
+
+		client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+		assert.NoError(t, err)
+
+		report, err := client.Sku.UpsertSKUs(context.Background(), desired, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, report.Failed)
+		assert.Equal(t, 4, report.Changes[1].ID)
+		assert.Equal(t, []string{
+			"PATCH /sku/1/ {\"brand\":5}\n",
+			"POST /sku/ {\"id\":0,\"cid\":\"D\",\"image\":13,\"name\":\"Water\"}\n",
+		}, calls)
+	})
+
+	t.Run("duplicate desired cid", func(t *testing.T) {
+		var calls []string
+		ts := newServer(&calls)
+		defer ts.Close()
+
+		client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+		assert.NoError(t, err)
+
+		_, err = client.Sku.UpsertSKUs(context.Background(), []Sku{{CID: "A"}, {CID: "A"}}, nil)
+		assert.Error(t, err)
+		assert.Empty(t, calls)
+	})
+
+	t.Run("invalid desired sku after a change", func(t *testing.T) {
+		var calls []string
+		ts := newServer(&calls)
+		defer ts.Close()
+
+		client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+		assert.NoError(t, err)
+
+		report, err := client.Sku.UpsertSKUs(context.Background(), append(desired[:3:3], Sku{Name: "No CID"}), nil)
+		assert.Error(t, err)
+		assert.Nil(t, report)
+		assert.Empty(t, calls)
+	})
+
+	t.Run("canceled mid-run", func(t *testing.T) {
+		var calls []string
+		api := newServer(&calls)
+		defer api.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			api.Config.Handler.ServeHTTP(w, r)
+			if r.Method == methodPATCH {
+				cancel()
+			}
+		}))
+		defer ts.Close()
+
+		client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+		assert.NoError(t, err)
+
+		report, err := client.Sku.UpsertSKUs(ctx, desired, &SkuUpsertOptions{DeleteMissing: true})
+		assert.ErrorIs(t, err, context.Canceled)
+		if assert.NotNil(t, report) && assert.Len(t, report.Changes, 1) {
+			assert.Equal(t, SkuUpsertUpdate, report.Changes[0].Op)
+			assert.Equal(t, "A", report.Changes[0].CID)
+		}
+		assert.Len(t, calls, 1)
+	})
+}
+
+func TestSkuService_GetAllSKUParallel(t *testing.T) {
+	// catalogServer serves `total` SKUs with IDs 1..total; shift moves every
+	// page after the first by the given number of items to simulate inserts.
+	// A positive maxLimit caps the page size like a DRF max_limit.
+	var maxLimit int
+	catalogServer := func(total int, shift func(call int32) int) (*httptest.Server, *int32) {
+		var calls int32
+		var mu sync.Mutex
+		var serverURL string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			calls++
+			call := calls
+			mu.Unlock()
+
+			assert.Equal(t, "drinks", r.URL.Query().Get("search"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			if maxLimit > 0 {
+				limit = min(limit, maxLimit)
+			}
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			count := total
+			if offset > 0 {
+				s := shift(call)
+				offset -= s
+				count += s
+			}
+
+			var items []string
+			for id := offset + 1; id <= offset+limit && id <= total; id++ {
+				items = append(items, fmt.Sprintf(`{"id": %d, "cid": "SKU%03d", "name": "Product %d", "image": 1}`, id, id, id))
+			}
+			var next *string
+			if offset+limit < total {
+				n := fmt.Sprintf("%s/sku/?limit=%d&offset=%d", serverURL, limit, offset+limit)
+				next = &n
+			}
+			writePage(w, count, next, "["+strings.Join(items, ",")+"]")
+		}))
+		serverURL = ts.URL
+		return ts, &calls
+	}
+	query := &SkuQuery{Search: "drinks"}
+
+	t.Run("fetches pages concurrently in order", func(t *testing.T) {
+		ts, calls := catalogServer(23, func(int32) int { return 0 })
+		defer ts.Close()
+
+		client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+		assert.NoError(t, err)
+
+		skus, err := client.Sku.GetAllSKUParallel(context.Background(), &ParallelFetchOptions{PageSize: 5, Concurrency: 2}, query)
+		assert.NoError(t, err)
+		assert.Equal(t, 23, len(skus))
+		for i, sku := range skus {
+			assert.Equal(t, i+1, sku.ID)
+		}
+		assert.Equal(t, int32(5), *calls)
+	})
+
+	t.Run("server caps the page size", func(t *testing.T) {
+		maxLimit = 3
+		defer func() { maxLimit = 0 }()
+		ts, calls := catalogServer(23, func(int32) int { return 0 })
+		defer ts.Close()
+
+		client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+		assert.NoError(t, err)
+
+		skus, err := client.Sku.GetAllSKUParallel(context.Background(), &ParallelFetchOptions{PageSize: 5, Concurrency: 2, Strict: true}, query)
+		assert.NoError(t, err)
+		assert.Equal(t, 23, len(skus))
+		for i, sku := range skus {
+			assert.Equal(t, i+1, sku.ID)
+		}
+		assert.Equal(t, int32(8), *calls)
+	})
+
+	t.Run("retries when catalog changes", func(t *testing.T) {
+		// first attempt (calls 1-3) sees an insert, second attempt is stable
+		ts, calls := catalogServer(10, func(call int32) int {
+			if call <= 3 {
+				return 1
+			}
+			return 0
+		})
+		defer ts.Close()
+
+		client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+		assert.NoError(t, err)
+
+		var warnings []ConsistencyWarning
+		skus, err := client.Sku.GetAllSKUParallel(context.Background(), &ParallelFetchOptions{
+			PageSize:   4,
+			MaxRetries: 1,
+			OnWarning:  func(w ConsistencyWarning) { warnings = append(warnings, w) },
+		}, query)
+		assert.NoError(t, err)
+		assert.Equal(t, 10, len(skus))
+		assert.Empty(t, warnings)
+		assert.Equal(t, int32(6), *calls)
+	})
+
+	t.Run("reports warning and deduplicates", func(t *testing.T) {
+		ts, _ := catalogServer(10, func(int32) int { return 1 })
+		defer ts.Close()
+
+		client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+		assert.NoError(t, err)
+
+		var warnings []ConsistencyWarning
+		skus, err := client.Sku.GetAllSKUParallel(context.Background(), &ParallelFetchOptions{
+			PageSize:  4,
+			OnWarning: func(w ConsistencyWarning) { warnings = append(warnings, w) },
+		}, query)
+		assert.NoError(t, err)
+		assert.Equal(t, 10, len(skus))
+		assert.Equal(t, 1, len(warnings))
+		assert.Equal(t, []int{11, 11}, warnings[0].CountDrift)
+		assert.Equal(t, []int{4}, warnings[0].DuplicateIDs)
+		assert.Contains(t, warnings[0].String(), "expected 10 items, fetched 11")
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		ts, _ := catalogServer(10, func(int32) int { return 1 })
+		defer ts.Close()
+
+		client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+		assert.NoError(t, err)
+
+		_, err = client.Sku.GetAllSKUParallel(context.Background(), &ParallelFetchOptions{PageSize: 4, Strict: true}, query)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to fetch sku/ consistently")
+	})
+
+	t.Run("page error", func(t *testing.T) {
+		var serverURL string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("offset") != "0" {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			next := fmt.Sprintf("%s/sku/?limit=1&offset=1", serverURL)
+			writePage(w, 3, &next, `[{"id": 1}]`)
+		}))
+		defer ts.Close()
+		serverURL = ts.URL
+
+		client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key"})
+		assert.NoError(t, err)
+
+		_, err = client.Sku.GetAllSKUParallel(context.Background(), &ParallelFetchOptions{PageSize: 1})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "502")
+	})
+
+	t.Run("canceled mid-fetch", func(t *testing.T) {
+		ts, _ := catalogServer(20, func(int32) int { return 0 })
+		defer ts.Close()
+
+		// the second page is delivered in full before the caller cancels,
+		// so only the workers still waiting for a slot see ctx done
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			resp, err := http.DefaultTransport.RoundTrip(r)
+			if err != nil || r.URL.Query().Get("offset") == "0" {
+				return resp, err
+			}
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			cancel()
+			return resp, err
+		})
+
+		client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: "test-key", HTTPClient: &http.Client{Transport: transport}})
+		assert.NoError(t, err)
+
+		skus, err := client.Sku.GetAllSKUParallel(ctx, &ParallelFetchOptions{PageSize: 2, Concurrency: 1}, query)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, skus)
+	})
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
@@ -3,6 +3,8 @@ package inspector
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -16,6 +18,56 @@ type Visit struct {
 	Longitude   float64   `json:"longitude,omitempty"` // Location of the merchandiser at the time of the visit.
 }
 
+// VisitCreateRequest represents a payload of request create visit.
+// Zero fields are omitted and filled with server-side defaults.
+type VisitCreateRequest struct {
+	Shop        int        `json:"shop,omitempty"`         // Client-specific customer/outlet/shop
+	Agent       string     `json:"agent,omitempty"`        // Client-specific agent name/id/route
+	StartedDate *time.Time `json:"started_date,omitempty"` // Date and time of the visit start
+	Latitude    *float64   `json:"latitude,omitempty"`     // Location of the merchandiser
+	Longitude   *float64   `json:"longitude,omitempty"`    // Location of the merchandiser
+}
+
+// VisitUpdateRequest represents a payload of partial visit update.
+// Nil fields are left unchanged on the server.
+type VisitUpdateRequest struct {
+	Shop        *int       `json:"shop,omitempty"`
+	Agent       *string    `json:"agent,omitempty"`
+	StartedDate *time.Time `json:"started_date,omitempty"`
+	Latitude    *float64   `json:"latitude,omitempty"`
+	Longitude   *float64   `json:"longitude,omitempty"`
+}
+
+// VisitQuery holds server-side filters for visit listing.
+// Zero values are not sent to the API.
+type VisitQuery struct {
+	Shop          *int       // Shop ID
+	Agent         string     // exact agent name/id/route
+	StartedAfter  *time.Time // visits started at or after this time
+	StartedBefore *time.Time // visits started at or before this time
+}
+
+// Values encodes the query to url.Values.
+func (q *VisitQuery) Values() url.Values {
+	v := url.Values{}
+	if q == nil {
+		return v
+	}
+	if q.Shop != nil {
+		v.Set(queryParamShop, strconv.Itoa(*q.Shop))
+	}
+	if q.Agent != "" {
+		v.Set(queryParamAgent, q.Agent)
+	}
+	if q.StartedAfter != nil {
+		v.Set(queryParamStartedAfter, q.StartedAfter.UTC().Format(time.RFC3339))
+	}
+	if q.StartedBefore != nil {
+		v.Set(queryParamStartedBefore, q.StartedBefore.UTC().Format(time.RFC3339))
+	}
+	return v
+}
+
 // VisitService provides access to the Visit functions in the IC API.
 type VisitService struct {
 	client *Client
@@ -23,16 +75,92 @@ type VisitService struct {
 
 // AddVisit adds new IC visit with server-side defaults
 func (srv *VisitService) AddVisit(ctx context.Context) (*Visit, error) {
-	req, err := srv.client.httpClient.NewRequest(methodPOST, endpointVisits, struct{}{})
+	return srv.CreateVisit(ctx, VisitCreateRequest{})
+}
+
+// CreateVisit adds new IC visit
+func (srv *VisitService) CreateVisit(ctx context.Context, vr VisitCreateRequest) (*Visit, error) {
+	req, err := srv.client.httpClient.NewRequest(methodPOST, endpointVisits, vr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to NewRequest(%s, %s):%w", methodPOST, endpointVisits, err)
+		return nil, fmt.Errorf("failed to NewRequest(%s, %s, %v):%w", methodPOST, endpointVisits, vr, err)
 	}
 
 	resp := &Visit{}
 	_, err = srv.client.httpClient.Do(ctx, req, resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to Do with Request(%s, %s):%w", methodPOST, endpointVisits, err)
+		return nil, fmt.Errorf("failed to Do with Request(%s, %s, %v):%w", methodPOST, endpointVisits, vr, err)
+	}
+
+	return resp, nil
+}
+
+// GetVisit requests visit for the given id
+func (srv *VisitService) GetVisit(ctx context.Context, id int) (*Visit, error) {
+	var v Visit
+	if err := srv.client.getObject(ctx, fmt.Sprintf(endpointVisitByID, id), &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// UpdateVisit partially updates visit for the given id
+func (srv *VisitService) UpdateVisit(ctx context.Context, id int, vr VisitUpdateRequest) (*Visit, error) {
+	path := fmt.Sprintf(endpointVisitByID, id)
+	req, err := srv.client.httpClient.NewRequest(methodPATCH, path, vr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to NewRequest(%s, %s):%w", methodPATCH, path, err)
+	}
+
+	resp := &Visit{}
+	if _, err = srv.client.httpClient.Do(ctx, req, resp); err != nil {
+		return nil, fmt.Errorf("failed to Do with Request(%s, %s):%w", methodPATCH, path, err)
 	}
 
 	return resp, nil
 }
+
+// DeleteVisit deletes visit for the given id
+func (srv *VisitService) DeleteVisit(ctx context.Context, id int) error {
+	path := fmt.Sprintf(endpointVisitByID, id)
+	req, err := srv.client.httpClient.NewRequest(methodDELETE, path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to NewRequest(%s, %s):%w", methodDELETE, path, err)
+	}
+
+	if _, err = srv.client.httpClient.Do(ctx, req, nil); err != nil {
+		return fmt.Errorf("failed to Do with Request(%s, %s):%w", methodDELETE, path, err)
+	}
+
+	return nil
+}
+
+// VisitIterator provides paginated iteration over visits.
+type VisitIterator = Iterator[Visit]
+
+// IterateVisits returns an iterator over visits.
+// pageSize controls how many items are fetched per page (default: 100).
+// An optional VisitQuery is applied to every page.
+func (srv *VisitService) IterateVisits(ctx context.Context, pageSize int, query ...*VisitQuery) VisitIterator {
+	var q *VisitQuery
+	if len(query) > 0 {
+		q = query[0]
+	}
+	return NewPaginator[Visit](ctx, srv.client, endpointVisits, q.Values(), pageSize)
+}
+
+// ListVisits fetches all visits matching query using automatic pagination.
+func (srv *VisitService) ListVisits(ctx context.Context, query *VisitQuery) ([]Visit, error) {
+	return srv.IterateVisits(ctx, DefaultPageSize, query).Collect()
+}
+
+// GetVisitReports fetches all reports linked to the visit.
+func (srv *VisitService) GetVisitReports(ctx context.Context, visitID int) ([]Report, error) {
+	q := url.Values{queryParamVisit: {strconv.Itoa(visitID)}}
+	return NewPaginator[Report](ctx, srv.client, endpointReportList, q, DefaultPageSize).Collect()
+}
+
+// GetVisitRecognitions fetches all recognition requests linked to the visit.
+func (srv *VisitService) GetVisitRecognitions(ctx context.Context, visitID int) ([]RecognizeResponse, error) {
+	q := url.Values{queryParamVisit: {strconv.Itoa(visitID)}}
+	return NewPaginator[RecognizeResponse](ctx, srv.client, endpointRecognize, q, DefaultPageSize).Collect()
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -31,3 +32,115 @@ func TestVisitService_AddVisit(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 999, resp.ID)
 }
+
+func TestVisitService_CreateVisit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, methodPOST, r.Method)
+		assert.Equal(t, "/"+endpointVisits, r.URL.Path)
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"shop":42,"agent":"route-7","started_date":"2024-05-01T09:30:00Z","latitude":55.75,"longitude":0}`, string(body))
+
+		fmt.Fprint(w, `{"id":1000,"shop":42,"agent":"route-7","started_date":"2024-05-01T09:30:00Z","latitude":55.75}`)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: ""})
+	assert.NoError(t, err)
+
+	started := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	lat, lon := 55.75, 0.0
+	visit, err := client.Visit.CreateVisit(context.Background(), VisitCreateRequest{
+		Shop:        42,
+		Agent:       "route-7",
+		StartedDate: &started,
+		Latitude:    &lat,
+		Longitude:   &lon,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &Visit{ID: 1000, Shop: 42, Agent: "route-7", StartedDate: started, Latitude: 55.75}, visit)
+}
+
+func TestVisitService_GetUpdateDeleteVisit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/visits/5/", r.URL.Path)
+		switch r.Method {
+		case methodGET:
+			fmt.Fprint(w, `{"id":5,"shop":1,"agent":"a"}`)
+		case methodPATCH:
+			body, err := ioutil.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{"agent":"b"}`, string(body))
+			fmt.Fprint(w, `{"id":5,"shop":1,"agent":"b"}`)
+		case methodDELETE:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: ""})
+	assert.NoError(t, err)
+
+	visit, err := client.Visit.GetVisit(context.Background(), 5)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", visit.Agent)
+
+	agent := "b"
+	visit, err = client.Visit.UpdateVisit(context.Background(), 5, VisitUpdateRequest{Agent: &agent})
+	assert.NoError(t, err)
+	assert.Equal(t, "b", visit.Agent)
+
+	assert.NoError(t, client.Visit.DeleteVisit(context.Background(), 5))
+}
+
+func TestVisitService_ListVisits(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "42", q.Get("shop"))
+		assert.Equal(t, "route-7", q.Get("agent"))
+		assert.Equal(t, "2024-05-01T00:00:00Z", q.Get("started_date_after"))
+		assert.Equal(t, "2024-05-31T23:59:59Z", q.Get("started_date_before"))
+		writePage(w, 2, nil, `[{"id":1,"shop":42,"agent":"route-7"},{"id":2,"shop":42,"agent":"route-7"}]`)
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: ""})
+	assert.NoError(t, err)
+
+	shop := 42
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 5, 31, 23, 59, 59, 0, time.UTC)
+	visits, err := client.Visit.ListVisits(context.Background(), &VisitQuery{Shop: &shop, Agent: "route-7", StartedAfter: &from, StartedBefore: &to})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(visits))
+}
+
+func TestVisitService_GetVisitReportsAndRecognitions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "77", r.URL.Query().Get("visit"))
+		switch r.URL.Path {
+		case "/" + endpointReportList:
+			writePage(w, 2, nil, `[{"id":10,"status":"READY","report_type":"FACING_COUNT","visit":77},{"id":11,"status":"NOT_READY","report_type":"PRICE_TAGS","visit":77}]`)
+		case "/" + endpointRecognize:
+			writePage(w, 1, nil, `[{"id":3,"images":[1,2],"scene":"abc","reports":{"FACING_COUNT":10,"PRICE_TAGS":11}}]`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ClintConf{Instance: ts.URL, APIKey: ""})
+	assert.NoError(t, err)
+
+	reports, err := client.Visit.GetVisitReports(context.Background(), 77)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(reports))
+	assert.Equal(t, ReportStatusNOT_READY, reports[1].Status)
+
+	recognitions, err := client.Visit.GetVisitRecognitions(context.Background(), 77)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(recognitions))
+	assert.Equal(t, 11, recognitions[0].Reports[ReportTypePRICE_TAGS])
+}